@@ -1,15 +1,111 @@
 package main
 
 import (
-	
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/pufington-pixie/haver/pkg/brick"
+	controller "github.com/pufington-pixie/haver/pkg/controllers"
+	"github.com/pufington-pixie/haver/pkg/database"
+	"github.com/pufington-pixie/haver/pkg/importer"
+	"github.com/pufington-pixie/haver/pkg/imports"
 	"github.com/pufington-pixie/haver/pkg/routes"
+	"github.com/pufington-pixie/haver/pkg/store/sqlstore"
 )
 
+// shutdownTimeout bounds how long in-flight requests and imports get to
+// finish on shutdown.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
+	// Build the shared connection pool
+	cfg, err := database.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	log.Println("Connected to the database!")
+
+	s := sqlstore.New(db)
+
+	// Load the point list header aliases
+	aliases, err := importer.LoadAliases(os.Getenv("IMPORT_ALIASES"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the background import workers and resume what the previous
+	// run left queued
+	importService := imports.NewService(s, imports.Options{
+		Aliases:   aliases,
+		UploadDir: os.Getenv("UPLOAD_DIR"),
+		Workers:   envInt("IMPORT_WORKERS"),
+		QueueSize: envInt("IMPORT_QUEUE_SIZE"),
+	})
+	importService.Start()
+	if err := importService.Recover(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	// Load the Brick class mapping
+	brickMapping, err := brick.LoadMapping(os.Getenv("BRICK_MAPPING"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Set up routes
-	routers.SetRoutes()
-	
-	
-  }
+	c := controller.New(s, controller.Options{Imports: importService, Brick: brickMapping})
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: routers.NewRouter(c),
+	}
+
+	// Start the HTTP server
+	go func() {
+		log.Printf("Server listening on port %s", srv.Addr)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("HTTP shutdown:", err)
+	}
+	if err := importService.Shutdown(ctx); err != nil {
+		log.Println("Import shutdown:", err)
+	}
+}
+
+// envInt reads an integer setting, returning 0 when it is unset so the
+// package default applies.
+func envInt(key string) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("%s: %v", key, err)
+	}
+	return n
+}
@@ -16,6 +16,237 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/data/{id}": {
+            "get": {
+                "description": "Get the point list of a project from the database.\nAny datapoints column can be used as a filter parameter, for example System, EquipType, EquipID, PointType, BACnetObjectType or IncludedInProject; text is matched ignoring case.\nq searches PointName and Descriptor. sort orders by any column, prefixed with - for descending order.\nWithout limit every matching point is returned; with limit the response meta carries the total count and the cursor of the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Get the datapoints of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by system",
+                        "name": "System",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by equipment type",
+                        "name": "EquipType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by equipment",
+                        "name": "EquipID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by point type",
+                        "name": "PointType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by BACnet object type",
+                        "name": "BACnetObjectType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by inclusion in the project",
+                        "name": "IncludedInProject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search PointName and Descriptor",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column to sort by, e.g. PointName or -PointName",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DataPoint"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/models.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/imports/{importId}": {
+            "get": {
+                "description": "Get the record of a point list upload, including the progress of a background import",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Get an import by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Import ID",
+                        "name": "importId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Import"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/imports/{importId}/file": {
+            "get": {
+                "description": "Download the stored point list of an import under its original file name",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Download the file of an import",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Import ID",
+                        "name": "importId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/projects": {
             "get": {
                 "description": "Get the list of projects from the database",
@@ -71,10 +302,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Project"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": ""
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "500": {
@@ -169,6 +406,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -217,21 +460,112 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/upload/{id}": {
-            "post": {
-                "description": "Uploads a CSV file, parses its content, and saves the data to the database",
-                "consumes": [
-                    "multipart/form-data"
+        "/api/projects/{id}/capacity": {
+            "get": {
+                "description": "Group the datapoints of a project by panel (DeviceSysName, or NodeIdentifier) and by trunk (BLNSysName and MSTPnetwork) and estimate the samples per day, buffered samples, trend memory and messages per second of each from the trend and COV columns.\nPanels and trunks over a threshold list what they exceed. The thresholds have defaults and can be set per request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trends"
                 ],
-                "summary": "Upload CSV file and save data to the database",
+                "summary": "Estimate the panel and trunk load of a project",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "CSV file to upload",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Trend samples a panel buffers",
+                        "name": "panelSamples",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trend memory of a panel in bytes",
+                        "name": "panelBytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Memory a buffered sample takes",
+                        "name": "bytesPerSample",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Changes of value a COV point reports an hour",
+                        "name": "covPerHour",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Messages per second a BLN trunk carries",
+                        "name": "blnMessages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Messages per second an MS/TP trunk carries",
+                        "name": "mstpMessages",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/capacity.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/conflicts": {
+            "get": {
+                "description": "Analyse every datapoint of a project and return the conflicting point definitions grouped by category:\npoints sharing an EquipID and PointName, points addressing the same BACnet object, BACnet devices sharing a device instance on one network, MS/TP devices sharing a MAC address on one trunk and field devices named like a panel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Get the conflicts of a project",
+                "parameters": [
                     {
                         "type": "integer",
                         "description": "Project ID",
@@ -242,107 +576,5122 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "CSV file uploaded and saved to the database successfully!",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/conflicts.Report"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
         },
-        "models.Project": {
-            "type": "object",
-            "properties": {
-                "branchId": {
-                    "description": "The branch ID associated with the project.\n\nrequired: true\nexample: 1",
-                    "type": "integer"
-                },
-                "date": {
-                    "description": "The date of the project.\n\nrequired: true\nformat: date\nexample: 2023-06-07",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "The unique identifier of the project.\n\nrequired: true\nexample: 1",
-                    "type": "integer"
-                },
-                "name": {
+        "/api/projects/{id}/datapoints": {
+            "get": {
+                "description": "Get the point list of a project from the database.\nAny datapoints column can be used as a filter parameter, for example System, EquipType, EquipID, PointType, BACnetObjectType or IncludedInProject; text is matched ignoring case.\nq searches PointName and Descriptor. sort orders by any column, prefixed with - for descending order.\nWithout limit every matching point is returned; with limit the response meta carries the total count and the cursor of the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Get the datapoints of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by system",
+                        "name": "System",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by equipment type",
+                        "name": "EquipType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by equipment",
+                        "name": "EquipID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by point type",
+                        "name": "PointType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by BACnet object type",
+                        "name": "BACnetObjectType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by inclusion in the project",
+                        "name": "IncludedInProject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search PointName and Descriptor",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column to sort by, e.g. PointName or -PointName",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DataPoint"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/models.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a single datapoint to a project's point list\nThe BACnet addressing is validated and must not repeat the object of another point of the project. Trend intervals and sample counts must fit a field panel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Create a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datapoint to be created",
+                        "name": "datapoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataPoint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataPoint"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/datapoints/export": {
+            "get": {
+                "description": "Stream the point list of a project as CSV or XLSX. The header uses the datapoints column names, so the file can be uploaded again unchanged.\ncolumns picks the columns and their order; every column is exported by default. The filter, q and sort parameters of the datapoints list apply.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Export the datapoints of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated column names, e.g. EquipID,PointName,Descriptor",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search PointName and Descriptor",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column to sort by, e.g. PointName or -PointName",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/datapoints/scaling": {
+            "get": {
+                "description": "List the datapoints of a project whose Slope or Intercept differ from those computed from their SensorType, EngineeringLow and EngineeringHigh.\nPoints with a sensor type or range whose scaling cannot be computed are listed as unchecked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scaling"
+                ],
+                "summary": "Check the scaling of datapoints",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Allowed difference, relative to the slope and to the engineering span (default 0.001)",
+                        "name": "tolerance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/sensors.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Compute Slope and Intercept from the raw range of the SensorType and the EngineeringLow and EngineeringHigh of each point, and store them.\nsensorType, low and high replace the stored values of the listed points first when given. Without pointIds every point of the project with a sensor type is recalculated. Points whose scaling cannot be computed are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scaling"
+                ],
+                "summary": "Recalculate the scaling of datapoints",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Points and range",
+                        "name": "scaling",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.scalingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.scalingResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/datapoints/{pointId}": {
+            "get": {
+                "description": "Get a datapoint of a project with all of its columns",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Get a datapoint by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataPoint"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace every column of a datapoint; columns missing from the body are cleared\nTrend and BACnet addressing columns are only validated where the new value differs from the stored one.\nUnitId is looked up again in the units catalogue when EngineeringUnits or NavigatorUnits change, unless the body changes UnitId too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Update a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datapoint to be stored",
+                        "name": "datapoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataPoint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataPoint"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a datapoint from a project's point list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Delete a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the columns present in the body and keep the others\nTrend and BACnet addressing columns are only validated where the new value differs from the stored one.\nUnitId is looked up again in the units catalogue when EngineeringUnits or NavigatorUnits change, unless the body changes UnitId too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Patch a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Columns to be changed",
+                        "name": "datapoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataPoint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataPoint"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/datapoints/{pointId}/tags": {
+            "get": {
+                "description": "Get the marker tags derived for a datapoint, the user's overrides and the resulting tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Get the Haystack tags of a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.pointTagging"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the marker tags added to and removed from the tags derived for a datapoint. Empty lists return the point to its derived tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Override the Haystack tags of a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to add and remove",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.tagOverrides"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.pointTagging"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/datapoints/{pointId}/trends": {
+            "get": {
+                "description": "Get the change of value trend and the interval trend definitions of a datapoint, read from its trend columns.\nColumns that cannot be read are left out and listed in errors.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trends"
+                ],
+                "summary": "Get the trends of a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.pointTrends"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the change of value trend and the interval trend definitions of a datapoint. Every trend column is rewritten; definitions without a slot take the first free one.\nIntervals are HH:MM:SS, HH:MM, a number and unit such as 15 min or 1 hr, a duration such as 1h30m or a number of minutes. Collection is Auto, Manual or Off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trends"
+                ],
+                "summary": "Update the trends of a datapoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Datapoint ID",
+                        "name": "pointId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trend configuration",
+                        "name": "trends",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TrendConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TrendConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/devices": {
+            "get": {
+                "description": "Get the field panels and field devices of a project, derived from the DeviceSysName and FLNdeviceSysName of its datapoints or declared through the API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Get the devices of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Device"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Declare a field panel or field device before any datapoint refers to it. A declared device stays until it is deleted; the values of its points take precedence over the declared network, parent, address and instance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Declare a device",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Device to be declared",
+                        "name": "device",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Device"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Device"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/devices/{deviceId}": {
+            "delete": {
+                "description": "Remove a device of a project. Devices that datapoints still refer to cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Delete a device",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Device ID",
+                        "name": "deviceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/diff": {
+            "post": {
+                "description": "Compare the point list of a project with a CSV or XLSX point list before importing it, matching points on EquipID and PointName.\nThe file is read like an upload, with the same aliases, sheet and headerRow options, but nothing is stored. Only the columns of the file are compared; rejected rows are left out and listed in the report.\nWith format=csv the changes are downloaded as a table with one row per added or removed point and one per changed column.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Compare the points of a project with a file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file to compare",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping headers to datapoints columns",
+                        "name": "aliases",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject files with unknown columns",
+                        "name": "strict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "XLSX worksheet name or 1-based position, the first sheet by default",
+                        "name": "sheet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based row holding the header, 1 by default",
+                        "name": "headerRow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.diffResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.diffResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/diff/{otherId}": {
+            "get": {
+                "description": "Compare the point list of a project with the one of another project, matching points on EquipID and PointName.\nPoints only in the other project are added, points only in this project removed, and points in both with different values modified, with the before and after value of every changed column.\nWith format=csv the changes are downloaded as a table with one row per added or removed point and one per changed column.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "datapoints"
+                ],
+                "summary": "Compare the points of two projects",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID, the point list before",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID, the point list after",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/diff.Result"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/equipment": {
+            "get": {
+                "description": "Get the equipment derived from the datapoints of a project, one per EquipID. The parent of a piece of equipment comes from the EquipRef of its points.\nWith tree=true the equipment is nested under its parent; equipment without a known parent is a root.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Get the equipment of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the equipment as a nested tree",
+                        "name": "tree",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Equipment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/equipment/{equipId}": {
+            "get": {
+                "description": "Get a piece of equipment of a project by EquipID, with the equipment it feeds nested below it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Get a piece of equipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EquipID",
+                        "name": "equipId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/equipment.Node"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/equipment/{equipId}/points": {
+            "get": {
+                "description": "Get the datapoints of a project with the given EquipID. The filter, q, sort and paging parameters of the datapoints list apply.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Get the datapoints of a piece of equipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EquipID",
+                        "name": "equipId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search PointName and Descriptor",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column to sort by, e.g. PointName or -PointName",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DataPoint"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/models.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/export/apogee": {
+            "get": {
+                "description": "Export the datapoints of a project for import into Apogee/Desigo: CSV with one row per point, or text with one POINT block per point.\nPoint types, engineering units and sensor types are translated; points that cannot be expressed are left out. GET /api/projects/{id}/export/apogee/report tells which and why.",
+                "produces": [
+                    "text/csv",
+                    "text/plain"
+                ],
+                "tags": [
+                    "apogee"
+                ],
+                "summary": "Export a project as Apogee point definitions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or txt",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/export/apogee/report": {
+            "get": {
+                "description": "List the datapoints of a project an Apogee export skips, and the values it drops or shortens, such as units without an Apogee spelling or descriptors over 16 characters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apogee"
+                ],
+                "summary": "Check a project against Apogee",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/exporters.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/export/brick": {
+            "get": {
+                "description": "Export a project as Brick Schema RDF in Turtle. The project is a brick:Site with a brick:Building; equipment takes its class from EquipType and brick:feeds the equipment naming it in EquipRef.\nPoints take their class from their Haystack tags and carry a BACnet reference when their device, object type and instance are set.",
+                "produces": [
+                    "text/turtle"
+                ],
+                "tags": [
+                    "brick"
+                ],
+                "summary": "Export a project as a Brick model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/export/haystack": {
+            "get": {
+                "description": "Export a project as a Haystack grid in Zinc or JSON: the project as the site, its equipment and its datapoints with their marker tags.\nEquipment and points reference the site through siteRef and their equipment through equipRef.",
+                "produces": [
+                    "text/zinc",
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Export a project as Project Haystack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "zinc (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/haystack/rules": {
+            "get": {
+                "description": "Get the rules deriving Haystack marker tags from the datapoints of a project. Projects without rules of their own use the default rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Get the Haystack tagging rules of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TaggingRules"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the tagging rules of a project. A rule adds its tags to the datapoints whose column matches its pattern anywhere, ignoring case.\nRules on EquipType tag the equipment; the others tag the points.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Update the Haystack tagging rules of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tagging rules to be stored",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaggingRules"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TaggingRules"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/haystack/tags": {
+            "get": {
+                "description": "Get the marker tags derived for every datapoint of a project, the user's overrides and the resulting tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "haystack"
+                ],
+                "summary": "Get the Haystack tags of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/controller.pointTagging"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/imports": {
+            "get": {
+                "description": "Get every point list uploaded into a project, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Get the imports of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Import"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/naming": {
+            "get": {
+                "description": "Get the naming rules and point name template of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "naming"
+                ],
+                "summary": "Get the naming convention of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NamingConvention"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the naming rules and point name template of a project.\nRules are regular expressions the whole value of a column must match. The template names columns in braces, e.g. {System:upper}.{EquipID}.{Function}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "naming"
+                ],
+                "summary": "Update the naming convention of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Naming convention to be stored",
+                        "name": "convention",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NamingConvention"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NamingConvention"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/naming/generate": {
+            "post": {
+                "description": "Build PointName from the project's point name template, for the datapoints without a name unless overwrite=true.\nThe names are only previewed unless apply=true, which stores them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "naming"
+                ],
+                "summary": "Generate point names",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Store the generated names",
+                        "name": "apply",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also rename datapoints that have a name",
+                        "name": "overwrite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/controller.generatedName"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/naming/violations": {
+            "get": {
+                "description": "Evaluate every datapoint of a project against its naming rules and list the values that break them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "naming"
+                ],
+                "summary": "Get the naming violations of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.namingReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/networks": {
+            "get": {
+                "description": "Get the BLN, FLN, MS/TP and BACnet/IP networks derived from the addressing columns of the datapoints of a project, with their device and point counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Get the networks of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Network"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/templates/{templateId}/stamp": {
+            "post": {
+                "description": "Add count pieces of equipment to a project, each with the points of the template. EquipIDs are the prefix followed by a number counting up from start, zero padded to digits.\nPoint names come from the project's point name template, or are EquipID.Function when it has none. Units are mapped onto the units catalogue like an import.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Stamp equipment out of a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Equipment to be created",
+                        "name": "stamp",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/templates.Stamp"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.stampResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/topology": {
+            "get": {
+                "description": "Get the networks of a project with their panels, the field devices of each panel and the datapoints of each device.\nPoints that belong to no device are listed in unassignedPoints, devices without points in unusedDevices.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Get the topology of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/topology.Site"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/topology/sync": {
+            "post": {
+                "description": "Derive the networks and devices of a project from its datapoints again and return the resulting tree. Projects whose points have not changed since the topology was introduced need this once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topology"
+                ],
+                "summary": "Rebuild the topology of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/topology.Site"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/projects/{id}/trends": {
+            "get": {
+                "description": "Count the trended points, trend definitions, buffered panel samples and interval samples per day of every panel of a project.\nPoints are assigned to panels by DeviceSysName, or NodeIdentifier when they have no system name. Panels buffering more samples than a panel holds are flagged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trends"
+                ],
+                "summary": "Get the trend load of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/trends.Summary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sensors": {
+            "get": {
+                "description": "Get the sensor types Slope and Intercept can be computed for, with the range of their raw value. Temperature sensors report degrees Fahrenheit over their rated range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scaling"
+                ],
+                "summary": "Get the sensor types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/sensors.Sensor"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/templates": {
+            "get": {
+                "description": "Get the point template library, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get the point templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PointTemplate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add the standard point set of an equipment type to the library. Every point needs a Function unique within the template.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create a point template",
+                "parameters": [
+                    {
+                        "description": "Template to be created",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PointTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PointTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/templates/{templateId}": {
+            "get": {
+                "description": "Get a point template with its points",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get a point template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PointTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a point template. Equipment stamped from it before keeps its points.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Update a point template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template to be stored",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PointTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PointTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a point template. Equipment stamped from it keeps its points.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete a point template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/units": {
+            "get": {
+                "description": "Get the engineering units point list units are mapped onto, the BACnet engineering units enumeration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "units"
+                ],
+                "summary": "Get the units catalogue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Unit"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/units/aliases": {
+            "get": {
+                "description": "Get the spellings mapped onto catalogue units besides their names and symbols",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "units"
+                ],
+                "summary": "Get the unit aliases",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UnitAlias"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Map a spelling onto a catalogue unit for future imports. The alias is stored normalized: lower case, \"°\" as \"deg\" and without spaces or punctuation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "units"
+                ],
+                "summary": "Create a unit alias",
+                "parameters": [
+                    {
+                        "description": "Alias to be created",
+                        "name": "alias",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UnitAlias"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UnitAlias"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/units/aliases/{aliasId}": {
+            "delete": {
+                "description": "Remove a spelling from the unit aliases",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "units"
+                ],
+                "summary": "Delete a unit alias",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Alias ID",
+                        "name": "aliasId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/upload/{id}": {
+            "post": {
+                "description": "Uploads a CSV or XLSX point list, maps its header onto the datapoints columns and saves the valid rows to the database.\nHeaders are matched through the alias table; the optional \"aliases\" form field adds request specific aliases as a JSON object of header to column.\nRows with invalid BACnet addressing, or addressing an object already used by the project or an earlier row, are rejected, and so are rows with trend intervals or sample counts a field panel does not support. Trend values that cannot be read reject their rows too, unless legacyTrends=true imports them as they are with a warning.\nEngineeringUnits, or failing that NavigatorUnits, is mapped onto the units catalogue to fill in UnitId; units that are not in the catalogue are reported.\nUnknown columns are ignored and reported unless strict=true, which rejects the file.\nThe import runs in a single transaction: if any row is rejected nothing is written unless partial=true, and replace=true swaps the project's existing point list for the file atomically.\nEvery upload is recorded as an import and the file is kept for download.\nXLSX workbooks are detected from their content; sheet picks the worksheet and headerRow the row holding the header, for CSV files too.\nWith async=true the file is queued for a background worker and 202 is returned straight away; poll the import for progress.\nWith dryRun=true nothing is stored: the response previews the column mapping, the first previewRows normalized rows, the errors of every row and the diff of the project's point list against the file (uploadPreview). When appending every row is listed as added and the rows sharing EquipID and PointName with an existing point are listed as collisions.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Upload CSV or XLSX file and save data to the database",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping headers to datapoints columns",
+                        "name": "aliases",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is uploading the file",
+                        "name": "uploader",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject files with unknown columns",
+                        "name": "strict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import the valid rows even if some rows are rejected",
+                        "name": "partial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Replace the project's existing datapoints",
+                        "name": "replace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import trend values that cannot be read with a warning instead of rejecting their rows",
+                        "name": "legacyTrends",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import in the background",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate the file and preview the import without writing anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of rows returned by a dry run, 20 by default",
+                        "name": "previewRows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "XLSX worksheet name or 1-based position, the first sheet by default",
+                        "name": "sheet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based row holding the header, 1 by default",
+                        "name": "headerRow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.uploadResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Import"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.uploadResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controller.uploadResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "capacity.Load": {
+            "type": "object",
+            "properties": {
+                "covPoints": {
+                    "type": "integer"
+                },
+                "messagesPerSecond": {
+                    "description": "MessagesPerSecond counts interval samples and COV reports.",
+                    "type": "number"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "samplesPerDay": {
+                    "type": "number"
+                },
+                "trendedPoints": {
+                    "type": "integer"
+                }
+            }
+        },
+        "capacity.Node": {
+            "type": "object",
+            "properties": {
+                "bufferedSamples": {
+                    "type": "integer"
+                },
+                "covPoints": {
+                    "type": "integer"
+                },
+                "exceeded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "messagesPerSecond": {
+                    "description": "MessagesPerSecond counts interval samples and COV reports.",
+                    "type": "number"
+                },
+                "panel": {
+                    "description": "Panel is \"\" for the points assigned to no panel.",
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "samplesPerDay": {
+                    "type": "number"
+                },
+                "storageBytes": {
+                    "type": "integer"
+                },
+                "trendedPoints": {
+                    "type": "integer"
+                }
+            }
+        },
+        "capacity.Report": {
+            "type": "object",
+            "properties": {
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/capacity.Node"
+                    }
+                },
+                "overloaded": {
+                    "description": "Overloaded is the number of nodes and trunks exceeding a threshold.",
+                    "type": "integer"
+                },
+                "thresholds": {
+                    "$ref": "#/definitions/capacity.Thresholds"
+                },
+                "total": {
+                    "$ref": "#/definitions/capacity.Load"
+                },
+                "trunks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/capacity.Trunk"
+                    }
+                }
+            }
+        },
+        "capacity.Thresholds": {
+            "type": "object",
+            "properties": {
+                "blnMessages": {
+                    "description": "BLNMessages and MSTPMessages are the messages per second a trunk\ncarries.",
+                    "type": "number"
+                },
+                "bytesPerSample": {
+                    "description": "BytesPerSample is the memory a buffered sample takes.",
+                    "type": "integer"
+                },
+                "covPerHour": {
+                    "description": "COVPerHour is the number of changes of value a COV point reports an\nhour.",
+                    "type": "number"
+                },
+                "mstpMessages": {
+                    "type": "number"
+                },
+                "panelBytes": {
+                    "description": "PanelBytes is the trend memory of a panel.",
+                    "type": "integer"
+                },
+                "panelSamples": {
+                    "description": "PanelSamples is the number of trend samples a panel buffers, the\nPanelCapacity of the trend limits.",
+                    "type": "integer"
+                }
+            }
+        },
+        "capacity.Trunk": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "number"
+                },
+                "covPoints": {
+                    "type": "integer"
+                },
+                "exceeded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "messagesPerSecond": {
+                    "description": "MessagesPerSecond counts interval samples and COV reports.",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "panels": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "samplesPerDay": {
+                    "type": "number"
+                },
+                "trendedPoints": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "conflicts.Category": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/conflicts.Conflict"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "conflicts.Conflict": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "pointIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "conflicts.Report": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/conflicts.Category"
+                    }
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.diffResult": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "$ref": "#/definitions/diff.Result"
+                },
+                "report": {
+                    "$ref": "#/definitions/importer.Report"
+                }
+            }
+        },
+        "controller.generatedName": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "generated": {
+                    "type": "string"
+                },
+                "pointId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.namingReport": {
+            "type": "object",
+            "properties": {
+                "points": {
+                    "type": "integer"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/naming.Violation"
+                    }
+                }
+            }
+        },
+        "controller.pointTagging": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "derived": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pointId": {
+                    "type": "integer"
+                },
+                "pointName": {
+                    "type": "string"
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controller.pointTrends": {
+            "type": "object",
+            "properties": {
+                "cov": {
+                    "description": "Change of value trending, null when COVTrend is off.",
+                    "$ref": "#/definitions/models.COVTrend"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "trends": {
+                    "description": "The interval trend definitions, at most four.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrendDefinition"
+                    }
+                }
+            }
+        },
+        "controller.scaledPoint": {
+            "type": "object",
+            "properties": {
+                "engineeringHigh": {
+                    "type": "string"
+                },
+                "engineeringLow": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "intercept": {
+                    "type": "string"
+                },
+                "pointName": {
+                    "type": "string"
+                },
+                "sensorType": {
+                    "type": "string"
+                },
+                "slope": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.scalingRequest": {
+            "type": "object",
+            "properties": {
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "description": "Low and High replace the engineering range of the points when set.",
+                    "type": "number"
+                },
+                "pointIds": {
+                    "description": "PointIDs are the points to recalculate; every point with a sensor\ntype when empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sensorType": {
+                    "description": "SensorType replaces the sensor type of the points when set.",
+                    "type": "string"
+                }
+            }
+        },
+        "controller.scalingResult": {
+            "type": "object",
+            "properties": {
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointProblem"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.scaledPoint"
+                    }
+                }
+            }
+        },
+        "controller.stampResult": {
+            "type": "object",
+            "properties": {
+                "equipIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "points": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.tagOverrides": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controller.uploadResult": {
+            "type": "object",
+            "properties": {
+                "import": {
+                    "$ref": "#/definitions/models.Import"
+                },
+                "report": {
+                    "$ref": "#/definitions/importer.Report"
+                }
+            }
+        },
+        "diff.Field": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "column": {
+                    "type": "string"
+                }
+            }
+        },
+        "diff.Point": {
+            "type": "object",
+            "properties": {
+                "EquipID": {
+                    "type": "string"
+                },
+                "afterId": {
+                    "type": "integer"
+                },
+                "beforeId": {
+                    "type": "integer"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Field"
+                    }
+                },
+                "point_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "diff.Result": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Point"
+                    }
+                },
+                "columns": {
+                    "description": "Columns are the columns that were compared.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "modified": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Point"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Point"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "equipment.Node": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/equipment.Node"
+                    }
+                },
+                "equipId": {
+                    "description": "The EquipID of its datapoints.\n\nexample: AHU-1",
+                    "type": "string"
+                },
+                "equipType": {
+                    "description": "The most common EquipType of its datapoints.\n\nexample: AHU",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the equipment.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "parent": {
+                    "description": "The EquipID of the equipment feeding this one, from the EquipRef of its\ndatapoints.\n\nexample: CH-1",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The number of datapoints of the equipment.\n\nexample: 12",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the equipment belongs to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "system": {
+                    "description": "The most common System of its datapoints.\n\nexample: HVAC",
+                    "type": "string"
+                }
+            }
+        },
+        "exporters.Report": {
+            "type": "object",
+            "properties": {
+                "exported": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped are the points left out, one problem each.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointProblem"
+                    }
+                },
+                "warnings": {
+                    "description": "Warnings are the values of exported points that were dropped or\nchanged.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointProblem"
+                    }
+                }
+            }
+        },
+        "importer.ColumnMapping": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "header": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.Report": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.ColumnMapping"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.RowResult"
+                    }
+                },
+                "unknownColumns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unmappedUnits": {
+                    "description": "UnmappedUnits lists the units of imported rows that are not in the\ncatalogue. The rows are imported without a UnitId.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "importer.RowResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "type": "boolean"
+                },
+                "row": {
+                    "description": "Row is the 1-based record number in the file, counted from the\nheader row.",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Warnings are the values that were imported as they are, such as\ntrend settings that cannot be read.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.COVTrend": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "description": "Auto, Manual or Off.\n\nexample: Auto",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "The change that triggers a sample, in engineering units.\n\nexample: 0.5",
+                    "type": "number"
+                },
+                "panelSamples": {
+                    "description": "The number of samples kept in the panel.\n\nexample: 500",
+                    "type": "integer"
+                },
+                "pcSamples": {
+                    "description": "The number of samples kept on the PC.\n\nexample: 10000",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DataPoint": {
+            "type": "object",
+            "properties": {
+                "AddressTypeDHCP": {
+                    "type": "string"
+                },
+                "ApogeePtType": {
+                    "description": "Apogee point definition.",
+                    "type": "string"
+                },
+                "AppInstanceId": {
+                    "type": "string"
+                },
+                "BACnetDeviceInstance": {
+                    "type": "string"
+                },
+                "BACnetNetwork": {
+                    "type": "integer"
+                },
+                "BACnetObjectInstance": {
+                    "type": "string"
+                },
+                "BACnetObjectName": {
+                    "type": "string"
+                },
+                "BACnetObjectType": {
+                    "description": "BACnet addressing.",
+                    "type": "string"
+                },
+                "BLNSysName": {
+                    "type": "string"
+                },
+                "BLNType": {
+                    "description": "Apogee building level network addressing.",
+                    "type": "string"
+                },
+                "COVLimit": {
+                    "type": "string"
+                },
+                "COVTrend": {
+                    "description": "Change of value and trend settings.",
+                    "type": "string"
+                },
+                "Collection": {
+                    "type": "string"
+                },
+                "Collection1": {
+                    "type": "string"
+                },
+                "Collection2": {
+                    "type": "string"
+                },
+                "Collection3": {
+                    "type": "string"
+                },
+                "Collection4": {
+                    "type": "string"
+                },
+                "DeviceSysName": {
+                    "type": "string"
+                },
+                "DisplayMode": {
+                    "type": "string"
+                },
+                "EngineeringHigh": {
+                    "type": "string"
+                },
+                "EngineeringLow": {
+                    "description": "The engineering values at the low and high end of the sensor's raw\nrange, which Slope and Intercept are computed from.",
+                    "type": "string"
+                },
+                "EngineeringUnits": {
+                    "description": "Engineering units and sensor scaling.",
+                    "type": "string"
+                },
+                "EquipID": {
+                    "description": "Equipment and point identification.",
+                    "type": "string"
+                },
+                "EquipRef": {
+                    "description": "Equipment reference and display.",
+                    "type": "string"
+                },
+                "EquipType": {
+                    "type": "string"
+                },
+                "FLNdeviceSysName": {
+                    "type": "string"
+                },
+                "Function": {
+                    "type": "string"
+                },
+                "IPaddress": {
+                    "type": "string"
+                },
+                "IPport": {
+                    "type": "string"
+                },
+                "IncludedInProject": {
+                    "description": "Project bookkeeping.",
+                    "type": "boolean"
+                },
+                "InsightNodeNumber": {
+                    "type": "integer"
+                },
+                "Intercept": {
+                    "type": "string"
+                },
+                "LocalRef": {
+                    "type": "string"
+                },
+                "MSTPaddress": {
+                    "type": "string"
+                },
+                "MSTPnetwork": {
+                    "type": "string"
+                },
+                "MatchingRatio": {
+                    "type": "string"
+                },
+                "Medium": {
+                    "type": "string"
+                },
+                "MediumId": {
+                    "type": "integer"
+                },
+                "Memo": {
+                    "type": "string"
+                },
+                "NavigatorUnits": {
+                    "type": "string"
+                },
+                "NodeIdentifier": {
+                    "type": "integer"
+                },
+                "PCDays1": {
+                    "type": "string"
+                },
+                "PCDays2": {
+                    "type": "string"
+                },
+                "PCDays3": {
+                    "type": "string"
+                },
+                "PCDays4": {
+                    "type": "string"
+                },
+                "PCSamples": {
+                    "type": "string"
+                },
+                "PanelSamples": {
+                    "type": "string"
+                },
+                "PanelSamples1": {
+                    "type": "string"
+                },
+                "PanelSamples2": {
+                    "type": "string"
+                },
+                "PanelSamples3": {
+                    "type": "string"
+                },
+                "PanelSamples4": {
+                    "type": "string"
+                },
+                "ReportGroup": {
+                    "type": "string"
+                },
+                "ReportGroupId": {
+                    "type": "integer"
+                },
+                "SensorType": {
+                    "type": "string"
+                },
+                "Slope": {
+                    "type": "string"
+                },
+                "System": {
+                    "type": "string"
+                },
+                "TrendInterval1": {
+                    "type": "string"
+                },
+                "TrendInterval2": {
+                    "type": "string"
+                },
+                "TrendInterval3": {
+                    "type": "string"
+                },
+                "TrendInterval4": {
+                    "type": "string"
+                },
+                "UDPport": {
+                    "type": "string"
+                },
+                "UnitId": {
+                    "type": "integer"
+                },
+                "Virtual": {
+                    "type": "string"
+                },
+                "descriptor": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the datapoint.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "point_name": {
+                    "type": "string"
+                },
+                "point_type": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "description": "The project the datapoint belongs to.\n\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Device": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The node number, MS/TP MAC address or IP address of the device.\n\nexample: 10.0.0.21:47808",
+                    "type": "string"
+                },
+                "declared": {
+                    "description": "Whether the device was declared through the API.\n\nexample: false",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "The unique identifier of the device.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "instance": {
+                    "description": "The BACnet device instance.\n\nexample: 2001",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "panel or field.\n\nrequired: true\nexample: panel",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "The system name of the device, unique within the project.\n\nrequired: true\nexample: PXC-1",
+                    "type": "string"
+                },
+                "network": {
+                    "description": "The name of the network the device is on.\n\nexample: BLN1",
+                    "type": "string"
+                },
+                "networkType": {
+                    "description": "The type of the network the device is on.\n\nexample: BLN",
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "The panel a field device is connected to.\n\nexample: PXC-1",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The number of datapoints of the device.\n\nexample: 24",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the device belongs to.\n\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Equipment": {
+            "type": "object",
+            "properties": {
+                "equipId": {
+                    "description": "The EquipID of its datapoints.\n\nexample: AHU-1",
+                    "type": "string"
+                },
+                "equipType": {
+                    "description": "The most common EquipType of its datapoints.\n\nexample: AHU",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the equipment.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "parent": {
+                    "description": "The EquipID of the equipment feeding this one, from the EquipRef of its\ndatapoints.\n\nexample: CH-1",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The number of datapoints of the equipment.\n\nexample: 12",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the equipment belongs to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "system": {
+                    "description": "The most common System of its datapoints.\n\nexample: HVAC",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Import": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "The hex encoded SHA-256 checksum of the file.",
+                    "type": "string"
+                },
+                "durationMs": {
+                    "description": "How long the import took in milliseconds.",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "The errors found in the file, or why the import failed.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fileName": {
+                    "description": "The name of the file as uploaded.\n\nexample: points.csv",
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "description": "When the import finished, if it has.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the import.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "importedRows": {
+                    "description": "The number of rows written to the project.",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "Whether the points were appended or replaced the project's point list.\n\nexample: append",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "The options the import was started with, kept so a queued import can\nbe resumed after a restart.",
+                    "$ref": "#/definitions/models.ImportOptions"
+                },
+                "processedRows": {
+                    "description": "The number of rows written so far while the import is running.",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the file was imported into.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "rejectedRows": {
+                    "description": "The number of rows that failed validation.",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "The size of the file in bytes.\n\nexample: 20480",
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "description": "When the import started.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "The state of the import: queued, running, succeeded, rejected or failed.\n\nexample: succeeded",
+                    "type": "string"
+                },
+                "storedName": {
+                    "description": "The name the file is kept under in the upload directory.\n\nexample: 1-3861530372.csv",
+                    "type": "string"
+                },
+                "totalRows": {
+                    "description": "The number of data rows in the file.",
+                    "type": "integer"
+                },
+                "uploader": {
+                    "description": "Who uploaded the file.\n\nexample: jdoe",
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "Problems that did not stop rows from being imported, such as units\nmissing from the catalogue.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ImportOptions": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Request specific header to column aliases.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "headerRow": {
+                    "description": "The 1-based row holding the header.",
+                    "type": "integer"
+                },
+                "legacyTrends": {
+                    "description": "Import trend values that cannot be read as they are, with a warning,\ninstead of rejecting their rows.",
+                    "type": "boolean"
+                },
+                "partial": {
+                    "description": "Import the valid rows even if some rows are rejected.",
+                    "type": "boolean"
+                },
+                "sheet": {
+                    "description": "The worksheet of an XLSX file to read, by name or 1-based position.",
+                    "type": "string"
+                },
+                "strict": {
+                    "description": "Reject files with unknown columns.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Meta": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "The number of items in this page.\n\nexample: 100",
+                    "type": "integer"
+                },
+                "limit": {
+                    "description": "The page size that was applied, if any.\n\nexample: 100",
+                    "type": "integer"
+                },
+                "nextCursor": {
+                    "description": "The cursor to pass to fetch the next page, absent on the last page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "The number of items matching the request over all pages.\n\nexample: 1250",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NamingConvention": {
+            "type": "object",
+            "properties": {
+                "pointNameTemplate": {
+                    "description": "The template point names are generated from. Placeholders name a\ndatapoints column, optionally with an upper or lower modifier.\n\nexample: {System:upper}.{EquipType}.{EquipID}.{Function}",
+                    "type": "string"
+                },
+                "projectId": {
+                    "description": "The project the rules apply to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "rules": {
+                    "description": "The rules the values of the datapoints must follow.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NamingRule"
+                    }
+                }
+            }
+        },
+        "models.NamingRule": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "description": "The datapoints column the rule checks.\n\nrequired: true\nexample: PointName",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "What the rule asks for, reported with its violations.\n\nexample: Building.Floor.Equipment.Point",
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "A regular expression the whole value must match. Empty values are not\nchecked.\n\nrequired: true\nexample: [A-Z]+\\.[0-9]{2}\\.[A-Z]{3}[0-9]{2}\\.[A-Z]+",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Network": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "description": "The number of devices on the network.\n\nexample: 4",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "The unique identifier of the network.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "The BLNSysName, MSTPnetwork or BACnetNetwork of the network. FLN\ntrunks are named after their panel.\n\nexample: BLN1",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The number of datapoints of the devices on the network.\n\nexample: 120",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the network belongs to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "BLN, FLN, MS/TP or BACnet/IP.\n\nexample: BLN",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PointProblem": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pointId": {
+                    "type": "integer"
+                },
+                "pointName": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PointTemplate": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "What the template is for.\n\nexample: Pressure independent VAV box with hot water reheat",
+                    "type": "string"
+                },
+                "equipType": {
+                    "description": "The EquipType of the equipment stamped from the template.\n\nrequired: true\nexample: VAV",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the template.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "The unique name of the template.\n\nrequired: true\nexample: VAV with reheat",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The points of every piece of equipment.\n\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TemplatePoint"
+                    }
+                }
+            }
+        },
+        "models.Project": {
+            "type": "object",
+            "properties": {
+                "branchId": {
+                    "description": "The branch ID associated with the project.\n\nrequired: true\nexample: 1",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "The date of the project.\n\nrequired: true\nformat: date\nexample: 2023-06-07",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The unique identifier of the project.\n\nrequired: true\nexample: 1",
+                    "type": "integer"
+                },
+                "name": {
                     "description": "The name of the project.\n\nrequired: true\nexample: Project 1",
                     "type": "string"
                 },
-                "notes": {
-                    "description": "Additional notes for the project.\n\nrequired: false\nexample: Some notes about the project.",
+                "notes": {
+                    "description": "Additional notes for the project.\n\nrequired: false\nexample: Some notes about the project.",
+                    "type": "string"
+                },
+                "sapNumber": {
+                    "description": "The SAP number of the project.\n\nrequired: true\nexample: SAP12345",
+                    "type": "string"
+                },
+                "services": {
+                    "description": "The service associated with the project.\n\nrequired: true",
+                    "$ref": "#/definitions/models.Service"
+                },
+                "statusId": {
+                    "description": "The status ID of the project.\n\nrequired: true\nexample: 1",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "The title of the project.\n\nrequired: true\nexample: Title 1",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Response": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "The data payload of the response."
+                },
+                "message": {
+                    "description": "The message associated with the response.\n\nrequired: true\nexample: Success",
+                    "type": "string"
+                },
+                "meta": {
+                    "description": "Paging information for list responses.",
+                    "$ref": "#/definitions/models.Meta"
+                },
+                "status": {
+                    "description": "The status code of the response.\n\nrequired: true\nexample: 200",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Service": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "The ID of the service.\n\nrequired: true\nexample: 1",
+                    "type": "integer"
+                },
+                "serviceName": {
+                    "description": "The name of the service.\n\nrequired: true\nexample: Service 1",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagRule": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "description": "The datapoints column the rule looks at.\n\nrequired: true\nexample: Descriptor",
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "A regular expression found anywhere in the value, ignoring case.\n\nrequired: true\nexample: \\b(discharge|supply)\\b",
                     "type": "string"
                 },
-                "sapnumber": {
-                    "description": "The SAP number of the project.\n\nrequired: true\nexample: SAP12345",
+                "tags": {
+                    "description": "The marker tags added when the pattern matches.\n\nrequired: true\nexample: [\"discharge\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TaggingRules": {
+            "type": "object",
+            "properties": {
+                "projectId": {
+                    "description": "The project the rules apply to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "rules": {
+                    "description": "The rules, applied in order. Every matching rule adds its tags.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagRule"
+                    }
+                }
+            }
+        },
+        "models.TemplatePoint": {
+            "type": "object",
+            "properties": {
+                "BACnetObjectType": {
+                    "description": "example: AI",
                     "type": "string"
                 },
-                "services": {
-                    "description": "The service associated with the project.\n\nrequired: true",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Service"
-                        }
-                    ]
+                "EngineeringUnits": {
+                    "description": "example: degF",
+                    "type": "string"
                 },
-                "statusId": {
-                    "description": "The status ID of the project.\n\nrequired: true\nexample: 1",
+                "Function": {
+                    "description": "Unique within the template; used to generate the point name.\n\nrequired: true\nexample: ZN-T",
+                    "type": "string"
+                },
+                "descriptor": {
+                    "description": "example: Zone Temperature",
+                    "type": "string"
+                },
+                "point_type": {
+                    "description": "example: AI",
+                    "type": "string"
+                },
+                "trend": {
+                    "description": "The change of value and interval trends, none when null.",
+                    "$ref": "#/definitions/models.TrendConfig"
+                }
+            }
+        },
+        "models.TrendConfig": {
+            "type": "object",
+            "properties": {
+                "cov": {
+                    "description": "Change of value trending, null when COVTrend is off.",
+                    "$ref": "#/definitions/models.COVTrend"
+                },
+                "trends": {
+                    "description": "The interval trend definitions, at most four.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrendDefinition"
+                    }
+                }
+            }
+        },
+        "models.TrendDefinition": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "description": "Auto, Manual or Off.\n\nexample: Auto",
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "The sampling interval as HH:MM:SS.\n\nrequired: true\nexample: 00:15:00",
+                    "type": "string"
+                },
+                "intervalSeconds": {
+                    "description": "The sampling interval in seconds, ignored on input.\n\nexample: 900",
                     "type": "integer"
                 },
-                "title": {
-                    "description": "The title of the project.\n\nrequired: true\nexample: Title 1",
+                "panelSamples": {
+                    "description": "The number of samples kept in the panel.\n\nexample: 672",
+                    "type": "integer"
+                },
+                "pcDays": {
+                    "description": "The number of days of samples kept on the PC.\n\nexample: 365",
+                    "type": "integer"
+                },
+                "slot": {
+                    "description": "The column set the definition is stored in, 1 to 4.\n\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Unit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "The BACnet engineering units value.\n\nexample: 64",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "The BACnet name of the unit.\n\nexample: degrees-fahrenheit",
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "The usual symbol of the unit, if it has one.\n\nexample: °F",
                     "type": "string"
                 }
             }
         },
-        "models.Response": {
+        "models.UnitAlias": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "The data payload of the response."
+                "alias": {
+                    "description": "The spelling, stored normalized.\n\nrequired: true\nexample: degf",
+                    "type": "string"
                 },
-                "message": {
-                    "description": "The message associated with the response.\n\nrequired: true\nexample: Success",
+                "id": {
+                    "description": "The unique identifier of the alias.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "unitId": {
+                    "description": "The unit the spelling stands for.\n\nrequired: true\nexample: 64",
+                    "type": "integer"
+                }
+            }
+        },
+        "naming.Violation": {
+            "type": "object",
+            "properties": {
+                "column": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "The status code of the response.\n\nrequired: true\nexample: 200",
+                "description": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "pointId": {
                     "type": "integer"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "models.Service": {
+        "sensors.Mismatch": {
+            "type": "object",
+            "properties": {
+                "engineeringHigh": {
+                    "type": "string"
+                },
+                "engineeringLow": {
+                    "type": "string"
+                },
+                "expectedIntercept": {
+                    "type": "string"
+                },
+                "expectedSlope": {
+                    "type": "string"
+                },
+                "intercept": {
+                    "type": "string"
+                },
+                "pointId": {
+                    "type": "integer"
+                },
+                "pointName": {
+                    "type": "string"
+                },
+                "sensorType": {
+                    "type": "string"
+                },
+                "slope": {
+                    "type": "string"
+                }
+            }
+        },
+        "sensors.Report": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Checked is the number of points whose scaling could be computed.",
+                    "type": "integer"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sensors.Mismatch"
+                    }
+                },
+                "unchecked": {
+                    "description": "Unchecked are the points with a sensor type or engineering range\nwhose scaling cannot be computed.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointProblem"
+                    }
+                }
+            }
+        },
+        "sensors.Sensor": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases are other spellings of the type seen in point lists.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "apogee": {
+                    "description": "Apogee is the sensor type of Apogee point definitions.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the canonical spelling, written to SensorType.",
+                    "type": "string"
+                },
+                "rawHigh": {
+                    "type": "number"
+                },
+                "rawLow": {
+                    "type": "number"
+                },
+                "signal": {
+                    "description": "Signal is the unit of the raw range.",
+                    "type": "string"
+                }
+            }
+        },
+        "templates.Stamp": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "The number of pieces of equipment.\n\nrequired: true\nexample: 12",
+                    "type": "integer"
+                },
+                "digits": {
+                    "description": "The minimum number of digits, padded with zeros.\n\nexample: 2",
+                    "type": "integer"
+                },
+                "equipRef": {
+                    "description": "The EquipRef of the points: the equipment feeding the new equipment.\n\nexample: AHU-1",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Put in front of the number to form the EquipID, the template's\nEquipType and a dash by default.\n\nexample: VAV-1-",
+                    "type": "string"
+                },
+                "start": {
+                    "description": "The number of the first piece of equipment, 1 by default.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "system": {
+                    "description": "The System of the points.\n\nexample: HVAC",
+                    "type": "string"
+                }
+            }
+        },
+        "topology.DeviceNode": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The node number, MS/TP MAC address or IP address of the device.\n\nexample: 10.0.0.21:47808",
+                    "type": "string"
+                },
+                "declared": {
+                    "description": "Whether the device was declared through the API.\n\nexample: false",
+                    "type": "boolean"
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/topology.DeviceNode"
+                    }
+                },
+                "id": {
+                    "description": "The unique identifier of the device.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "instance": {
+                    "description": "The BACnet device instance.\n\nexample: 2001",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "panel or field.\n\nrequired: true\nexample: panel",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "The system name of the device, unique within the project.\n\nrequired: true\nexample: PXC-1",
+                    "type": "string"
+                },
+                "network": {
+                    "description": "The name of the network the device is on.\n\nexample: BLN1",
+                    "type": "string"
+                },
+                "networkType": {
+                    "description": "The type of the network the device is on.\n\nexample: BLN",
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "The panel a field device is connected to.\n\nexample: PXC-1",
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/topology.Point"
+                    }
+                },
+                "projectId": {
+                    "description": "The project the device belongs to.\n\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "topology.NetworkNode": {
             "type": "object",
             "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/topology.DeviceNode"
+                    }
+                },
                 "id": {
-                    "description": "The ID of the service.\n\nrequired: true\nexample: 1",
+                    "description": "The unique identifier of the network.\n\nexample: 1",
                     "type": "integer"
                 },
                 "name": {
-                    "description": "The name of the service.\n\nrequired: true\nexample: Service 1",
+                    "description": "The BLNSysName, MSTPnetwork or BACnetNetwork of the network. FLN\ntrunks are named after their panel.\n\nexample: BLN1",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "The number of datapoints of the devices on the network.\n\nexample: 120",
+                    "type": "integer"
+                },
+                "projectId": {
+                    "description": "The project the network belongs to.\n\nexample: 1",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "BLN, FLN, MS/TP or BACnet/IP.\n\nexample: BLN",
+                    "type": "string"
+                }
+            }
+        },
+        "topology.Point": {
+            "type": "object",
+            "properties": {
+                "EquipID": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "point_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "topology.Site": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "networks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/topology.NetworkNode"
+                    }
+                },
+                "unassignedPoints": {
+                    "description": "UnassignedPoints are the points that belong to no device.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/topology.Point"
+                    }
+                },
+                "unusedDevices": {
+                    "description": "UnusedDevices are the devices without points or field devices.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Device"
+                    }
+                }
+            }
+        },
+        "trends.PanelLoad": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "covPoints": {
+                    "type": "integer"
+                },
+                "definitions": {
+                    "type": "integer"
+                },
+                "invalidPoints": {
+                    "description": "InvalidPoints are the points whose trend columns cannot be read.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "overCapacity": {
+                    "type": "boolean"
+                },
+                "panel": {
+                    "description": "Panel is the DeviceSysName of the points, \"Node n\" for points with\nonly a NodeIdentifier and \"\" for points assigned to no panel.",
                     "type": "string"
+                },
+                "panelSamples": {
+                    "description": "PanelSamples is the number of samples buffered in the panel.",
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "samplesPerDay": {
+                    "description": "SamplesPerDay counts the interval samples only; COV samples depend\non how the value moves.",
+                    "type": "number"
+                },
+                "trendedPoints": {
+                    "type": "integer"
+                }
+            }
+        },
+        "trends.Summary": {
+            "type": "object",
+            "properties": {
+                "definitions": {
+                    "type": "integer"
+                },
+                "panelSamples": {
+                    "type": "integer"
+                },
+                "panels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trends.PanelLoad"
+                    }
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "samplesPerDay": {
+                    "type": "number"
+                },
+                "trendedPoints": {
+                    "type": "integer"
                 }
             }
         }
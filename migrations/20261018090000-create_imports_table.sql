@@ -0,0 +1,26 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `imports` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT,
+  `ProjectId` int(11) NOT NULL,
+  `FileName` varchar(255) NOT NULL,
+  `StoredName` varchar(255) NOT NULL,
+  `Checksum` char(64) NOT NULL,
+  `Size` bigint(20) NOT NULL DEFAULT 0,
+  `Uploader` varchar(128) DEFAULT NULL,
+  `Mode` varchar(20) NOT NULL,
+  `Status` varchar(20) NOT NULL,
+  `TotalRows` int(11) NOT NULL DEFAULT 0,
+  `ImportedRows` int(11) NOT NULL DEFAULT 0,
+  `RejectedRows` int(11) NOT NULL DEFAULT 0,
+  `Errors` text DEFAULT NULL,
+  `StartedAt` datetime NOT NULL,
+  `FinishedAt` datetime DEFAULT NULL,
+  `DurationMs` bigint(20) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  KEY `Imports_ProjectId_Project_Id_idx` (`ProjectId`),
+  CONSTRAINT `Imports_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+-- +migrate Down
+DROP TABLE imports;
@@ -0,0 +1,10 @@
+
+-- +migrate Up
+ALTER TABLE `imports`
+  ADD COLUMN `ProcessedRows` int(11) NOT NULL DEFAULT 0 AFTER `RejectedRows`,
+  ADD COLUMN `Options` text DEFAULT NULL AFTER `Mode`;
+
+-- +migrate Down
+ALTER TABLE `imports`
+  DROP COLUMN `ProcessedRows`,
+  DROP COLUMN `Options`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `imports`
+  ADD COLUMN `Warnings` text DEFAULT NULL AFTER `Errors`;
+
+-- +migrate Down
+ALTER TABLE `imports`
+  DROP COLUMN `Warnings`;
@@ -0,0 +1,12 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `naming_conventions` (
+  `ProjectId` int(11) NOT NULL,
+  `PointNameTemplate` varchar(255) DEFAULT NULL,
+  `Rules` text DEFAULT NULL,
+  PRIMARY KEY (`ProjectId`),
+  CONSTRAINT `NamingConventions_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+-- +migrate Down
+DROP TABLE naming_conventions;
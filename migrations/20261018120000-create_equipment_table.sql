@@ -0,0 +1,25 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `equipment` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT,
+  `ProjectId` int(11) NOT NULL,
+  `EquipID` varchar(45) NOT NULL,
+  `EquipType` varchar(45) DEFAULT NULL,
+  `System` varchar(45) DEFAULT NULL,
+  `Parent` varchar(45) DEFAULT NULL,
+  `Points` int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `Equipment_ProjectId_EquipID_UNIQUE` (`ProjectId`, `EquipID`),
+  CONSTRAINT `Equipment_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+-- Equipment of the point lists imported so far; the API keeps it in step
+-- with the datapoints from here on.
+INSERT INTO `equipment` (`ProjectId`, `EquipID`, `EquipType`, `System`, `Parent`, `Points`)
+SELECT `ProjectId`, MIN(TRIM(`EquipID`)), MAX(`EquipType`), MAX(`System`), MAX(NULLIF(TRIM(`EquipRef`), '')), COUNT(*)
+FROM `datapoints`
+WHERE TRIM(`EquipID`) <> ''
+GROUP BY `ProjectId`, TRIM(`EquipID`);
+
+-- +migrate Down
+DROP TABLE equipment;
@@ -0,0 +1,22 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `tagging_rules` (
+  `ProjectId` int(11) NOT NULL,
+  `Rules` text DEFAULT NULL,
+  PRIMARY KEY (`ProjectId`),
+  CONSTRAINT `TaggingRules_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE TABLE IF NOT EXISTS `point_tags` (
+  `DataPointId` int(11) NOT NULL,
+  `ProjectId` int(11) NOT NULL,
+  `AddTags` text DEFAULT NULL,
+  `RemoveTags` text DEFAULT NULL,
+  PRIMARY KEY (`DataPointId`),
+  KEY `PointTags_ProjectId_idx` (`ProjectId`),
+  CONSTRAINT `PointTags_DataPointId_DataPoint_Id` FOREIGN KEY (`DataPointId`) REFERENCES `datapoints` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+-- +migrate Down
+DROP TABLE point_tags;
+DROP TABLE tagging_rules;
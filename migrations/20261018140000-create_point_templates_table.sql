@@ -0,0 +1,14 @@
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `point_templates` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT,
+  `Name` varchar(128) NOT NULL,
+  `EquipType` varchar(45) NOT NULL,
+  `Description` varchar(255) DEFAULT NULL,
+  `Points` mediumtext NOT NULL,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `PointTemplates_Name_UNIQUE` (`Name`)
+);
+
+-- +migrate Down
+DROP TABLE point_templates;
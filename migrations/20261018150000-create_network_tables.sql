@@ -0,0 +1,36 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS `networks` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT,
+  `ProjectId` int(11) NOT NULL,
+  `Type` varchar(16) NOT NULL,
+  `Name` varchar(64) NOT NULL,
+  `Devices` int(11) NOT NULL DEFAULT 0,
+  `Points` int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `Networks_ProjectId_Type_Name_UNIQUE` (`ProjectId`, `Type`, `Name`),
+  CONSTRAINT `Networks_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE TABLE IF NOT EXISTS `devices` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT,
+  `ProjectId` int(11) NOT NULL,
+  `Name` varchar(45) NOT NULL,
+  `Kind` varchar(16) NOT NULL,
+  `NetworkType` varchar(16) DEFAULT NULL,
+  `Network` varchar(64) DEFAULT NULL,
+  `Parent` varchar(45) DEFAULT NULL,
+  `Address` varchar(174) DEFAULT NULL,
+  `Instance` int(11) DEFAULT NULL,
+  `Declared` tinyint(1) NOT NULL DEFAULT 0,
+  `Points` int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `Devices_ProjectId_Name_UNIQUE` (`ProjectId`, `Name`),
+  CONSTRAINT `Devices_ProjectId_Project_Id` FOREIGN KEY (`ProjectId`) REFERENCES `projects` (`Id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+-- The networks and devices of existing projects are derived the next time
+-- their points change, or through POST /api/projects/{id}/topology/sync.
+
+-- +migrate Down
+DROP TABLE devices;
+DROP TABLE networks;
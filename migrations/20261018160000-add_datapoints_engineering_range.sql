@@ -0,0 +1,10 @@
+
+-- +migrate Up
+ALTER TABLE `datapoints`
+  ADD COLUMN `EngineeringLow` varchar(45) DEFAULT NULL AFTER `SensorType`,
+  ADD COLUMN `EngineeringHigh` varchar(45) DEFAULT NULL AFTER `EngineeringLow`;
+
+-- +migrate Down
+ALTER TABLE `datapoints`
+  DROP COLUMN `EngineeringLow`,
+  DROP COLUMN `EngineeringHigh`;
@@ -0,0 +1,128 @@
+// Package bacnet validates the BACnet addressing of datapoints.
+//
+// The datapoints table keeps the addressing columns as free text. Check
+// looks at the values of a single point; KeyOf identifies the object a point
+// addresses so duplicates can be found within a project.
+package bacnet
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+const (
+	// MaxInstance is the highest object and device instance number.
+	// 4194303 is reserved as the unconfigured instance.
+	MaxInstance = 4194302
+
+	// MaxMSTPMaster is the highest MS/TP MAC address of a master node.
+	MaxMSTPMaster = 127
+)
+
+// Key identifies a BACnet object: the device that holds it, its type and its
+// instance within the device.
+type Key struct {
+	Device   int
+	Type     ObjectType
+	Instance int
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("device %d, %s %d", k.Device, k.Type, k.Instance)
+}
+
+// KeyOf returns the object addressed by dp. ok is false unless the device
+// instance, object type and object instance are all set and valid.
+func KeyOf(dp models.DataPoint) (key Key, ok bool) {
+	var err error
+	if key.Device, err = parseInstance(dp.BACnetDeviceInstance); err != nil {
+		return Key{}, false
+	}
+	if key.Type, err = ParseObjectType(dp.BACnetObjectType); err != nil {
+		return Key{}, false
+	}
+	if key.Instance, err = parseInstance(dp.BACnetObjectInstance); err != nil {
+		return Key{}, false
+	}
+	return key, true
+}
+
+// Check returns a message for every invalid BACnet addressing value of dp.
+// Empty values are not checked.
+func Check(dp models.DataPoint) []string {
+	return CheckChanged(models.DataPoint{}, dp)
+}
+
+// CheckChanged is Check for the addressing columns whose value differs
+// between before and dp, so that an edit is not refused over values it
+// leaves alone. A zero before checks every column that is set.
+func CheckChanged(before, dp models.DataPoint) []string {
+	var errs []string
+	check := func(column string, parse func(string) error) {
+		value := dp.Get(column)
+		if strings.TrimSpace(value) == "" || value == before.Get(column) {
+			return
+		}
+		if err := parse(value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", column, err))
+		}
+	}
+
+	check("BACnetObjectType", func(v string) error {
+		_, err := ParseObjectType(v)
+		return err
+	})
+	check("BACnetObjectInstance", func(v string) error {
+		_, err := parseInstance(v)
+		return err
+	})
+	check("BACnetDeviceInstance", func(v string) error {
+		_, err := parseInstance(v)
+		return err
+	})
+	check("MSTPaddress", checkMSTPAddress)
+	check("IPaddress", checkIPAddress)
+	check("UDPport", checkUDPPort)
+
+	return errs
+}
+
+func parseInstance(s string) (int, error) {
+	value := strings.TrimSpace(s)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > MaxInstance {
+		return 0, fmt.Errorf("%q is not an instance number between 0 and %d", value, MaxInstance)
+	}
+	return n, nil
+}
+
+func checkMSTPAddress(s string) error {
+	value := strings.TrimSpace(s)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > MaxMSTPMaster {
+		return fmt.Errorf("%q is not an MS/TP master address between 0 and %d", value, MaxMSTPMaster)
+	}
+	return nil
+}
+
+func checkIPAddress(s string) error {
+	value := strings.TrimSpace(s)
+	addr, err := netip.ParseAddr(value)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("%q is not an IPv4 address", value)
+	}
+	return nil
+}
+
+func checkUDPPort(s string) error {
+	value := strings.TrimSpace(s)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%q is not a UDP port between 1 and 65535", value)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package bacnet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestParseObjectType(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ObjectType
+		wantErr bool
+	}{
+		{in: "analog-input", want: 0},
+		{in: "Analog Input", want: 0},
+		{in: "ANALOG_INPUT", want: 0},
+		{in: " binary-output ", want: 4},
+		{in: "AI", want: 0},
+		{in: "msv", want: 19},
+		{in: "MSI", want: 13},
+		{in: "19", want: 19},
+		{in: "0", want: 0},
+		{in: "64", want: 64},
+		{in: "65", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "analog", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseObjectType(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseObjectType(%q) error = %v, want error %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseObjectType(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestObjectTypeString(t *testing.T) {
+	tests := []struct {
+		in   ObjectType
+		want string
+	}{
+		{0, "analog-input"},
+		{19, "multi-state-value"},
+		{64, "color-temperature"},
+		{65, "object-type-65"},
+		{-1, "object-type--1"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("ObjectType(%d).String() = %q, want %q", int(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		dp     models.DataPoint
+		want   Key
+		wantOK bool
+	}{
+		{
+			name:   "complete",
+			dp:     models.DataPoint{BACnetDeviceInstance: "1001", BACnetObjectType: "AV", BACnetObjectInstance: "3"},
+			want:   Key{Device: 1001, Type: 2, Instance: 3},
+			wantOK: true,
+		},
+		{
+			name:   "padded",
+			dp:     models.DataPoint{BACnetDeviceInstance: " 7 ", BACnetObjectType: "binary value", BACnetObjectInstance: " 0 "},
+			want:   Key{Device: 7, Type: 5, Instance: 0},
+			wantOK: true,
+		},
+		{
+			name: "no device",
+			dp:   models.DataPoint{BACnetObjectType: "AI", BACnetObjectInstance: "1"},
+		},
+		{
+			name: "unknown type",
+			dp:   models.DataPoint{BACnetDeviceInstance: "1", BACnetObjectType: "XY", BACnetObjectInstance: "1"},
+		},
+		{
+			name: "reserved instance",
+			dp:   models.DataPoint{BACnetDeviceInstance: "4194303", BACnetObjectType: "AI", BACnetObjectInstance: "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := KeyOf(tt.dp)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("KeyOf() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		dp   models.DataPoint
+		want []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			dp: models.DataPoint{
+				BACnetObjectType:     "AI",
+				BACnetObjectInstance: "1",
+				BACnetDeviceInstance: "4194302",
+				MSTPaddress:          "127",
+				IPaddress:            "10.0.0.1",
+				UDPport:              "47808",
+			},
+		},
+		{
+			name: "invalid",
+			dp: models.DataPoint{
+				BACnetObjectType:     "AX",
+				BACnetObjectInstance: "-1",
+				BACnetDeviceInstance: "4194303",
+				MSTPaddress:          "128",
+				IPaddress:            "::1",
+				UDPport:              "0",
+			},
+			want: []string{
+				`BACnetObjectType: "AX" is not a standard BACnet object type`,
+				`BACnetObjectInstance: "-1" is not an instance number between 0 and 4194302`,
+				`BACnetDeviceInstance: "4194303" is not an instance number between 0 and 4194302`,
+				`MSTPaddress: "128" is not an MS/TP master address between 0 and 127`,
+				`IPaddress: "::1" is not an IPv4 address`,
+				`UDPport: "0" is not a UDP port between 1 and 65535`,
+			},
+		},
+		{
+			name: "blank is not checked",
+			dp:   models.DataPoint{IPaddress: "  ", UDPport: "70000"},
+			want: []string{`UDPport: "70000" is not a UDP port between 1 and 65535`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.dp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckChanged(t *testing.T) {
+	legacy := models.DataPoint{BACnetObjectType: "AX", MSTPaddress: "128"}
+
+	tests := []struct {
+		name string
+		dp   models.DataPoint
+		want []string
+	}{
+		{
+			name: "untouched legacy values",
+			dp:   models.DataPoint{BACnetObjectType: "AX", MSTPaddress: "128", Descriptor: "Supply"},
+		},
+		{
+			name: "changed to another invalid value",
+			dp:   models.DataPoint{BACnetObjectType: "AX", MSTPaddress: "200"},
+			want: []string{`MSTPaddress: "200" is not an MS/TP master address between 0 and 127`},
+		},
+		{
+			name: "cleared",
+			dp:   models.DataPoint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckChanged(legacy, tt.dp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckChanged() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
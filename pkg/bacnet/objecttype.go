@@ -0,0 +1,151 @@
+package bacnet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// ObjectType is a standard BACnet object type.
+type ObjectType int
+
+// objectTypes lists the standard object types of ANSI/ASHRAE 135 by number.
+var objectTypes = []string{
+	"analog-input",
+	"analog-output",
+	"analog-value",
+	"binary-input",
+	"binary-output",
+	"binary-value",
+	"calendar",
+	"command",
+	"device",
+	"event-enrollment",
+	"file",
+	"group",
+	"loop",
+	"multi-state-input",
+	"multi-state-output",
+	"notification-class",
+	"program",
+	"schedule",
+	"averaging",
+	"multi-state-value",
+	"trend-log",
+	"life-safety-point",
+	"life-safety-zone",
+	"accumulator",
+	"pulse-converter",
+	"event-log",
+	"global-group",
+	"trend-log-multiple",
+	"load-control",
+	"structured-view",
+	"access-door",
+	"timer",
+	"access-credential",
+	"access-point",
+	"access-rights",
+	"access-user",
+	"access-zone",
+	"credential-data-input",
+	"network-security",
+	"bitstring-value",
+	"characterstring-value",
+	"date-pattern-value",
+	"date-value",
+	"datetime-pattern-value",
+	"datetime-value",
+	"integer-value",
+	"large-analog-value",
+	"octetstring-value",
+	"positive-integer-value",
+	"time-pattern-value",
+	"time-value",
+	"notification-forwarder",
+	"alert-enrollment",
+	"channel",
+	"lighting-output",
+	"binary-lighting-output",
+	"network-port",
+	"elevator-group",
+	"escalator",
+	"lift",
+	"staging",
+	"audit-log",
+	"audit-reporter",
+	"color",
+	"color-temperature",
+}
+
+// abbreviations are the short object type names used in point schedules.
+var abbreviations = map[string]ObjectType{
+	"ai":    0,
+	"ao":    1,
+	"av":    2,
+	"bi":    3,
+	"bo":    4,
+	"bv":    5,
+	"cal":   6,
+	"dev":   8,
+	"ee":    9,
+	"lp":    12,
+	"mi":    13,
+	"msi":   13,
+	"mo":    14,
+	"mso":   14,
+	"nc":    15,
+	"prg":   16,
+	"sch":   17,
+	"sched": 17,
+	"mv":    19,
+	"msv":   19,
+	"tl":    20,
+	"acc":   23,
+	"el":    25,
+	"tlm":   27,
+	"iv":    45,
+	"lav":   46,
+	"piv":   48,
+	"np":    56,
+}
+
+// byName maps normalized object type names onto their number.
+var byName = func() map[string]ObjectType {
+	m := make(map[string]ObjectType, len(objectTypes)+len(abbreviations))
+	for i, name := range objectTypes {
+		m[models.Normalize(name)] = ObjectType(i)
+	}
+	for name, t := range abbreviations {
+		m[name] = t
+	}
+	return m
+}()
+
+// String returns the standard name of t, such as "analog-input".
+func (t ObjectType) String() string {
+	if t < 0 || int(t) >= len(objectTypes) {
+		return "object-type-" + strconv.Itoa(int(t))
+	}
+	return objectTypes[t]
+}
+
+// ParseObjectType reads an object type given by standard name, in any case
+// and with or without separators ("analog-input", "Analog Input",
+// "ANALOG_INPUT"), by common abbreviation ("AI", "MSV") or by number.
+func ParseObjectType(s string) (ObjectType, error) {
+	value := strings.TrimSpace(s)
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 0 || n >= len(objectTypes) {
+			return 0, fmt.Errorf("%d is not a standard BACnet object type", n)
+		}
+		return ObjectType(n), nil
+	}
+
+	if t, ok := byName[models.Normalize(value)]; ok {
+		return t, nil
+	}
+	return 0, fmt.Errorf("%q is not a standard BACnet object type", value)
+}
@@ -0,0 +1,211 @@
+// Package brick writes projects as Brick Schema models in RDF Turtle.
+//
+// Brick classes come from a mapping table: equipment classes are picked by
+// matching EquipType, point classes by the Haystack tags of the point.
+package brick
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Mapping is the table of Brick classes.
+type Mapping struct {
+	// Equipment is tried in order; the first pattern found in the EquipType
+	// of a piece of equipment gives its class.
+	Equipment []EquipmentClass `json:"equipment"`
+
+	// Points gives the class of a point carrying all the listed Haystack
+	// tags. The entry with the most tags wins, the earliest among equals.
+	Points []PointClass `json:"points"`
+}
+
+// EquipmentClass maps EquipType values onto a Brick equipment class.
+type EquipmentClass struct {
+	Pattern string `json:"pattern"`
+	Class   string `json:"class"`
+}
+
+// PointClass maps a set of Haystack tags onto a Brick point class.
+type PointClass struct {
+	Tags  []string `json:"tags"`
+	Class string   `json:"class"`
+}
+
+// DefaultMapping is the built-in mapping table.
+var DefaultMapping = Mapping{
+	Equipment: []EquipmentClass{
+		{Pattern: `^AHU`, Class: "AHU"},
+		{Pattern: `^RTU`, Class: "RTU"},
+		{Pattern: `^MAU`, Class: "MAU"},
+		{Pattern: `^VAV`, Class: "VAV"},
+		{Pattern: `^FCU`, Class: "FCU"},
+		{Pattern: `^(CH|CHLR|CHILLER)\b`, Class: "Chiller"},
+		{Pattern: `^(BLR|BOILER)\b`, Class: "Boiler"},
+		{Pattern: `^(P|PUMP|CHWP|CWP|HWP)\b`, Class: "Pump"},
+		{Pattern: `^(CT|COOLING ?TOWER)\b`, Class: "Cooling_Tower"},
+		{Pattern: `^EF\b`, Class: "Exhaust_Fan"},
+		{Pattern: `^SF\b`, Class: "Supply_Fan"},
+		{Pattern: `^RF\b`, Class: "Return_Fan"},
+		{Pattern: `^FAN\b`, Class: "Fan"},
+		{Pattern: `^(MTR|METER)\b`, Class: "Meter"},
+	},
+	Points: []PointClass{
+		{Tags: []string{"discharge", "air", "temp", "sensor"}, Class: "Discharge_Air_Temperature_Sensor"},
+		{Tags: []string{"discharge", "air", "temp", "sp"}, Class: "Discharge_Air_Temperature_Setpoint"},
+		{Tags: []string{"return", "air", "temp", "sensor"}, Class: "Return_Air_Temperature_Sensor"},
+		{Tags: []string{"mixed", "air", "temp", "sensor"}, Class: "Mixed_Air_Temperature_Sensor"},
+		{Tags: []string{"outside", "air", "temp", "sensor"}, Class: "Outside_Air_Temperature_Sensor"},
+		{Tags: []string{"exhaust", "air", "temp", "sensor"}, Class: "Exhaust_Air_Temperature_Sensor"},
+		{Tags: []string{"zone", "air", "temp", "sensor"}, Class: "Zone_Air_Temperature_Sensor"},
+		{Tags: []string{"zone", "air", "temp", "sp"}, Class: "Zone_Air_Temperature_Setpoint"},
+		{Tags: []string{"chilled", "water", "temp", "sensor"}, Class: "Chilled_Water_Temperature_Sensor"},
+		{Tags: []string{"hot", "water", "temp", "sensor"}, Class: "Hot_Water_Temperature_Sensor"},
+		{Tags: []string{"discharge", "air", "pressure", "sensor"}, Class: "Discharge_Air_Static_Pressure_Sensor"},
+		{Tags: []string{"discharge", "air", "flow", "sensor"}, Class: "Discharge_Air_Flow_Sensor"},
+		{Tags: []string{"air", "temp", "sensor"}, Class: "Air_Temperature_Sensor"},
+		{Tags: []string{"water", "temp", "sensor"}, Class: "Water_Temperature_Sensor"},
+		{Tags: []string{"air", "flow", "sensor"}, Class: "Air_Flow_Sensor"},
+		{Tags: []string{"water", "flow", "sensor"}, Class: "Water_Flow_Sensor"},
+		{Tags: []string{"temp", "sensor"}, Class: "Temperature_Sensor"},
+		{Tags: []string{"temp", "sp"}, Class: "Temperature_Setpoint"},
+		{Tags: []string{"humidity", "sensor"}, Class: "Humidity_Sensor"},
+		{Tags: []string{"humidity", "sp"}, Class: "Humidity_Setpoint"},
+		{Tags: []string{"pressure", "sensor"}, Class: "Pressure_Sensor"},
+		{Tags: []string{"pressure", "sp"}, Class: "Pressure_Setpoint"},
+		{Tags: []string{"flow", "sensor"}, Class: "Flow_Sensor"},
+		{Tags: []string{"flow", "sp"}, Class: "Flow_Setpoint"},
+		{Tags: []string{"co2", "sensor"}, Class: "CO2_Sensor"},
+		{Tags: []string{"occ", "sensor"}, Class: "Occupancy_Sensor"},
+		{Tags: []string{"damper", "cmd"}, Class: "Damper_Position_Command"},
+		{Tags: []string{"valve", "cmd"}, Class: "Valve_Command"},
+		{Tags: []string{"fan", "speed", "cmd"}, Class: "Fan_Speed_Command"},
+		{Tags: []string{"speed", "cmd"}, Class: "Speed_Command"},
+		{Tags: []string{"fan", "run", "sensor"}, Class: "Fan_Status"},
+		{Tags: []string{"run", "sensor"}, Class: "Run_Status"},
+		{Tags: []string{"enable", "cmd"}, Class: "Enable_Command"},
+		{Tags: []string{"alarm"}, Class: "Alarm"},
+		{Tags: []string{"sensor"}, Class: "Sensor"},
+		{Tags: []string{"sp"}, Class: "Setpoint"},
+		{Tags: []string{"cmd"}, Class: "Command"},
+	},
+}
+
+// className is the form of a Brick class name.
+var className = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// LoadMapping returns the default mapping extended with the JSON mapping
+// stored at path, whose entries take precedence over the defaults. An empty
+// path returns the defaults.
+func LoadMapping(path string) (*Mapper, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("brick: reading mapping: %w", err)
+	}
+
+	var extra Mapping
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("brick: parsing mapping %s: %w", path, err)
+	}
+
+	return Compile(Mapping{
+		Equipment: append(extra.Equipment, DefaultMapping.Equipment...),
+		Points:    append(extra.Points, DefaultMapping.Points...),
+	})
+}
+
+// Default returns the default mapping compiled.
+func Default() *Mapper {
+	m, err := Compile(DefaultMapping)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Mapper is a compiled mapping table.
+type Mapper struct {
+	equipment []equipmentClass
+	points    []PointClass
+}
+
+type equipmentClass struct {
+	re    *regexp.Regexp
+	class string
+}
+
+// Compile checks the mapping and prepares it for use.
+func Compile(m Mapping) (*Mapper, error) {
+	mapper := &Mapper{}
+
+	for i, e := range m.Equipment {
+		re, err := regexp.Compile("(?i)" + e.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("brick: equipment class %d: %v", i+1, err)
+		}
+		if !className.MatchString(e.Class) {
+			return nil, fmt.Errorf("brick: equipment class %d: %q is not a valid class name", i+1, e.Class)
+		}
+		mapper.equipment = append(mapper.equipment, equipmentClass{re: re, class: e.Class})
+	}
+
+	for i, p := range m.Points {
+		if len(p.Tags) == 0 {
+			return nil, fmt.Errorf("brick: point class %d: tags are required", i+1)
+		}
+		if !className.MatchString(p.Class) {
+			return nil, fmt.Errorf("brick: point class %d: %q is not a valid class name", i+1, p.Class)
+		}
+		mapper.points = append(mapper.points, p)
+	}
+
+	return mapper, nil
+}
+
+// EquipmentClass returns the Brick class of equipment of the given type,
+// Equipment when no entry matches.
+func (m *Mapper) EquipmentClass(equipType string) string {
+	if equipType = strings.TrimSpace(equipType); equipType != "" {
+		for _, e := range m.equipment {
+			if e.re.MatchString(equipType) {
+				return e.class
+			}
+		}
+	}
+	return "Equipment"
+}
+
+// PointClass returns the Brick class of a point with the given Haystack
+// tags, Point when no entry matches.
+func (m *Mapper) PointClass(tags []string) string {
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	class, best := "Point", 0
+	for _, p := range m.points {
+		if len(p.Tags) <= best {
+			continue
+		}
+		all := true
+		for _, tag := range p.Tags {
+			if !has[tag] {
+				all = false
+				break
+			}
+		}
+		if all {
+			class, best = p.Class, len(p.Tags)
+		}
+	}
+
+	return class
+}
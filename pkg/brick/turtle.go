@@ -0,0 +1,132 @@
+package brick
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Namespaces of the vocabularies the model uses.
+const (
+	BrickNamespace  = "https://brickschema.org/schema/Brick#"
+	RefNamespace    = "https://brickschema.org/schema/Brick/ref#"
+	BACnetNamespace = "http://data.ashrae.org/bacnet/2020#"
+	RDFSNamespace   = "http://www.w3.org/2000/01/rdf-schema#"
+)
+
+// Project is what a project's model is built from.
+type Project struct {
+	Project   models.Project
+	Equipment []models.Equipment
+	Points    []models.DataPoint
+	// Tags are the Haystack tags of the points by point ID.
+	Tags map[int][]string
+}
+
+// Write writes the Brick model of a project in Turtle. The project is a
+// brick:Site with a single brick:Building, equipment feeds the equipment
+// naming it in EquipRef, and points carry a BACnet reference when their
+// addressing is complete.
+func (m *Mapper) Write(w io.Writer, p Project) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "@prefix brick: <%s> .\n", BrickNamespace)
+	fmt.Fprintf(b, "@prefix ref: <%s> .\n", RefNamespace)
+	fmt.Fprintf(b, "@prefix bacnet: <%s> .\n", BACnetNamespace)
+	fmt.Fprintf(b, "@prefix rdfs: <%s> .\n", RDFSNamespace)
+	fmt.Fprintf(b, "@prefix : <urn:haver:project:%d#> .\n", p.Project.ID)
+
+	// Equipment by EquipID, with the points and equipment each one has
+	equips := make(map[string]models.Equipment, len(p.Equipment))
+	for _, e := range p.Equipment {
+		equips[strings.ToLower(e.EquipID)] = e
+	}
+	feeds := make(map[int][]string)
+	for _, e := range p.Equipment {
+		if parent, ok := equips[strings.ToLower(e.Parent)]; ok && parent.ID != e.ID {
+			feeds[parent.ID] = append(feeds[parent.ID], equipName(e))
+		}
+	}
+	points := make(map[int][]string)
+	for _, dp := range p.Points {
+		if e, ok := equips[strings.ToLower(strings.TrimSpace(dp.EquipID))]; ok {
+			points[e.ID] = append(points[e.ID], pointName(dp))
+		}
+	}
+
+	site := literal(p.Project.Name)
+	fmt.Fprintf(b, "\n:site a brick:Site ;\n    rdfs:label %s ;\n    brick:hasPart :building .\n", site)
+	fmt.Fprintf(b, "\n:building a brick:Building ;\n    rdfs:label %s ;\n    brick:isPartOf :site .\n", site)
+
+	for _, e := range p.Equipment {
+		fmt.Fprintf(b, "\n%s a brick:%s ;\n    rdfs:label %s ;\n    brick:hasLocation :building",
+			equipName(e), m.EquipmentClass(e.EquipType), literal(e.EquipID))
+		writeObjects(b, "brick:feeds", feeds[e.ID])
+		writeObjects(b, "brick:hasPoint", points[e.ID])
+		b.WriteString(" .\n")
+	}
+
+	devices := make(map[int]bool)
+	for _, dp := range p.Points {
+		fmt.Fprintf(b, "\n%s a brick:%s ;\n    rdfs:label %s", pointName(dp), m.PointClass(p.Tags[dp.ID]), literal(label(dp)))
+		if e, ok := equips[strings.ToLower(strings.TrimSpace(dp.EquipID))]; ok {
+			fmt.Fprintf(b, " ;\n    brick:isPointOf %s", equipName(e))
+		}
+		if key, ok := bacnet.KeyOf(dp); ok {
+			devices[key.Device] = true
+			fmt.Fprintf(b, " ;\n    ref:hasExternalReference [\n        a ref:BACnetReference ;\n        bacnet:object-identifier %s ;\n",
+				literal(key.Type.String()+","+strconv.Itoa(key.Instance)))
+			if name := strings.TrimSpace(dp.BACnetObjectName); name != "" {
+				fmt.Fprintf(b, "        bacnet:object-name %s ;\n", literal(name))
+			}
+			fmt.Fprintf(b, "        bacnet:objectOf %s\n    ]", deviceName(key.Device))
+		}
+		b.WriteString(" .\n")
+	}
+
+	ids := make([]int, 0, len(devices))
+	for id := range devices {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		fmt.Fprintf(b, "\n%s a bacnet:BACnetDevice ;\n    bacnet:device-instance %d .\n", deviceName(id), id)
+	}
+
+	return b.Flush()
+}
+
+// writeObjects writes a predicate with its objects, nothing when there are
+// none.
+func writeObjects(b *bufio.Writer, predicate string, objects []string) {
+	if len(objects) == 0 {
+		return
+	}
+	fmt.Fprintf(b, " ;\n    %s %s", predicate, strings.Join(objects, ", "))
+}
+
+func equipName(e models.Equipment) string  { return ":equip_" + strconv.Itoa(e.ID) }
+func pointName(dp models.DataPoint) string { return ":point_" + strconv.Itoa(dp.ID) }
+func deviceName(instance int) string       { return ":device_" + strconv.Itoa(instance) }
+
+// label is the label of a point.
+func label(dp models.DataPoint) string {
+	for _, s := range []string{dp.PointName, dp.Descriptor, dp.BACnetObjectName} {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return "Point " + strconv.Itoa(dp.ID)
+}
+
+// literal returns s as a Turtle string literal.
+func literal(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
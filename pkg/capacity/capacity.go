@@ -0,0 +1,220 @@
+// Package capacity estimates the load the trends and COV points of a project
+// put on its field panels and the trunks connecting them.
+//
+// Points are grouped by panel, their DeviceSysName or NodeIdentifier, and by
+// trunk, their BLNSysName and MSTPnetwork. Every interval sample and every
+// change of value report is counted as a message on the trunks of the point;
+// samples buffered in a panel take up its trend memory.
+package capacity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/trends"
+)
+
+// The trunk types.
+const (
+	TrunkBLN  = "BLN"
+	TrunkMSTP = "MS/TP"
+)
+
+// Thresholds are the assumptions of the estimate and the capacities load is
+// checked against.
+type Thresholds struct {
+	// PanelSamples is the number of trend samples a panel buffers, the
+	// PanelCapacity of the trend limits.
+	PanelSamples int `json:"panelSamples"`
+	// PanelBytes is the trend memory of a panel.
+	PanelBytes int `json:"panelBytes"`
+	// BytesPerSample is the memory a buffered sample takes.
+	BytesPerSample int `json:"bytesPerSample"`
+	// COVPerHour is the number of changes of value a COV point reports an
+	// hour.
+	COVPerHour float64 `json:"covPerHour"`
+	// BLNMessages and MSTPMessages are the messages per second a trunk
+	// carries.
+	BLNMessages  float64 `json:"blnMessages"`
+	MSTPMessages float64 `json:"mstpMessages"`
+}
+
+// DefaultThresholds are the thresholds of a typical installation.
+var DefaultThresholds = Thresholds{
+	PanelSamples:   trends.DefaultLimits.PanelCapacity,
+	PanelBytes:     2 << 20,
+	BytesPerSample: 10,
+	COVPerHour:     6,
+	BLNMessages:    50,
+	MSTPMessages:   10,
+}
+
+// Load is the estimated load of a group of points.
+type Load struct {
+	Points        int     `json:"points"`
+	TrendedPoints int     `json:"trendedPoints"`
+	COVPoints     int     `json:"covPoints"`
+	SamplesPerDay float64 `json:"samplesPerDay"`
+	// MessagesPerSecond counts interval samples and COV reports.
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+}
+
+// Node is the load of a field panel.
+type Node struct {
+	// Panel is "" for the points assigned to no panel.
+	Panel string `json:"panel"`
+	Load
+	BufferedSamples int      `json:"bufferedSamples"`
+	StorageBytes    int      `json:"storageBytes"`
+	Exceeded        []string `json:"exceeded"`
+}
+
+// Trunk is the load of a BLN or MS/TP trunk.
+type Trunk struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Load
+	Panels   int      `json:"panels"`
+	Capacity float64  `json:"capacity"`
+	Exceeded []string `json:"exceeded"`
+}
+
+// Report is the load estimate of a project.
+type Report struct {
+	Thresholds Thresholds `json:"thresholds"`
+	Total      Load       `json:"total"`
+	Nodes      []Node     `json:"nodes"`
+	Trunks     []Trunk    `json:"trunks"`
+	// Overloaded is the number of nodes and trunks exceeding a threshold.
+	Overloaded int `json:"overloaded"`
+}
+
+// Estimate computes the load of points. Panels are those of the trend
+// summary, so both agree on the points, samples and capacity of a panel.
+func Estimate(points []models.DataPoint, th Thresholds) Report {
+	report := Report{Thresholds: th, Nodes: []Node{}, Trunks: []Trunk{}}
+
+	limits := trends.DefaultLimits
+	limits.PanelCapacity = th.PanelSamples
+	summary := trends.Summarize(points, limits)
+
+	for _, p := range summary.Panels {
+		n := Node{
+			Panel:           p.Panel,
+			Load:            newLoad(p.Points, p.TrendedPoints, p.COVPoints, p.SamplesPerDay, th),
+			BufferedSamples: p.PanelSamples,
+			StorageBytes:    p.PanelSamples * th.BytesPerSample,
+			Exceeded:        []string{},
+		}
+		if p.OverCapacity {
+			n.Exceeded = append(n.Exceeded, fmt.Sprintf("%d samples buffered, the panel holds %d", p.PanelSamples, p.Capacity))
+		}
+		if th.PanelBytes > 0 && n.StorageBytes > th.PanelBytes {
+			n.Exceeded = append(n.Exceeded, fmt.Sprintf("%d bytes of trend memory used, the panel has %d", n.StorageBytes, th.PanelBytes))
+		}
+		report.Total.add(n.Load)
+		n.round()
+		if len(n.Exceeded) > 0 {
+			report.Overloaded++
+		}
+		report.Nodes = append(report.Nodes, n)
+	}
+
+	trunks := make(map[string]*Trunk)
+	trunkPanels := make(map[string]map[string]bool)
+	for _, dp := range points {
+		cfg, _ := trends.Parse(dp)
+		pl := trends.PointLoad(cfg)
+		l := newLoad(1, count(pl.Trended), count(pl.COV), pl.SamplesPerDay, th)
+
+		for _, t := range pointTrunks(dp) {
+			key := t.Type + "\x00" + strings.ToLower(t.Name)
+			trunk, ok := trunks[key]
+			if !ok {
+				trunk = &Trunk{Type: t.Type, Name: t.Name, Exceeded: []string{}}
+				trunks[key] = trunk
+				trunkPanels[key] = make(map[string]bool)
+			}
+			trunk.add(l)
+			if name := trends.Panel(dp); name != "" {
+				trunkPanels[key][strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	for key, t := range trunks {
+		t.Panels = len(trunkPanels[key])
+		t.Capacity = th.MSTPMessages
+		if t.Type == TrunkBLN {
+			t.Capacity = th.BLNMessages
+		}
+		if t.Capacity > 0 && t.MessagesPerSecond > t.Capacity {
+			t.Exceeded = append(t.Exceeded, fmt.Sprintf("%.4g messages per second, the trunk carries %g", t.MessagesPerSecond, t.Capacity))
+		}
+		t.round()
+		if len(t.Exceeded) > 0 {
+			report.Overloaded++
+		}
+		report.Trunks = append(report.Trunks, *t)
+	}
+	report.Total.round()
+
+	sort.Slice(report.Trunks, func(i, j int) bool {
+		a, b := report.Trunks[i], report.Trunks[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	return report
+}
+
+// newLoad returns the load of points, trended of which are trended and cov
+// report changes of value, taking intervalSamples interval samples a day.
+func newLoad(points, trended, cov int, intervalSamples float64, th Thresholds) Load {
+	l := Load{
+		Points:        points,
+		TrendedPoints: trended,
+		COVPoints:     cov,
+		SamplesPerDay: intervalSamples + float64(cov)*th.COVPerHour*24,
+	}
+	l.MessagesPerSecond = l.SamplesPerDay / 86400
+	return l
+}
+
+func count(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// pointTrunks returns the trunks a point communicates over.
+func pointTrunks(dp models.DataPoint) []Trunk {
+	var trunks []Trunk
+	if name := strings.TrimSpace(dp.BLNSysName); name != "" {
+		trunks = append(trunks, Trunk{Type: TrunkBLN, Name: name})
+	}
+	if name := strings.TrimSpace(dp.MSTPnetwork); name != "" {
+		trunks = append(trunks, Trunk{Type: TrunkMSTP, Name: name})
+	}
+	return trunks
+}
+
+func (l *Load) add(o Load) {
+	l.Points += o.Points
+	l.TrendedPoints += o.TrendedPoints
+	l.COVPoints += o.COVPoints
+	l.SamplesPerDay += o.SamplesPerDay
+	l.MessagesPerSecond += o.MessagesPerSecond
+}
+
+// round rounds the rates to four decimals for display.
+func (l *Load) round() {
+	l.SamplesPerDay = math.Round(l.SamplesPerDay*1e4) / 1e4
+	l.MessagesPerSecond = math.Round(l.MessagesPerSecond*1e4) / 1e4
+}
@@ -0,0 +1,114 @@
+package capacity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/trends"
+)
+
+func TestEstimate(t *testing.T) {
+	th := Thresholds{
+		PanelSamples:   1000,
+		PanelBytes:     5000,
+		BytesPerSample: 10,
+		COVPerHour:     1,
+		BLNMessages:    1,
+		MSTPMessages:   0.01,
+	}
+	points := []models.DataPoint{
+		// 96 interval samples a day, 672 buffered
+		{ID: 1, DeviceSysName: "PXC-1", BLNSysName: "BLN1", TrendInterval1: "00:15:00", PanelSamples1: "672"},
+		// 24 COV samples a day, 500 buffered
+		{ID: 2, DeviceSysName: "pxc-1", BLNSysName: "BLN1", MSTPnetwork: "FLN1", COVTrend: "Yes", PanelSamples: "500"},
+		// 7 interval samples a day, which integer division used to lose
+		{ID: 3, DeviceSysName: "PXC-2", BLNSysName: "BLN1", TrendInterval1: "7", PanelSamples1: "10"},
+		{ID: 4},
+	}
+
+	report := Estimate(points, th)
+
+	wantNodes := []Node{
+		{
+			Panel:           "PXC-1",
+			Load:            Load{Points: 2, TrendedPoints: 2, COVPoints: 1, SamplesPerDay: 120, MessagesPerSecond: 0.0014},
+			BufferedSamples: 1172,
+			StorageBytes:    11720,
+			Exceeded: []string{
+				"1172 samples buffered, the panel holds 1000",
+				"11720 bytes of trend memory used, the panel has 5000",
+			},
+		},
+		{
+			Panel:           "PXC-2",
+			Load:            Load{Points: 1, TrendedPoints: 1, SamplesPerDay: 205.7143, MessagesPerSecond: 0.0024},
+			BufferedSamples: 10,
+			StorageBytes:    100,
+			Exceeded:        []string{},
+		},
+		{
+			Load:     Load{Points: 1},
+			Exceeded: []string{},
+		},
+	}
+	if !reflect.DeepEqual(report.Nodes, wantNodes) {
+		t.Errorf("Nodes = %+v, want %+v", report.Nodes, wantNodes)
+	}
+
+	wantTrunks := []Trunk{
+		{
+			Type:     TrunkBLN,
+			Name:     "BLN1",
+			Load:     Load{Points: 3, TrendedPoints: 3, COVPoints: 1, SamplesPerDay: 325.7143, MessagesPerSecond: 0.0038},
+			Panels:   2,
+			Capacity: 1,
+			Exceeded: []string{},
+		},
+		{
+			Type:     TrunkMSTP,
+			Name:     "FLN1",
+			Load:     Load{Points: 1, TrendedPoints: 1, COVPoints: 1, SamplesPerDay: 24, MessagesPerSecond: 0.0003},
+			Panels:   1,
+			Capacity: 0.01,
+			Exceeded: []string{},
+		},
+	}
+	if !reflect.DeepEqual(report.Trunks, wantTrunks) {
+		t.Errorf("Trunks = %+v, want %+v", report.Trunks, wantTrunks)
+	}
+
+	wantTotal := Load{Points: 4, TrendedPoints: 3, COVPoints: 1, SamplesPerDay: 325.7143, MessagesPerSecond: 0.0038}
+	if report.Total != wantTotal {
+		t.Errorf("Total = %+v, want %+v", report.Total, wantTotal)
+	}
+	if report.Overloaded != 1 {
+		t.Errorf("Overloaded = %d, want 1", report.Overloaded)
+	}
+}
+
+func TestEstimateAgreesWithSummary(t *testing.T) {
+	points := []models.DataPoint{
+		{DeviceSysName: "PXC-1", TrendInterval1: "7", PanelSamples1: "100", TrendInterval2: "00:00:13", PanelSamples2: "150"},
+		{DeviceSysName: "PXC-1", COVTrend: "Yes", PanelSamples: "300"},
+		{DeviceSysName: "PXC-2", TrendInterval1: "00:15:00", PanelSamples1: "900"},
+	}
+	th := DefaultThresholds
+	th.PanelSamples = 500
+	th.COVPerHour = 0
+
+	limits := trends.DefaultLimits
+	limits.PanelCapacity = th.PanelSamples
+	summary := trends.Summarize(points, limits)
+	report := Estimate(points, th)
+
+	if len(report.Nodes) != len(summary.Panels) {
+		t.Fatalf("%d nodes, %d panels", len(report.Nodes), len(summary.Panels))
+	}
+	for i, p := range summary.Panels {
+		n := report.Nodes[i]
+		if n.Panel != p.Panel || n.BufferedSamples != p.PanelSamples || n.SamplesPerDay != p.SamplesPerDay || (len(n.Exceeded) > 0) != p.OverCapacity {
+			t.Errorf("node %+v disagrees with panel %+v", n, p)
+		}
+	}
+}
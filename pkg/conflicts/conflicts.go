@@ -0,0 +1,258 @@
+// Package conflicts finds point definitions of a project that clash with
+// each other, the errors that otherwise only show up during commissioning.
+package conflicts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/topology"
+	"github.com/pufington-pixie/haver/pkg/trends"
+)
+
+// The conflict categories.
+const (
+	CategoryPointName      = "duplicate-point-name"
+	CategoryBACnetObject   = "duplicate-bacnet-object"
+	CategoryDeviceInstance = "shared-device-instance"
+	CategoryMSTPAddress    = "shared-mstp-address"
+	CategoryDeviceName     = "shared-device-name"
+)
+
+// Conflict is a group of points that clash. Key is what they share; Values
+// lists the distinct devices sharing it, for the device categories.
+type Conflict struct {
+	Key      string   `json:"key"`
+	Values   []string `json:"values,omitempty"`
+	PointIDs []int    `json:"pointIds"`
+}
+
+// Category holds the conflicts of one kind.
+type Category struct {
+	Name        string     `json:"category"`
+	Description string     `json:"description"`
+	Conflicts   []Conflict `json:"conflicts"`
+}
+
+// Report is the outcome of a project analysis. Every category is listed,
+// including the ones without conflicts.
+type Report struct {
+	Points     int        `json:"points"`
+	Total      int        `json:"total"`
+	Categories []Category `json:"categories"`
+}
+
+// Find analyses the points of a project.
+func Find(points []models.DataPoint) Report {
+	report := Report{
+		Points: len(points),
+		Categories: []Category{
+			{
+				Name:        CategoryPointName,
+				Description: "Points of the same equipment sharing a point name",
+				Conflicts:   pointNames(points),
+			},
+			{
+				Name:        CategoryBACnetObject,
+				Description: "Points addressing the same BACnet object",
+				Conflicts:   bacnetObjects(points),
+			},
+			{
+				Name:        CategoryDeviceInstance,
+				Description: "Different BACnet devices sharing a device instance on one network",
+				Conflicts:   deviceInstances(points),
+			},
+			{
+				Name:        CategoryMSTPAddress,
+				Description: "Different MS/TP devices sharing a MAC address on one trunk",
+				Conflicts:   mstpAddresses(points),
+			},
+			{
+				Name:        CategoryDeviceName,
+				Description: "Field devices named like a panel, which the topology takes for the panel",
+				Conflicts:   deviceNames(points),
+			},
+		},
+	}
+
+	for _, c := range report.Categories {
+		report.Total += len(c.Conflicts)
+	}
+	return report
+}
+
+// pointNames groups points by EquipID and PointName, ignoring case.
+func pointNames(points []models.DataPoint) []Conflict {
+	groups := newGroups()
+	for _, dp := range points {
+		equip, name := strings.TrimSpace(dp.EquipID), strings.TrimSpace(dp.PointName)
+		if name == "" {
+			continue
+		}
+		// Neither column holds a NUL, so the id cannot be spelled two ways
+		id := strings.ToLower(equip + "\x00" + name)
+		groups.add(id, fmt.Sprintf("%s %s", equip, name), "", dp.ID)
+	}
+	return groups.conflicts(false)
+}
+
+// bacnetObjects groups points by the BACnet object they address.
+func bacnetObjects(points []models.DataPoint) []Conflict {
+	groups := newGroups()
+	for _, dp := range points {
+		if key, ok := bacnet.KeyOf(dp); ok {
+			groups.add(key.String(), key.String(), "", dp.ID)
+		}
+	}
+	return groups.conflicts(false)
+}
+
+// deviceInstances groups points by network and device instance, and reports
+// the groups that span more than one device.
+func deviceInstances(points []models.DataPoint) []Conflict {
+	groups := newGroups()
+	for _, dp := range points {
+		instance := strings.TrimSpace(dp.BACnetDeviceInstance)
+		device := deviceAddress(dp)
+		if instance == "" || device == "" {
+			continue
+		}
+		network := "local"
+		if dp.BACnetNetwork != nil {
+			network = fmt.Sprint(*dp.BACnetNetwork)
+		}
+		key := fmt.Sprintf("network %s, device %s", network, instance)
+		groups.add(key, key, device, dp.ID)
+	}
+	return groups.conflicts(true)
+}
+
+// mstpAddresses groups points by MS/TP trunk and MAC address, and reports
+// the groups that span more than one device. A point without MSTPnetwork is
+// on the trunk of its panel, as in the topology.
+func mstpAddresses(points []models.DataPoint) []Conflict {
+	groups := newGroups()
+	for _, dp := range points {
+		mac := strings.TrimSpace(dp.MSTPaddress)
+		device := deviceAddress(dp)
+		if mac == "" || device == "" {
+			continue
+		}
+		trunk := strings.TrimSpace(dp.MSTPnetwork)
+		if panel := trends.Panel(dp); trunk == "" && panel != "" {
+			trunk = panel + " MS/TP"
+		}
+		key := fmt.Sprintf("trunk %s, MAC %s", trunk, mac)
+		groups.add(strings.ToLower(key), key, device, dp.ID)
+	}
+	return groups.conflicts(true)
+}
+
+// deviceNames groups points by the name of their panel and of their field
+// device, and reports the names used for both.
+func deviceNames(points []models.DataPoint) []Conflict {
+	groups := newGroups()
+	for _, dp := range points {
+		if panel := trends.Panel(dp); panel != "" {
+			groups.add(strings.ToLower(panel), panel, models.DevicePanel, dp.ID)
+		}
+		if field := strings.TrimSpace(dp.FLNdeviceSysName); field != "" {
+			groups.add(strings.ToLower(field), field, models.DeviceField, dp.ID)
+		}
+	}
+	return groups.conflicts(true)
+}
+
+// defaultUDPPort is the BACnet/IP port a device listens on when the point
+// list leaves UDPport empty.
+const defaultUDPPort = "47808"
+
+// deviceAddress identifies the device holding a point: the device it is
+// named after, or failing that its IP address and port or its MS/TP trunk and
+// MAC address. Points of one device that spell its address differently, or
+// only some of which carry it, thereby still count as one device.
+func deviceAddress(dp models.DataPoint) string {
+	if owner := topology.Owner(dp); owner != "" {
+		return owner
+	}
+	if ip := strings.TrimSpace(dp.IPaddress); ip != "" {
+		port := strings.TrimSpace(dp.UDPport)
+		if port == "" {
+			port = defaultUDPPort
+		}
+		return fmt.Sprintf("%s:%s", ip, port)
+	}
+	if mac := strings.TrimSpace(dp.MSTPaddress); mac != "" {
+		return fmt.Sprintf("MS/TP %s:%s", strings.TrimSpace(dp.MSTPnetwork), mac)
+	}
+	return ""
+}
+
+// group is a set of points sharing a key.
+type group struct {
+	key    string
+	values []string
+	ids    []int
+}
+
+// groups collects points by key, in the order the keys are first seen.
+type groups struct {
+	order []string
+	byKey map[string]*group
+}
+
+func newGroups() *groups {
+	return &groups{byKey: make(map[string]*group)}
+}
+
+// add puts a point in the group of id. value is the device the point belongs
+// to, for groups that only conflict across devices.
+func (g *groups) add(id, key, value string, pointID int) {
+	grp, ok := g.byKey[id]
+	if !ok {
+		grp = &group{key: key}
+		g.byKey[id] = grp
+		g.order = append(g.order, id)
+	}
+	// A point may name the same device twice
+	if n := len(grp.ids); n == 0 || grp.ids[n-1] != pointID {
+		grp.ids = append(grp.ids, pointID)
+	}
+	if value != "" && !contains(grp.values, value) {
+		grp.values = append(grp.values, value)
+	}
+}
+
+// conflicts returns the groups of more than one point, or of more than one
+// device when byValue is set, sorted by key.
+func (g *groups) conflicts(byValue bool) []Conflict {
+	out := []Conflict{}
+	for _, id := range g.order {
+		grp := g.byKey[id]
+		if byValue && len(grp.values) < 2 || !byValue && len(grp.ids) < 2 {
+			continue
+		}
+		c := Conflict{Key: grp.key, PointIDs: grp.ids}
+		if byValue {
+			c.Values = grp.values
+		}
+		out = append(out, c)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Key) < strings.ToLower(out[j].Key)
+	})
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package conflicts
+
+import (
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestDeviceInstances(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []models.DataPoint
+		want   int
+	}{
+		{
+			name: "one device, port left out on some points",
+			points: []models.DataPoint{
+				{ID: 1, BACnetDeviceInstance: "100", IPaddress: "10.0.0.1", UDPport: "47808"},
+				{ID: 2, BACnetDeviceInstance: "100", IPaddress: "10.0.0.1"},
+			},
+		},
+		{
+			name: "one named device, addressed by IP and by MS/TP",
+			points: []models.DataPoint{
+				{ID: 1, BACnetDeviceInstance: "100", DeviceSysName: "PXC-1", IPaddress: "10.0.0.1"},
+				{ID: 2, BACnetDeviceInstance: "100", DeviceSysName: "pxc-1", MSTPnetwork: "1", MSTPaddress: "5"},
+			},
+		},
+		{
+			name: "two named devices",
+			points: []models.DataPoint{
+				{ID: 1, BACnetDeviceInstance: "100", DeviceSysName: "PXC-1"},
+				{ID: 2, BACnetDeviceInstance: "100", DeviceSysName: "PXC-2"},
+			},
+			want: 1,
+		},
+		{
+			name: "two ports",
+			points: []models.DataPoint{
+				{ID: 1, BACnetDeviceInstance: "100", IPaddress: "10.0.0.1", UDPport: "47808"},
+				{ID: 2, BACnetDeviceInstance: "100", IPaddress: "10.0.0.1", UDPport: "47809"},
+			},
+			want: 1,
+		},
+		{
+			name: "two networks",
+			points: []models.DataPoint{
+				{ID: 1, BACnetDeviceInstance: "100", IPaddress: "10.0.0.1", BACnetNetwork: intPtr(1)},
+				{ID: 2, BACnetDeviceInstance: "100", IPaddress: "10.0.0.2", BACnetNetwork: intPtr(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deviceInstances(tt.points); len(got) != tt.want {
+				t.Errorf("deviceInstances() = %+v, want %d conflicts", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestDeviceNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []models.DataPoint
+		want   int
+	}{
+		{
+			name: "field devices of a panel",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1"},
+				{ID: 2, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1"},
+			},
+		},
+		{
+			name: "field device named like another panel",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "pxc-2"},
+				{ID: 2, DeviceSysName: "PXC-2"},
+			},
+			want: 1,
+		},
+		{
+			name:   "field device named like its own panel",
+			points: []models.DataPoint{{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "PXC-1"}},
+			want:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deviceNames(tt.points); len(got) != tt.want {
+				t.Errorf("deviceNames() = %+v, want %d conflicts", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []models.DataPoint
+		want   int
+	}{
+		{
+			name: "same name ignoring case",
+			points: []models.DataPoint{
+				{ID: 1, EquipID: "AHU-1", PointName: "SAT"},
+				{ID: 2, EquipID: "ahu-1", PointName: " sat"},
+			},
+			want: 1,
+		},
+		{
+			name: "same text split differently",
+			points: []models.DataPoint{
+				{ID: 1, EquipID: "AHU 1", PointName: "SAT"},
+				{ID: 2, EquipID: "AHU", PointName: "1 SAT"},
+			},
+		},
+		{
+			name: "other equipment",
+			points: []models.DataPoint{
+				{ID: 1, EquipID: "AHU-1", PointName: "SAT"},
+				{ID: 2, EquipID: "AHU-2", PointName: "SAT"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointNames(tt.points); len(got) != tt.want {
+				t.Errorf("pointNames() = %+v, want %d conflicts", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMSTPAddresses(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []models.DataPoint
+		want   int
+	}{
+		{
+			name: "two field devices of a panel",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1", MSTPaddress: "5"},
+				{ID: 2, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-2", MSTPaddress: "5"},
+			},
+			want: 1,
+		},
+		{
+			name: "one device, instance left out on some points",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1", MSTPaddress: "5", BACnetDeviceInstance: "105"},
+				{ID: 2, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1", MSTPaddress: "5"},
+			},
+		},
+		{
+			name: "local trunks of two panels",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1", MSTPaddress: "5"},
+				{ID: 2, DeviceSysName: "PXC-2", FLNdeviceSysName: "TEC-2", MSTPaddress: "5"},
+			},
+		},
+		{
+			name: "named trunk shared by two panels",
+			points: []models.DataPoint{
+				{ID: 1, DeviceSysName: "PXC-1", FLNdeviceSysName: "TEC-1", MSTPnetwork: "1", MSTPaddress: "5"},
+				{ID: 2, DeviceSysName: "PXC-2", FLNdeviceSysName: "TEC-2", MSTPnetwork: "1", MSTPaddress: "5"},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mstpAddresses(tt.points); len(got) != tt.want {
+				t.Errorf("mstpAddresses() = %+v, want %d conflicts", got, tt.want)
+			}
+		})
+	}
+}
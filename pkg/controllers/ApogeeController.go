@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/exporters"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// ExportApogee writes the datapoints of a project as Apogee point
+// definitions.
+// @Summary Export a project as Apogee point definitions
+// @Description Export the datapoints of a project for import into Apogee/Desigo: CSV with one row per point, or text with one POINT block per point.
+// @Description Point types, engineering units and sensor types are translated; points that cannot be expressed are left out. GET /api/projects/{id}/export/apogee/report tells which and why.
+// @Tags apogee
+// @Produce text/csv
+// @Produce text/plain
+// @Param id path int true "Project ID"
+// @Param format query string false "csv (default) or txt"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/export/apogee [get]
+func (c *Controller) ExportApogee(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exporters.ApogeeCSV
+	}
+	if format != exporters.ApogeeCSV && format != exporters.ApogeeText {
+		utils.HandleError(w, nil, http.StatusBadRequest, "format must be csv or txt")
+		return
+	}
+
+	points, _, ok := c.apogee(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == exporters.ApogeeText {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="project-%d-apogee.%s"`, projectID, format))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if err := exporters.WriteApogee(w, format, points); err != nil {
+		log.Println("export:", err)
+	}
+}
+
+// GetApogeeReport tells which datapoints an Apogee export leaves out or
+// changes.
+// @Summary Check a project against Apogee
+// @Description List the datapoints of a project an Apogee export skips, and the values it drops or shortens, such as units without an Apogee spelling or descriptors over 16 characters
+// @Tags apogee
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=exporters.Report}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/export/apogee/report [get]
+func (c *Controller) GetApogeeReport(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	_, report, ok := c.apogee(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    report,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// apogee translates the datapoints of a project. It writes the error
+// response and returns false when that fails.
+func (c *Controller) apogee(w http.ResponseWriter, r *http.Request, projectID int) ([]exporters.ApogeePoint, exporters.Report, bool) {
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, exporters.Report{}, false
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, exporters.Report{}, false
+	}
+	catalogue, err := c.unitCatalogue(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, exporters.Report{}, false
+	}
+
+	list, report := exporters.Apogee(points, catalogue)
+	return list, report, true
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/brick"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// ExportBrick writes a project as a Brick model.
+// @Summary Export a project as a Brick model
+// @Description Export a project as Brick Schema RDF in Turtle. The project is a brick:Site with a brick:Building; equipment takes its class from EquipType and brick:feeds the equipment naming it in EquipRef.
+// @Description Points take their class from their Haystack tags and carry a BACnet reference when their device, object type and instance are set.
+// @Tags brick
+// @Produce text/turtle
+// @Param id path int true "Project ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/export/brick [get]
+func (c *Controller) ExportBrick(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	project, err := c.store.Projects.Get(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	tagger, ok := c.tagger(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	equipment, err := c.store.Equipment.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	overrides, err := c.pointOverrides(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	tags := make(map[int][]string, len(points))
+	for _, dp := range points {
+		tags[dp.ID] = tagPoint(tagger, dp, overrides[dp.ID]).Tags
+	}
+
+	w.Header().Set("Content-Type", "text/turtle; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="project-%d-brick.ttl"`, projectID))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	err = c.brick.Write(w, brick.Project{
+		Project:   project,
+		Equipment: equipment,
+		Points:    points,
+		Tags:      tags,
+	})
+	if err != nil {
+		log.Println("export:", err)
+	}
+}
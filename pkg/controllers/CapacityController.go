@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/capacity"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// GetCapacity estimates the load of a project's panels and trunks.
+// @Summary Estimate the panel and trunk load of a project
+// @Description Group the datapoints of a project by panel (DeviceSysName, or NodeIdentifier) and by trunk (BLNSysName and MSTPnetwork) and estimate the samples per day, buffered samples, trend memory and messages per second of each from the trend and COV columns.
+// @Description Panels and trunks over a threshold list what they exceed. The thresholds have defaults and can be set per request.
+// @Tags trends
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param panelSamples query int false "Trend samples a panel buffers"
+// @Param panelBytes query int false "Trend memory of a panel in bytes"
+// @Param bytesPerSample query int false "Memory a buffered sample takes"
+// @Param covPerHour query number false "Changes of value a COV point reports an hour"
+// @Param blnMessages query number false "Messages per second a BLN trunk carries"
+// @Param mstpMessages query number false "Messages per second an MS/TP trunk carries"
+// @Success 200 {object} models.Response{data=capacity.Report}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/capacity [get]
+func (c *Controller) GetCapacity(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	th, err := parseThresholds(r.URL.Query())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    capacity.Estimate(points, th),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// parseThresholds reads the thresholds set in the query string over the
+// defaults.
+func parseThresholds(values url.Values) (capacity.Thresholds, error) {
+	th := capacity.DefaultThresholds
+
+	ints := []struct {
+		name string
+		v    *int
+	}{
+		{"panelSamples", &th.PanelSamples},
+		{"panelBytes", &th.PanelBytes},
+		{"bytesPerSample", &th.BytesPerSample},
+	}
+	for _, p := range ints {
+		if raw := values.Get(p.name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				return th, fmt.Errorf("%s must be a positive whole number", p.name)
+			}
+			*p.v = n
+		}
+	}
+
+	floats := []struct {
+		name string
+		v    *float64
+	}{
+		{"covPerHour", &th.COVPerHour},
+		{"blnMessages", &th.BLNMessages},
+		{"mstpMessages", &th.MSTPMessages},
+	}
+	for _, p := range floats {
+		if raw := values.Get(p.name); raw != "" {
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+				return th, fmt.Errorf("%s must be a positive number", p.name)
+			}
+			*p.v = f
+		}
+	}
+
+	return th, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/conflicts"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// GetConflicts analyses the point list of a project for conflicts.
+// @Summary Get the conflicts of a project
+// @Description Analyse every datapoint of a project and return the conflicting point definitions grouped by category:
+// @Description points sharing an EquipID and PointName, points addressing the same BACnet object, BACnet devices sharing a device instance on one network, MS/TP devices sharing a MAC address on one trunk and field devices named like a panel.
+// @Tags datapoints
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=conflicts.Report}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/conflicts [get]
+func (c *Controller) GetConflicts(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    conflicts.Find(points),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
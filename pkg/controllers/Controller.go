@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pufington-pixie/haver/pkg/brick"
+	"github.com/pufington-pixie/haver/pkg/equipment"
+	"github.com/pufington-pixie/haver/pkg/imports"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/topology"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// Options configures a Controller.
+type Options struct {
+	// Imports runs point list uploads. An import service with default
+	// options is started when nil; it is not recovered or shut down, so
+	// servers should pass their own.
+	Imports *imports.Service
+
+	// Brick maps equipment and points onto Brick classes. The default
+	// mapping is used when nil.
+	Brick *brick.Mapper
+}
+
+// Controller holds the dependencies shared by the HTTP handlers.
+type Controller struct {
+	store   store.Store
+	imports *imports.Service
+	brick   *brick.Mapper
+}
+
+// New returns a Controller that serves requests from the given store.
+func New(s store.Store, opts Options) *Controller {
+	if opts.Imports == nil {
+		// Uploads queued on an unstarted service would never run
+		opts.Imports = imports.NewService(s, imports.Options{})
+		opts.Imports.Start()
+	}
+	if opts.Brick == nil {
+		opts.Brick = brick.Default()
+	}
+	return &Controller{store: s, imports: opts.Imports, brick: opts.Brick}
+}
+
+// syncDerived brings the equipment, networks and devices of a project in
+// step with its datapoints after an edit. The edit has been stored already,
+// so a failure is only logged.
+func (c *Controller) syncDerived(ctx context.Context, projectID int) {
+	if err := equipment.Sync(ctx, c.store, projectID); err != nil {
+		log.Printf("equipment: updating project %d: %v", projectID, err)
+	}
+	if err := topology.Sync(ctx, c.store, projectID); err != nil {
+		log.Printf("topology: updating project %d: %v", projectID, err)
+	}
+}
+
+// handleStoreError writes the response for an error returned by the store,
+// using notFound as the message when the record does not exist.
+func handleStoreError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		utils.HandleError(w, err, http.StatusNotFound, notFound)
+	case errors.Is(err, store.ErrConflict):
+		utils.HandleError(w, err, http.StatusConflict, "Conflict")
+	default:
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+	}
+}
@@ -0,0 +1,307 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// pointIDs reads the project and datapoint IDs from the path.
+func pointIDs(r *http.Request) (projectID, pointID int, err error) {
+	projectID, err = strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	pointID, err = strconv.Atoi(chi.URLParam(r, "pointId"))
+	return projectID, pointID, err
+}
+
+// GetDataPoint returns a single datapoint.
+// @Summary Get a datapoint by ID
+// @Description Get a datapoint of a project with all of its columns
+// @Tags datapoints
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Success 200 {object} models.Response{data=models.DataPoint}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId} [get]
+func (c *Controller) GetDataPoint(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	dp, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    dp,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// CreateDataPoint adds a datapoint to a project.
+// @Summary Create a datapoint
+// @Description Add a single datapoint to a project's point list
+// @Description The BACnet addressing is validated and must not repeat the object of another point of the project. Trend intervals and sample counts must fit a field panel.
+// @Tags datapoints
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param datapoint body models.DataPoint true "Datapoint to be created"
+// @Success 201 {object} models.Response{data=models.DataPoint}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints [post]
+func (c *Controller) CreateDataPoint(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var dp models.DataPoint
+	if err := json.NewDecoder(r.Body).Decode(&dp); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	dp.ID = 0
+	dp.ProjectID = projectID
+	if dp.IncludedInProject == nil {
+		// Same default as the datapoints table
+		included := true
+		dp.IncludedInProject = &included
+	}
+
+	if err := dp.Validate(); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !checkTrends(w, models.DataPoint{}, dp) || !c.checkAddress(w, r, models.DataPoint{}, dp) {
+		return
+	}
+	if err := c.mapUnit(r.Context(), models.DataPoint{}, &dp); err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	if err := c.store.DataPoints.Create(r.Context(), &dp); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+	c.syncDerived(r.Context(), projectID)
+
+	response := models.Response{
+		Status:  http.StatusCreated,
+		Message: "Datapoint created successfully",
+		Data:    dp,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusCreated)
+}
+
+// UpdateDataPoint replaces a datapoint.
+// @Summary Update a datapoint
+// @Description Replace every column of a datapoint; columns missing from the body are cleared
+// @Description Trend and BACnet addressing columns are only validated where the new value differs from the stored one.
+// @Description UnitId is looked up again in the units catalogue when EngineeringUnits or NavigatorUnits change, unless the body changes UnitId too.
+// @Tags datapoints
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Param datapoint body models.DataPoint true "Datapoint to be stored"
+// @Success 200 {object} models.Response{data=models.DataPoint}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId} [put]
+func (c *Controller) UpdateDataPoint(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	before, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	var dp models.DataPoint
+	if err := json.NewDecoder(r.Body).Decode(&dp); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	c.saveDataPoint(w, r, before, dp)
+}
+
+// PatchDataPoint changes some columns of a datapoint.
+// @Summary Patch a datapoint
+// @Description Change the columns present in the body and keep the others
+// @Description Trend and BACnet addressing columns are only validated where the new value differs from the stored one.
+// @Description UnitId is looked up again in the units catalogue when EngineeringUnits or NavigatorUnits change, unless the body changes UnitId too.
+// @Tags datapoints
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Param datapoint body models.DataPoint true "Columns to be changed"
+// @Success 200 {object} models.Response{data=models.DataPoint}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId} [patch]
+func (c *Controller) PatchDataPoint(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	before, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	// Decoding over the stored point only touches the fields in the body
+	dp := before.Clone()
+	if err := json.NewDecoder(r.Body).Decode(&dp); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	c.saveDataPoint(w, r, before, dp)
+}
+
+// saveDataPoint validates and stores dp as the new version of before, the
+// stored point.
+func (c *Controller) saveDataPoint(w http.ResponseWriter, r *http.Request, before, dp models.DataPoint) {
+	dp.ID = before.ID
+	dp.ProjectID = before.ProjectID
+
+	if err := dp.Validate(); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !checkTrends(w, before, dp) || !c.checkAddress(w, r, before, dp) {
+		return
+	}
+	if err := c.mapUnit(r.Context(), before, &dp); err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	if err := c.store.DataPoints.Update(r.Context(), dp); err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+	c.syncDerived(r.Context(), dp.ProjectID)
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Datapoint updated successfully",
+		Data:    dp,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// checkAddress validates the BACnet addressing dp changes from before, the
+// stored point, and makes sure no other point of the project addresses the
+// same object. Values an edit leaves alone are not checked again. It writes
+// the error response and returns false when dp must not be stored.
+func (c *Controller) checkAddress(w http.ResponseWriter, r *http.Request, before, dp models.DataPoint) bool {
+	if errs := bacnet.CheckChanged(before, dp); len(errs) > 0 {
+		msg := strings.Join(errs, "; ")
+		utils.HandleError(w, errors.New(msg), http.StatusBadRequest, msg)
+		return false
+	}
+
+	key, ok := bacnet.KeyOf(dp)
+	if !ok {
+		return true
+	}
+	if k, ok := bacnet.KeyOf(before); ok && k == key {
+		return true
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), dp.ProjectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return false
+	}
+	for _, other := range points {
+		if other.ID == dp.ID {
+			continue
+		}
+		if k, ok := bacnet.KeyOf(other); ok && k == key {
+			msg := fmt.Sprintf("BACnet object %s is already used by datapoint %d", key, other.ID)
+			utils.HandleError(w, errors.New(msg), http.StatusConflict, msg)
+			return false
+		}
+	}
+
+	return true
+}
+
+// DeleteDataPoint deletes a datapoint.
+// @Summary Delete a datapoint
+// @Description Remove a datapoint from a project's point list
+// @Tags datapoints
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId} [delete]
+func (c *Controller) DeleteDataPoint(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if err := c.store.DataPoints.Delete(r.Context(), projectID, pointID); err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+	c.syncDerived(r.Context(), projectID)
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Datapoint deleted successfully",
+		Data:    nil,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
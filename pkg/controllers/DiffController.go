@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/diff"
+	"github.com/pufington-pixie/haver/pkg/importer"
+	"github.com/pufington-pixie/haver/pkg/imports"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/pointlist"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// diffResult is the response to a diff against an uploaded file: the
+// changes and what was done with every row of the file.
+type diffResult struct {
+	Diff   *diff.Result     `json:"diff,omitempty"`
+	Report *importer.Report `json:"report,omitempty"`
+}
+
+// DiffProjects compares the point lists of two projects.
+// @Summary Compare the points of two projects
+// @Description Compare the point list of a project with the one of another project, matching points on EquipID and PointName.
+// @Description Points only in the other project are added, points only in this project removed, and points in both with different values modified, with the before and after value of every changed column.
+// @Description With format=csv the changes are downloaded as a table with one row per added or removed point and one per changed column.
+// @Tags datapoints
+// @Produce json
+// @Produce text/csv
+// @Param id path int true "Project ID, the point list before"
+// @Param otherId path int true "Project ID, the point list after"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} models.Response{data=diff.Result}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/diff/{otherId} [get]
+func (c *Controller) DiffProjects(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	otherID, err := strconv.Atoi(chi.URLParam(r, "otherId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	format, ok := diffFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var lists [2][]models.DataPoint
+	for i, id := range []int{projectID, otherID} {
+		if _, err := c.store.Projects.Get(r.Context(), id); err != nil {
+			handleStoreError(w, err, fmt.Sprintf("Project %d not found", id))
+			return
+		}
+		lists[i], err = c.store.DataPoints.ListByProject(r.Context(), id)
+		if err != nil {
+			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+	}
+
+	result := diff.Compare(lists[0], lists[1], diff.Columns())
+	if format == pointlist.FormatCSV {
+		writeDiff(w, result, fmt.Sprintf("project-%d-%d-diff.csv", projectID, otherID))
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    result,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// DiffFile compares the point list of a project with an uploaded file.
+// @Summary Compare the points of a project with a file
+// @Description Compare the point list of a project with a CSV or XLSX point list before importing it, matching points on EquipID and PointName.
+// @Description The file is read like an upload, with the same aliases, sheet and headerRow options, but nothing is stored. Only the columns of the file are compared; rejected rows are left out and listed in the report.
+// @Description With format=csv the changes are downloaded as a table with one row per added or removed point and one per changed column.
+// @Tags datapoints
+// @Accept multipart/form-data
+// @Produce json
+// @Produce text/csv
+// @Param file formData file true "CSV or XLSX file to compare"
+// @Param aliases formData string false "JSON object mapping headers to datapoints columns"
+// @Param id path int true "Project ID"
+// @Param format query string false "json (default) or csv"
+// @Param strict query bool false "Reject files with unknown columns"
+// @Param sheet query string false "XLSX worksheet name or 1-based position, the first sheet by default"
+// @Param headerRow query int false "1-based row holding the header, 1 by default"
+// @Success 200 {object} models.Response{data=diffResult}
+// @Failure 400 {object} models.Response{data=diffResult}
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/diff [post]
+func (c *Controller) DiffFile(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	format, ok := diffFormat(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	imp, err := c.parseImport(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	// The file stands for the whole point list, so its rows may address the
+	// BACnet objects of the points they replace
+	imp.ProjectID = projectID
+	imp.FileName = header.Filename
+	imp.Mode = models.ImportModeReplace
+
+	parsed, err := c.imports.Parse(r.Context(), imp, file)
+	if errors.Is(err, imports.ErrFileRejected) {
+		data := diffResult{}
+		if parsed != nil {
+			data.Report = &parsed.Report
+		}
+		response := models.Response{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    data,
+		}
+		utils.SendJSONResponse(w, response, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	result, err := c.diffFile(r.Context(), projectID, parsed)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	if format == pointlist.FormatCSV {
+		writeDiff(w, result, fmt.Sprintf("project-%d-diff.csv", projectID))
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    diffResult{Diff: result, Report: &parsed.Report},
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// diffFile compares the point list of a project with the accepted rows of a
+// parsed file, on the columns of the file.
+func (c *Controller) diffFile(ctx context.Context, projectID int, parsed *importer.Result) (*diff.Result, error) {
+	after, err := fileDataPoints(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := c.store.DataPoints.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff.Compare(before, after, parsed.Columns), nil
+}
+
+// fileDataPoints returns the accepted rows of a parsed file as datapoints.
+func fileDataPoints(parsed *importer.Result) ([]models.DataPoint, error) {
+	points := make([]models.DataPoint, len(parsed.Rows))
+	for i, row := range parsed.Rows {
+		for j, col := range parsed.Columns {
+			if err := points[i].Set(col, row[j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return points, nil
+}
+
+// diffFormat reads the format of a diff from the query string.
+func diffFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	switch format {
+	case "":
+		return "json", true
+	case "json", pointlist.FormatCSV:
+		return format, true
+	}
+	utils.HandleError(w, nil, http.StatusBadRequest, "format must be json or csv")
+	return "", false
+}
+
+// writeDiff sends the changes of a diff as a CSV download.
+func writeDiff(w http.ResponseWriter, result *diff.Result, filename string) {
+	w.Header().Set("Content-Type", pointlist.ContentType(pointlist.FormatCSV))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	writer, err := pointlist.NewWriter(w, pointlist.FormatCSV)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	for _, record := range result.Records() {
+		if err := writer.Write(record); err != nil {
+			log.Println("diff:", err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.Println("diff:", err)
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/equipment"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// GetEquipment returns the equipment of a project.
+// @Summary Get the equipment of a project
+// @Description Get the equipment derived from the datapoints of a project, one per EquipID. The parent of a piece of equipment comes from the EquipRef of its points.
+// @Description With tree=true the equipment is nested under its parent; equipment without a known parent is a root.
+// @Tags equipment
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param tree query bool false "Return the equipment as a nested tree"
+// @Success 200 {object} models.Response{data=[]models.Equipment}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/equipment [get]
+func (c *Controller) GetEquipment(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	list, err := c.store.Equipment.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	var data interface{} = list
+	if tree, _ := strconv.ParseBool(r.URL.Query().Get("tree")); tree {
+		data = equipment.Tree(list)
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    data,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetEquipmentByID returns a piece of equipment with the equipment it feeds.
+// @Summary Get a piece of equipment
+// @Description Get a piece of equipment of a project by EquipID, with the equipment it feeds nested below it
+// @Tags equipment
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param equipId path string true "EquipID"
+// @Success 200 {object} models.Response{data=equipment.Node}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/equipment/{equipId} [get]
+func (c *Controller) GetEquipmentByID(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	node, ok := c.findEquipment(w, r, projectID, chi.URLParam(r, "equipId"))
+	if !ok {
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    node,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetEquipmentPoints returns the datapoints of a piece of equipment.
+// @Summary Get the datapoints of a piece of equipment
+// @Description Get the datapoints of a project with the given EquipID. The filter, q, sort and paging parameters of the datapoints list apply.
+// @Tags equipment
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param equipId path string true "EquipID"
+// @Param q query string false "Search PointName and Descriptor"
+// @Param sort query string false "Column to sort by, e.g. PointName or -PointName"
+// @Param limit query int false "Page size, at most 1000"
+// @Param cursor query string false "Cursor of the page to return"
+// @Success 200 {object} models.Response{data=[]models.DataPoint,meta=models.Meta}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/equipment/{equipId}/points [get]
+func (c *Controller) GetEquipmentPoints(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	query, err := parseDataPointQuery(r.URL.Query())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	node, ok := c.findEquipment(w, r, projectID, chi.URLParam(r, "equipId"))
+	if !ok {
+		return
+	}
+	query.Equals["EquipID"] = node.EquipID
+
+	data, total, err := c.store.DataPoints.Query(r.Context(), projectID, query)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Data retrieved successfully",
+		Data:    data,
+		Meta:    pageMeta(query, len(data), total),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// findEquipment looks a piece of equipment up in the tree of a project. It
+// writes the error response and returns false when it is not there.
+func (c *Controller) findEquipment(w http.ResponseWriter, r *http.Request, projectID int, equipID string) (*equipment.Node, bool) {
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, false
+	}
+
+	list, err := c.store.Equipment.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, false
+	}
+
+	node, ok := equipment.Find(equipment.Tree(list), equipID)
+	if !ok {
+		utils.HandleError(w, nil, http.StatusNotFound, "Equipment not found")
+		return nil, false
+	}
+
+	return node, true
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/pointlist"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// exportPageSize is the number of points read from the store at a time
+// while streaming an export.
+const exportPageSize = 1000
+
+// ExportDataPoints streams the point list of a project as a file.
+// @Summary Export the datapoints of a project
+// @Description Stream the point list of a project as CSV or XLSX. The header uses the datapoints column names, so the file can be uploaded again unchanged.
+// @Description columns picks the columns and their order; every column is exported by default. The filter, q and sort parameters of the datapoints list apply.
+// @Tags datapoints
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path int true "Project ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Param columns query string false "Comma separated column names, e.g. EquipID,PointName,Descriptor"
+// @Param q query string false "Search PointName and Descriptor"
+// @Param sort query string false "Column to sort by, e.g. PointName or -PointName"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/export [get]
+func (c *Controller) ExportDataPoints(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	values := r.URL.Query()
+	query, err := parseDataPointQuery(values)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	columns, err := exportColumns(values.Get("columns"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := strings.ToLower(values.Get("format"))
+	if format == "" {
+		format = pointlist.FormatCSV
+	}
+	if format != pointlist.FormatCSV && format != pointlist.FormatXLSX {
+		utils.HandleError(w, nil, http.StatusBadRequest, "format must be csv or xlsx")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	// Read the first page before committing to a 200
+	query.Offset, query.Limit = 0, exportPageSize
+	page, total, err := c.store.DataPoints.Query(r.Context(), projectID, query)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	w.Header().Set("Content-Type", pointlist.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="project-%d-datapoints.%s"`, projectID, format))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	writer, err := pointlist.NewWriter(w, format)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+	if err := writer.Write(header); err != nil {
+		log.Println("export:", err)
+		return
+	}
+
+	// Once streaming has started errors can only be logged
+	for {
+		for _, dp := range page {
+			record := make([]string, len(columns))
+			for i, col := range columns {
+				record[i] = dp.Get(col.Name)
+			}
+			if err := writer.Write(record); err != nil {
+				log.Println("export:", err)
+				return
+			}
+		}
+
+		query.Offset += len(page)
+		if len(page) == 0 || query.Offset >= total {
+			break
+		}
+		page, _, err = c.store.DataPoints.Query(r.Context(), projectID, query)
+		if err != nil {
+			log.Println("export:", err)
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Println("export:", err)
+	}
+}
+
+// exportColumns resolves a comma separated list of column names, returning
+// every column in table order for an empty list.
+func exportColumns(list string) ([]models.Column, error) {
+	if strings.TrimSpace(list) == "" {
+		return models.DataPointColumns, nil
+	}
+
+	var columns []models.Column
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		col, ok := models.DataPointColumn(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", strings.TrimSpace(name))
+		}
+		if !seen[col.Name] {
+			seen[col.Name] = true
+			columns = append(columns, col)
+		}
+	}
+	return columns, nil
+}
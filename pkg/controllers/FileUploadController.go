@@ -1,152 +1,332 @@
 package controller
 
 import (
-	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"log"
 	"strconv"
-	"strings"
-	"time"
 
 	"net/http"
 
 	"github.com/go-chi/chi"
-	"github.com/pufington-pixie/haver/pkg/database"
+	"github.com/pufington-pixie/haver/pkg/diff"
+	"github.com/pufington-pixie/haver/pkg/importer"
+	"github.com/pufington-pixie/haver/pkg/imports"
 	"github.com/pufington-pixie/haver/pkg/models"
 	"github.com/pufington-pixie/haver/utils"
 )
 
 // UploadHandler uploads a project by it's ID.
-// @Summary Upload CSV file and save data to the database
-// @Description Uploads a CSV file, parses its content, and saves the data to the database
+// @Summary Upload CSV or XLSX file and save data to the database
+// @Description Uploads a CSV or XLSX point list, maps its header onto the datapoints columns and saves the valid rows to the database.
+// @Description Headers are matched through the alias table; the optional "aliases" form field adds request specific aliases as a JSON object of header to column.
+// @Description Rows with invalid BACnet addressing, or addressing an object already used by the project or an earlier row, are rejected, and so are rows with trend intervals or sample counts a field panel does not support. Trend values that cannot be read reject their rows too, unless legacyTrends=true imports them as they are with a warning.
+// @Description EngineeringUnits, or failing that NavigatorUnits, is mapped onto the units catalogue to fill in UnitId; units that are not in the catalogue are reported.
+// @Description Unknown columns are ignored and reported unless strict=true, which rejects the file.
+// @Description The import runs in a single transaction: if any row is rejected nothing is written unless partial=true, and replace=true swaps the project's existing point list for the file atomically.
+// @Description Every upload is recorded as an import and the file is kept for download.
+// @Description XLSX workbooks are detected from their content; sheet picks the worksheet and headerRow the row holding the header, for CSV files too.
+// @Description With async=true the file is queued for a background worker and 202 is returned straight away; poll the import for progress.
+// @Description With dryRun=true nothing is stored: the response previews the column mapping, the first previewRows normalized rows, the errors of every row and the diff of the project's point list against the file (uploadPreview). When appending every row is listed as added and the rows sharing EquipID and PointName with an existing point are listed as collisions.
 // @Accept multipart/form-data
-// @Param file formData file true "CSV file to upload"
+// @Produce json
+// @Param file formData file true "CSV or XLSX file to upload"
+// @Param aliases formData string false "JSON object mapping headers to datapoints columns"
+// @Param uploader formData string false "Who is uploading the file"
 // @Param id path int true "Project ID"
-// @Success 200 {string} string "CSV file uploaded and saved to the database successfully!"
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Param strict query bool false "Reject files with unknown columns"
+// @Param partial query bool false "Import the valid rows even if some rows are rejected"
+// @Param replace query bool false "Replace the project's existing datapoints"
+// @Param legacyTrends query bool false "Import trend values that cannot be read with a warning instead of rejecting their rows"
+// @Param async query bool false "Import in the background"
+// @Param dryRun query bool false "Validate the file and preview the import without writing anything"
+// @Param previewRows query int false "Number of rows returned by a dry run, 20 by default"
+// @Param sheet query string false "XLSX worksheet name or 1-based position, the first sheet by default"
+// @Param headerRow query int false "1-based row holding the header, 1 by default"
+// @Success 200 {object} models.Response{data=uploadResult}
+// @Success 202 {object} models.Response{data=models.Import}
+// @Failure 400 {object} models.Response{data=uploadResult}
+// @Failure 404 {object} models.Response
+// @Failure 422 {object} models.Response{data=uploadResult}
+// @Failure 500 {object} models.Response
+// @Failure 503 {object} models.Response
 // @Router /api/upload/{id} [post]
-func UploadHandler(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) UploadHandler(w http.ResponseWriter, r *http.Request) {
+	// Get the project ID from the projects table
+	projectIDStr := chi.URLParam(r, "id")
+	projectID, err := strconv.Atoi(projectIDStr)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Parse the multipart form in the request
-	err := r.ParseMultipartForm(32 << 20) 
+	err = r.ParseMultipartForm(32 << 20)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	imp, err := c.parseImport(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	imp.ProjectID = projectID
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	previewRows, err := previewSize(r.URL.Query().Get("previewRows"))
 	if err != nil {
 		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Get the file from the request
-	file, _, err := r.FormFile("file")
+	file, header, err := r.FormFile("file")
 	if err != nil {
 		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
 		return
 	}
 	defer file.Close()
 
-	// Create a  file name for storing the uploaded file
-	fileName := strconv.FormatInt(time.Now().Unix(), 10) + ".csv"
-	destinationPath := filepath.Join("uploads", fileName)
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
 
-	// Create the uploads directory if it doesn't exist
-	err = os.MkdirAll("uploads", os.ModePerm)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, err.Error())
+	if dryRun {
+		imp.FileName = header.Filename
+		c.previewImport(w, r, imp, file, previewRows)
 		return
 	}
 
-	// Create the destination file on the server
-	destinationFile, err := os.Create(destinationPath)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, err.Error())
+	// Keep the file and record the import before loading anything
+	if err := c.imports.Create(r.Context(), &imp, header.Filename, file); err != nil {
+		handleStoreError(w, err, "Project not found")
 		return
 	}
-	defer destinationFile.Close()
 
-	// Copy the contents of the uploaded file to the destination file
-	_, err = io.Copy(destinationFile, file)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, err.Error())
+	if async {
+		c.enqueueImport(w, r, imp)
 		return
 	}
 
-	// Read the CSV file
-	csvFile, err := os.Open(destinationPath)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, err.Error())
+	result, err := c.imports.Run(r.Context(), &imp)
+
+	data := uploadResult{Import: imp}
+	if result != nil {
+		data.Report = &result.Report
+	}
+
+	switch {
+	case imp.Status == models.ImportRejected:
+		status := http.StatusBadRequest
+		if errors.Is(err, imports.ErrRowsRejected) {
+			status = http.StatusUnprocessableEntity
+		}
+		response := models.Response{
+			Status:  status,
+			Message: err.Error(),
+			Data:    data,
+		}
+		utils.SendJSONResponse(w, response, status)
+		return
+
+	case err != nil:
+		handleStoreError(w, err, "Project not found")
 		return
 	}
-	defer csvFile.Close()
 
-	reader := csv.NewReader(csvFile)
-	records, err := reader.ReadAll()
+	message := "Data inserted successfully"
+	if result.Report.Rejected > 0 {
+		message = "Data inserted with rejected rows"
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    data,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// uploadResult is the response to an upload: the import record and what was
+// done with every row.
+type uploadResult struct {
+	Import models.Import    `json:"import"`
+	Report *importer.Report `json:"report,omitempty"`
+}
+
+// defaultPreviewRows and maxPreviewRows bound the rows returned by a dry run.
+const (
+	defaultPreviewRows = 20
+	maxPreviewRows     = 500
+)
+
+// uploadPreview is the response to a dry run: how the file would be
+// imported and what it would change, with nothing written.
+type uploadPreview struct {
+	// Columns are the datapoints columns of the rows, in row order.
+	Columns []string `json:"columns"`
+	// Rows are the first accepted rows of the file, normalized as they
+	// would be stored.
+	Rows   [][]string      `json:"rows"`
+	Report importer.Report `json:"report"`
+	// Diff compares the project's point list with the accepted rows. When
+	// appending every row is added and no point is removed or modified.
+	Diff *diff.Result `json:"diff,omitempty"`
+	// Collisions are the rows that share EquipID and PointName with a point
+	// of the project, which appending keeps side by side with the row.
+	Collisions []diff.Point `json:"collisions,omitempty"`
+}
+
+// previewSize reads the number of rows a dry run returns.
+func previewSize(raw string) (int, error) {
+	if raw == "" {
+		return defaultPreviewRows, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > maxPreviewRows {
+		return 0, fmt.Errorf("previewRows must be between 0 and %d", maxPreviewRows)
+	}
+	return n, nil
+}
+
+// previewImport parses and validates a file as an upload would, without
+// storing the file, recording an import or writing any point.
+func (c *Controller) previewImport(w http.ResponseWriter, r *http.Request, imp models.Import, file io.Reader, rows int) {
+	parsed, err := c.imports.Parse(r.Context(), imp, file)
+	if errors.Is(err, imports.ErrFileRejected) {
+		var data interface{}
+		if parsed != nil {
+			data = uploadPreview{Report: parsed.Report}
+		}
+		response := models.Response{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    data,
+		}
+		utils.SendJSONResponse(w, response, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, err.Error())
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 
-	db := database.GetDB()
-	defer db.Close()
-
-	// Get the project ID from the projects table
-	projectIDStr := chi.URLParam(r, "id")
-	projectID, err := strconv.Atoi(projectIDStr)
+	after, err := fileDataPoints(parsed)
 	if err != nil {
-		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	before, err := c.store.DataPoints.ListByProject(r.Context(), imp.ProjectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 
+	var (
+		result     *diff.Result
+		collisions []diff.Point
+	)
+	if imp.Mode == models.ImportModeReplace {
+		result = diff.Compare(before, after, parsed.Columns)
+	} else {
+		// Appending keeps every existing point and adds every row as a new
+		// one, whatever it shares with the points already there
+		result = diff.Compare(nil, after, parsed.Columns)
+		collisions = diff.Collisions(before, after)
+	}
+
+	if rows > len(parsed.Rows) {
+		rows = len(parsed.Rows)
+	}
+	data := uploadPreview{
+		Columns:    parsed.Columns,
+		Rows:       parsed.Rows[:rows],
+		Report:     parsed.Report,
+		Diff:       result,
+		Collisions: collisions,
+	}
+
+	message := "File can be imported"
+	if parsed.Report.Rejected > 0 {
+		message = "File has rejected rows"
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    data,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// parseImport reads the import options from the query string and the form.
+func (c *Controller) parseImport(r *http.Request) (models.Import, error) {
+	query := r.URL.Query()
+	imp := models.Import{
+		Uploader: r.FormValue("uploader"),
+		Mode:     models.ImportModeAppend,
+	}
+	imp.Options.Strict, _ = strconv.ParseBool(query.Get("strict"))
+	imp.Options.Partial, _ = strconv.ParseBool(query.Get("partial"))
+	imp.Options.LegacyTrends, _ = strconv.ParseBool(query.Get("legacyTrends"))
+	if replace, _ := strconv.ParseBool(query.Get("replace")); replace {
+		imp.Mode = models.ImportModeReplace
+	}
+
+	imp.Options.Sheet = query.Get("sheet")
+	if headerRow := query.Get("headerRow"); headerRow != "" {
+		n, err := strconv.Atoi(headerRow)
+		if err != nil || n < 1 {
+			return imp, errors.New("headerRow must be a positive row number")
+		}
+		imp.Options.HeaderRow = n
+	}
 
-	headers := records[0]
-	for i, header := range records[0] {
-		// Replace spaces with underscores and remove any other unwanted characters
-		header = strings.ReplaceAll(header, " ", "")
-		header = strings.ReplaceAll(header, ".", "") 
-		header = strings.ReplaceAll(header, "?", "") 
-		headers[i] = header
-	}
-	// Prepare the SQL statement for bulk insertion
-	columnCount := len(records[0]) 
-	placeholders := strings.TrimRight(strings.Repeat("?, ", columnCount), ", ")
-	
-	// Escape column names
-	escapedHeaders := make([]string, len(headers))
-	for i, h := range headers {
-		escapedHeaders[i] = "`" + h + "`"
-	}
-	
-	insertQuery := fmt.Sprintf("INSERT INTO datapoints (projectID, %s) VALUES (?, %s)", strings.Join(escapedHeaders, ", "), placeholders)
-		// Prepare the SQL statement
-		stmt, err := db.Prepare(insertQuery)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	if raw := r.FormValue("aliases"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &imp.Options.Aliases); err != nil {
+			return imp, errors.New("aliases must be a JSON object")
 		}
-		defer stmt.Close()
-	
-		// Insert each row into the database
-		for _, row := range records {
-			values := make([]interface{}, 0, len(row)+1)
-			values = append(values, projectID)
-	
-			for _, col := range row {
-				values = append(values, col)
-			}
-	
-			_, err := stmt.Exec(values...)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
+		// Catch unknown columns before the file is stored
+		if _, err := c.imports.Aliases().With(imp.Options.Aliases); err != nil {
+			return imp, err
 		}
-	
-		response := models.Response{
-			Status:  http.StatusOK,
-			Message: "Data inserted successfully",
-			Data:    nil,
+	}
+
+	return imp, nil
+}
+
+// enqueueImport hands a stored import to the background workers and answers
+// 202 with the import to poll.
+func (c *Controller) enqueueImport(w http.ResponseWriter, r *http.Request, imp models.Import) {
+	if err := c.imports.Enqueue(imp); err != nil {
+		imp.Status = models.ImportFailed
+		imp.Errors = []string{err.Error()}
+		if updateErr := c.store.Imports.Update(r.Context(), imp); updateErr != nil {
+			// Left queued, the import is picked up again on the next start
+			log.Printf("imports: marking import %d failed: %v", imp.ID, updateErr)
 		}
-	
-		utils.SendJSONResponse(w, response, http.StatusOK)
+
+		msg := "Import queue is full, try again later"
+		if errors.Is(err, imports.ErrClosed) {
+			msg = "Server is shutting down, try again later"
+		}
+		utils.HandleError(w, err, http.StatusServiceUnavailable, msg)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/imports/%d", imp.ID))
+	response := models.Response{
+		Status:  http.StatusAccepted,
+		Message: "Import queued",
+		Data:    imp,
 	}
-	
 
+	utils.SendJSONResponse(w, response, http.StatusAccepted)
+}
@@ -0,0 +1,425 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/haystack"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// pointTagging is the Haystack tags of a datapoint: those derived by the
+// rules, the user's overrides and the outcome.
+type pointTagging struct {
+	PointID   int      `json:"pointId"`
+	PointName string   `json:"pointName"`
+	Derived   []string `json:"derived"`
+	Add       []string `json:"add"`
+	Remove    []string `json:"remove"`
+	Tags      []string `json:"tags"`
+}
+
+// tagOverrides is the body of a point tags update.
+type tagOverrides struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// taggingRules returns the tagging rules of a project, the default rules if
+// it has none yet.
+func (c *Controller) taggingRules(ctx context.Context, projectID int) (models.TaggingRules, error) {
+	if _, err := c.store.Projects.Get(ctx, projectID); err != nil {
+		return models.TaggingRules{}, err
+	}
+
+	rules, err := c.store.Tags.GetRules(ctx, projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		return models.TaggingRules{ProjectID: projectID, Rules: haystack.DefaultRules}, nil
+	}
+	return rules, err
+}
+
+// tagger compiles the tagging rules of a project. It writes the error
+// response and returns false when they cannot be read.
+func (c *Controller) tagger(w http.ResponseWriter, r *http.Request, projectID int) (*haystack.Tagger, bool) {
+	rules, err := c.taggingRules(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, false
+	}
+
+	tagger, err := haystack.Compile(rules.Rules)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, false
+	}
+
+	return tagger, true
+}
+
+// pointOverrides returns the tag overrides of the datapoints of a project by
+// point ID.
+func (c *Controller) pointOverrides(ctx context.Context, projectID int) (map[int]models.PointTags, error) {
+	list, err := c.store.Tags.ListPointTags(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[int]models.PointTags, len(list))
+	for _, o := range list {
+		overrides[o.PointID] = o
+	}
+	return overrides, nil
+}
+
+// tagPoint returns the tagging of dp with the overrides o.
+func tagPoint(tagger *haystack.Tagger, dp models.DataPoint, o models.PointTags) pointTagging {
+	derived := tagger.PointTags(dp)
+	if o.Add == nil {
+		o.Add = []string{}
+	}
+	if o.Remove == nil {
+		o.Remove = []string{}
+	}
+	return pointTagging{
+		PointID:   dp.ID,
+		PointName: dp.PointName,
+		Derived:   derived,
+		Add:       o.Add,
+		Remove:    o.Remove,
+		Tags:      haystack.Apply(derived, o),
+	}
+}
+
+// GetTaggingRules returns the Haystack tagging rules of a project.
+// @Summary Get the Haystack tagging rules of a project
+// @Description Get the rules deriving Haystack marker tags from the datapoints of a project. Projects without rules of their own use the default rules.
+// @Tags haystack
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=models.TaggingRules}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/haystack/rules [get]
+func (c *Controller) GetTaggingRules(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	rules, err := c.taggingRules(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    rules,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// UpdateTaggingRules replaces the Haystack tagging rules of a project.
+// @Summary Update the Haystack tagging rules of a project
+// @Description Replace the tagging rules of a project. A rule adds its tags to the datapoints whose column matches its pattern anywhere, ignoring case.
+// @Description Rules on EquipType tag the equipment; the others tag the points.
+// @Tags haystack
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param rules body models.TaggingRules true "Tagging rules to be stored"
+// @Success 200 {object} models.Response{data=models.TaggingRules}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/haystack/rules [put]
+func (c *Controller) UpdateTaggingRules(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var rules models.TaggingRules
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	rules.ProjectID = projectID
+	if rules.Rules == nil {
+		rules.Rules = []models.TagRule{}
+	}
+
+	if _, err := haystack.Compile(rules.Rules); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	for i, rule := range rules.Rules {
+		col, _ := models.DataPointColumn(rule.Column)
+		rules.Rules[i].Column = col.Name
+	}
+
+	if err := c.store.Tags.PutRules(r.Context(), rules); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Tagging rules updated successfully",
+		Data:    rules,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetProjectTags returns the Haystack tags of every datapoint of a project.
+// @Summary Get the Haystack tags of a project
+// @Description Get the marker tags derived for every datapoint of a project, the user's overrides and the resulting tags
+// @Tags haystack
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=[]pointTagging}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/haystack/tags [get]
+func (c *Controller) GetProjectTags(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	tagger, ok := c.tagger(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	overrides, err := c.pointOverrides(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	list := make([]pointTagging, 0, len(points))
+	for _, dp := range points {
+		list = append(list, tagPoint(tagger, dp, overrides[dp.ID]))
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    list,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetPointTags returns the Haystack tags of a datapoint.
+// @Summary Get the Haystack tags of a datapoint
+// @Description Get the marker tags derived for a datapoint, the user's overrides and the resulting tags
+// @Tags haystack
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Success 200 {object} models.Response{data=pointTagging}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId}/tags [get]
+func (c *Controller) GetPointTags(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	tagger, ok := c.tagger(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	dp, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	o, err := c.store.Tags.GetPointTags(r.Context(), pointID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    tagPoint(tagger, dp, o),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// UpdatePointTags replaces the Haystack tag overrides of a datapoint.
+// @Summary Override the Haystack tags of a datapoint
+// @Description Replace the marker tags added to and removed from the tags derived for a datapoint. Empty lists return the point to its derived tags.
+// @Tags haystack
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Param tags body tagOverrides true "Tags to add and remove"
+// @Success 200 {object} models.Response{data=pointTagging}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId}/tags [put]
+func (c *Controller) UpdatePointTags(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var body tagOverrides
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	o, err := haystack.Canonical(models.PointTags{PointID: pointID, ProjectID: projectID, Add: body.Add, Remove: body.Remove})
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tagger, ok := c.tagger(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	dp, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	if err := c.store.Tags.PutPointTags(r.Context(), o); err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Datapoint tags updated successfully",
+		Data:    tagPoint(tagger, dp, o),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// ExportHaystack writes a project as a Haystack grid.
+// @Summary Export a project as Project Haystack
+// @Description Export a project as a Haystack grid in Zinc or JSON: the project as the site, its equipment and its datapoints with their marker tags.
+// @Description Equipment and points reference the site through siteRef and their equipment through equipRef.
+// @Tags haystack
+// @Produce text/zinc
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param format query string false "zinc (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/export/haystack [get]
+func (c *Controller) ExportHaystack(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "zinc"
+	}
+	if format != "zinc" && format != "json" {
+		utils.HandleError(w, nil, http.StatusBadRequest, "format must be zinc or json")
+		return
+	}
+
+	project, err := c.store.Projects.Get(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	tagger, ok := c.tagger(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	equipment, err := c.store.Equipment.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	overrides, err := c.store.Tags.ListPointTags(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	catalogue, err := c.unitCatalogue(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	grid := tagger.Grid(haystack.Project{
+		Project:   project,
+		Equipment: equipment,
+		Points:    points,
+		Overrides: overrides,
+		Units:     catalogue,
+	})
+
+	contentType := "text/zinc; charset=utf-8"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="project-%d-haystack.%s"`, projectID, format))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if format == "json" {
+		err = grid.WriteJSON(w)
+	} else {
+		err = grid.WriteZinc(w)
+	}
+	if err != nil {
+		log.Println("export:", err)
+	}
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// GetProjectImports returns the upload history of a project.
+// @Summary Get the imports of a project
+// @Description Get every point list uploaded into a project, newest first
+// @Tags imports
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=[]models.Import}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/imports [get]
+func (c *Controller) GetProjectImports(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	imports, err := c.store.Imports.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    imports,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetImport returns a single import.
+// @Summary Get an import by ID
+// @Description Get the record of a point list upload, including the progress of a background import
+// @Tags imports
+// @Produce json
+// @Param importId path int true "Import ID"
+// @Success 200 {object} models.Response{data=models.Import}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/imports/{importId} [get]
+func (c *Controller) GetImport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "importId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	imp, err := c.store.Imports.Get(r.Context(), id)
+	if err != nil {
+		handleStoreError(w, err, "Import not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    imp,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// DownloadImport returns the file exactly as it was uploaded.
+// @Summary Download the file of an import
+// @Description Download the stored point list of an import under its original file name
+// @Tags imports
+// @Produce octet-stream
+// @Param importId path int true "Import ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/imports/{importId}/file [get]
+func (c *Controller) DownloadImport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "importId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	imp, err := c.store.Imports.Get(r.Context(), id)
+	if err != nil {
+		handleStoreError(w, err, "Import not found")
+		return
+	}
+
+	file, err := c.imports.Open(imp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			utils.HandleError(w, err, http.StatusNotFound, "Import file not found")
+		} else {
+			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": imp.FileName}))
+	http.ServeContent(w, r, imp.FileName, imp.StartedAt, file)
+}
@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/naming"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// namingReport is the outcome of checking a project against its naming
+// convention.
+type namingReport struct {
+	Points     int                `json:"points"`
+	Violations []naming.Violation `json:"violations"`
+}
+
+// generatedName is the point name generated for a datapoint, or why none
+// could be.
+type generatedName struct {
+	PointID   int    `json:"pointId"`
+	Current   string `json:"current"`
+	Generated string `json:"generated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// namingConvention returns the convention of a project, an empty one if it
+// has none yet.
+func (c *Controller) namingConvention(ctx context.Context, projectID int) (models.NamingConvention, error) {
+	if _, err := c.store.Projects.Get(ctx, projectID); err != nil {
+		return models.NamingConvention{}, err
+	}
+
+	nc, err := c.store.Naming.Get(ctx, projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		return models.NamingConvention{ProjectID: projectID, Rules: []models.NamingRule{}}, nil
+	}
+	return nc, err
+}
+
+// GetNamingConvention returns the naming convention of a project.
+// @Summary Get the naming convention of a project
+// @Description Get the naming rules and point name template of a project
+// @Tags naming
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=models.NamingConvention}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/naming [get]
+func (c *Controller) GetNamingConvention(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	nc, err := c.namingConvention(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    nc,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// UpdateNamingConvention replaces the naming convention of a project.
+// @Summary Update the naming convention of a project
+// @Description Replace the naming rules and point name template of a project.
+// @Description Rules are regular expressions the whole value of a column must match. The template names columns in braces, e.g. {System:upper}.{EquipID}.{Function}.
+// @Tags naming
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param convention body models.NamingConvention true "Naming convention to be stored"
+// @Success 200 {object} models.Response{data=models.NamingConvention}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/naming [put]
+func (c *Controller) UpdateNamingConvention(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var nc models.NamingConvention
+	if err := json.NewDecoder(r.Body).Decode(&nc); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	nc.ProjectID = projectID
+	if nc.Rules == nil {
+		nc.Rules = []models.NamingRule{}
+	}
+
+	if _, err := naming.Compile(nc); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	for i, rule := range nc.Rules {
+		col, _ := models.DataPointColumn(rule.Column)
+		nc.Rules[i].Column = col.Name
+	}
+
+	if err := c.store.Naming.Put(r.Context(), nc); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Naming convention updated successfully",
+		Data:    nc,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetNamingViolations checks the datapoints of a project against its naming
+// convention.
+// @Summary Get the naming violations of a project
+// @Description Evaluate every datapoint of a project against its naming rules and list the values that break them
+// @Tags naming
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=namingReport}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/naming/violations [get]
+func (c *Controller) GetNamingViolations(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	convention, points, ok := c.loadNaming(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	report := namingReport{Points: len(points), Violations: []naming.Violation{}}
+	for _, dp := range points {
+		report.Violations = append(report.Violations, convention.Check(dp)...)
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    report,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GenerateNames builds point names from the template of a project.
+// @Summary Generate point names
+// @Description Build PointName from the project's point name template, for the datapoints without a name unless overwrite=true.
+// @Description The names are only previewed unless apply=true, which stores them.
+// @Tags naming
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param apply query bool false "Store the generated names"
+// @Param overwrite query bool false "Also rename datapoints that have a name"
+// @Success 200 {object} models.Response{data=[]generatedName}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/naming/generate [post]
+func (c *Controller) GenerateNames(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	query := r.URL.Query()
+	apply, _ := strconv.ParseBool(query.Get("apply"))
+	overwrite, _ := strconv.ParseBool(query.Get("overwrite"))
+
+	convention, points, ok := c.loadNaming(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	names := []generatedName{}
+	var renamed []models.DataPoint
+	for _, dp := range points {
+		if dp.PointName != "" && !overwrite {
+			continue
+		}
+
+		result := generatedName{PointID: dp.ID, Current: dp.PointName}
+		name, err := convention.Generate(dp)
+		if errors.Is(err, naming.ErrNoTemplate) {
+			utils.HandleError(w, err, http.StatusBadRequest, "The project has no point name template")
+			return
+		}
+		if err == nil {
+			dp.PointName = name
+			err = dp.Validate()
+		}
+		if err != nil {
+			result.Error = err.Error()
+			names = append(names, result)
+			continue
+		}
+		result.Generated = name
+
+		if name != result.Current {
+			renamed = append(renamed, dp)
+		}
+		names = append(names, result)
+	}
+
+	if apply && len(renamed) > 0 {
+		// Rename all or nothing, a half renamed project is worse than none
+		if err := c.store.DataPoints.UpdateMany(r.Context(), renamed); err != nil {
+			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+		c.syncDerived(r.Context(), projectID)
+	}
+
+	message := "Point names generated"
+	if apply {
+		message = "Point names updated successfully"
+	}
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    names,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// loadNaming reads the compiled naming convention and the datapoints of a
+// project. It writes the error response and returns false when either
+// cannot be read.
+func (c *Controller) loadNaming(w http.ResponseWriter, r *http.Request, projectID int) (*naming.Convention, []models.DataPoint, bool) {
+	nc, err := c.namingConvention(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, nil, false
+	}
+
+	convention, err := naming.Compile(nc)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, nil, false
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, nil, false
+	}
+
+	return convention, points, true
+}
@@ -0,0 +1,339 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/topology"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// GetNetworks returns the networks of a project.
+// @Summary Get the networks of a project
+// @Description Get the BLN, FLN, MS/TP and BACnet/IP networks derived from the addressing columns of the datapoints of a project, with their device and point counts
+// @Tags topology
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=[]models.Network}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/networks [get]
+func (c *Controller) GetNetworks(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	list, err := c.store.Networks.ListNetworks(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    list,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetDevices returns the devices of a project.
+// @Summary Get the devices of a project
+// @Description Get the field panels and field devices of a project, derived from the DeviceSysName and FLNdeviceSysName of its datapoints or declared through the API
+// @Tags topology
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=[]models.Device}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/devices [get]
+func (c *Controller) GetDevices(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	list, err := c.store.Networks.ListDevices(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    list,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// CreateDevice declares a device of a project.
+// @Summary Declare a device
+// @Description Declare a field panel or field device before any datapoint refers to it. A declared device stays until it is deleted; the values of its points take precedence over the declared network, parent, address and instance.
+// @Tags topology
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param device body models.Device true "Device to be declared"
+// @Success 201 {object} models.Response{data=models.Device}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/devices [post]
+func (c *Controller) CreateDevice(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var d models.Device
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	d.ID = 0
+	d.ProjectID = projectID
+	if err := checkDevice(&d); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.store.Networks.CreateDevice(r.Context(), &d); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+	c.syncDerived(r.Context(), projectID)
+
+	response := models.Response{
+		Status:  http.StatusCreated,
+		Message: "Device created successfully",
+		Data:    d,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusCreated)
+}
+
+// DeleteDevice removes a declared device from a project.
+// @Summary Delete a device
+// @Description Remove a device of a project. Devices that datapoints still refer to cannot be deleted.
+// @Tags topology
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param deviceId path int true "Device ID"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/devices/{deviceId} [delete]
+func (c *Controller) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	deviceID, err := strconv.Atoi(chi.URLParam(r, "deviceId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	devices, err := c.store.Networks.ListDevices(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	for _, d := range devices {
+		if d.ID == deviceID && d.Points > 0 {
+			utils.HandleError(w, nil, http.StatusConflict, fmt.Sprintf("Device %s still has %d datapoints", d.Name, d.Points))
+			return
+		}
+	}
+
+	if err := c.store.Networks.DeleteDevice(r.Context(), projectID, deviceID); err != nil {
+		handleStoreError(w, err, "Device not found")
+		return
+	}
+	c.syncDerived(r.Context(), projectID)
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Device deleted successfully",
+		Data:    nil,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetTopology returns the topology of a project as a tree.
+// @Summary Get the topology of a project
+// @Description Get the networks of a project with their panels, the field devices of each panel and the datapoints of each device.
+// @Description Points that belong to no device are listed in unassignedPoints, devices without points in unusedDevices.
+// @Tags topology
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=topology.Site}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/topology [get]
+func (c *Controller) GetTopology(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	site, ok := c.topology(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    site,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// SyncTopology rebuilds the networks and devices of a project.
+// @Summary Rebuild the topology of a project
+// @Description Derive the networks and devices of a project from its datapoints again and return the resulting tree. Projects whose points have not changed since the topology was introduced need this once.
+// @Tags topology
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=topology.Site}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/topology/sync [post]
+func (c *Controller) SyncTopology(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if err := topology.Sync(r.Context(), c.store, projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	site, ok := c.topology(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Topology rebuilt successfully",
+		Data:    site,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// topology loads the tree of a project. It writes the error response and
+// returns false when that fails.
+func (c *Controller) topology(w http.ResponseWriter, r *http.Request, projectID int) (*topology.Site, bool) {
+	project, err := c.store.Projects.Get(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, false
+	}
+
+	networks, err := c.store.Networks.ListNetworks(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, false
+	}
+	devices, err := c.store.Networks.ListDevices(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, false
+	}
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return nil, false
+	}
+
+	return topology.Tree(project, networks, devices, points), true
+}
+
+// checkDevice validates a device to be declared and tidies its names.
+func checkDevice(d *models.Device) error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Network = strings.TrimSpace(d.Network)
+	d.Parent = strings.TrimSpace(d.Parent)
+	d.Address = strings.TrimSpace(d.Address)
+
+	if d.Name == "" {
+		return errors.New("device name is required")
+	}
+	switch d.Kind {
+	case models.DevicePanel:
+		d.Parent = ""
+	case models.DeviceField:
+	default:
+		return fmt.Errorf("device kind must be %s or %s", models.DevicePanel, models.DeviceField)
+	}
+	switch d.NetworkType {
+	case "":
+		if d.Network != "" {
+			return errors.New("network type is required with a network")
+		}
+	case models.NetworkBLN, models.NetworkFLN, models.NetworkMSTP, models.NetworkBACnetIP:
+		if d.Network == "" {
+			return errors.New("network is required with a network type")
+		}
+	default:
+		return fmt.Errorf("unknown network type %q", d.NetworkType)
+	}
+
+	sizes := []struct {
+		name  string
+		value string
+		size  int
+	}{
+		{"name", d.Name, models.DeviceNameSize},
+		{"network", d.Network, models.DeviceNetworkSize},
+		{"parent", d.Parent, models.DeviceNameSize},
+		{"address", d.Address, models.DeviceAddressSize},
+	}
+	for _, f := range sizes {
+		if n := utf8.RuneCountInString(f.value); n > f.size {
+			return fmt.Errorf("device %s is %d characters, at most %d allowed", f.name, n, f.size)
+		}
+	}
+	return nil
+}
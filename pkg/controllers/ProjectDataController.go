@@ -1,26 +1,50 @@
 package controller
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
-	"github.com/pufington-pixie/haver/pkg/database"
+	"github.com/pufington-pixie/haver/pkg/importer"
 	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
 	"github.com/pufington-pixie/haver/utils"
 )
 
-type DataPoint struct {
-	ID         int    `json:"id"`
-	EquipID    string `json:"EquipID"`
-	System     string `json:"System"`
-	EquipType  string `json:"EquipType"`
-	PointName  string `json:"point_name"`
-	PointType  string `json:"point_type"`
-	Descriptor string `json:"descriptor"`
-}
+// maxPageSize caps the limit a client may ask for.
+const maxPageSize = 1000
 
-func GetData(w http.ResponseWriter, r *http.Request) {
+// GetData returns the datapoints of a project.
+// @Summary Get the datapoints of a project
+// @Description Get the point list of a project from the database.
+// @Description Any datapoints column can be used as a filter parameter, for example System, EquipType, EquipID, PointType, BACnetObjectType or IncludedInProject; text is matched ignoring case.
+// @Description q searches PointName and Descriptor. sort orders by any column, prefixed with - for descending order.
+// @Description Without limit every matching point is returned; with limit the response meta carries the total count and the cursor of the next page.
+// @Tags datapoints
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param System query string false "Filter by system"
+// @Param EquipType query string false "Filter by equipment type"
+// @Param EquipID query string false "Filter by equipment"
+// @Param PointType query string false "Filter by point type"
+// @Param BACnetObjectType query string false "Filter by BACnet object type"
+// @Param IncludedInProject query bool false "Filter by inclusion in the project"
+// @Param q query string false "Search PointName and Descriptor"
+// @Param sort query string false "Column to sort by, e.g. PointName or -PointName"
+// @Param limit query int false "Page size, at most 1000"
+// @Param cursor query string false "Cursor of the page to return"
+// @Success 200 {object} models.Response{data=[]models.DataPoint,meta=models.Meta}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/data/{id} [get]
+// @Router /api/projects/{id}/datapoints [get]
+func (c *Controller) GetData(w http.ResponseWriter, r *http.Request) {
 	projectIDStr := chi.URLParam(r, "id")
 	projectID, err := strconv.Atoi(projectIDStr)
 	if err != nil {
@@ -28,42 +52,106 @@ func GetData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db := database.GetDB()
-	defer db.Close()
+	query, err := parseDataPointQuery(r.URL.Query())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	// Query the database to retrieve data
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
 
-	rows, err := db.Query("SELECT id,EquipId, System, EquipType, Descriptor, PointName, PointType FROM datapoints WHERE projectID = ?", projectID)
+	// Query the database to retrieve data
+	data, total, err := c.store.DataPoints.Query(r.Context(), projectID, query)
 	if err != nil {
 		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
-	rows.Next()
-	defer rows.Close()
 
-	// Create a slice to store the retrieved data
-	data := []DataPoint{}
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Data retrieved successfully",
+		Data:    data,
+		Meta:    pageMeta(query, len(data), total),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
 
-	// Iterate over the rows and populate the data slice
-	for rows.Next() {
+}
 
-		var dp DataPoint
+// pageMeta describes a page of count datapoints out of total.
+func pageMeta(query store.DataPointQuery, count, total int) *models.Meta {
+	meta := &models.Meta{
+		Total: total,
+		Count: count,
+		Limit: query.Limit,
+	}
+	if next := query.Offset + count; query.Limit > 0 && next < total {
+		meta.NextCursor = encodeCursor(next)
+	}
+	return meta
+}
 
-		err := rows.Scan(&dp.ID,&dp.EquipID, &dp.System, &dp.EquipType, &dp.Descriptor, &dp.PointName, &dp.PointType)
+// parseDataPointQuery reads the datapoint filters, sort order and page from
+// the query string. Parameters that name no column are ignored.
+func parseDataPointQuery(values url.Values) (store.DataPointQuery, error) {
+	query := store.DataPointQuery{
+		Equals: make(map[string]string),
+		Search: strings.TrimSpace(values.Get("q")),
+	}
+
+	for key, vals := range values {
+		column, ok := models.DataPointColumn(key)
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		value, err := importer.NormalizeValue(models.Column{Name: column.Name, Type: column.Type}, vals[0])
 		if err != nil {
-			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
-			return
+			return query, err
 		}
+		query.Equals[column.Name] = value
+	}
 
-		data = append(data, dp)
+	if sort := values.Get("sort"); sort != "" {
+		query.Sort = strings.TrimPrefix(sort, "-")
+		query.Desc = strings.HasPrefix(sort, "-")
+		if _, ok := models.DataPointColumn(query.Sort); !ok && !strings.EqualFold(query.Sort, "id") {
+			return query, fmt.Errorf("cannot sort by unknown column %q", query.Sort)
+		}
 	}
 
-	response := models.Response{
-		Status:  http.StatusOK,
-		Message: "Data retrieved successfully",
-		Data:    data,
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageSize {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+		query.Limit = n
 	}
 
-	utils.SendJSONResponse(w, response, http.StatusOK)
+	if cursor := values.Get("cursor"); cursor != "" {
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
 
+// Cursors are opaque to clients; they encode the offset of the next page.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(raw), "o:") {
+		if offset, err := strconv.Atoi(string(raw[2:])); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, errors.New("invalid cursor")
 }
@@ -1,18 +1,28 @@
 package controller
 
 import (
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi"
-	"github.com/pufington-pixie/haver/pkg/database"
 	"github.com/pufington-pixie/haver/pkg/models"
 	"github.com/pufington-pixie/haver/utils"
 )
 
+// validateProject checks the fields the projects table requires.
+func validateProject(project models.Project) error {
+	if project.Name == "" {
+		return errors.New("project name is required")
+	}
+	if project.Service.ID == 0 {
+		return errors.New("project service id is required")
+	}
+	return nil
+}
+
 // InsertProject inserts a new project.
 // @Summary Insert a new project
 // @Description Insert a new project into the database
@@ -21,10 +31,11 @@ import (
 // @Produce json
 // @Param project body models.Project true "Project to be inserted"
 // @Success 200 {object} models.Project
-// @Failure 404 {} string "User not found"
+// @Failure 400 {object} models.Response
+// @Failure 409 {object} models.Response
 // @Failure 500 {object} models.Response
 // @Router /api/projects [post]
-func InsertProject(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) InsertProject(w http.ResponseWriter, r *http.Request) {
 	var response models.Response
 
 	// Parse JSON request body
@@ -42,25 +53,25 @@ func InsertProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db := database.GetDB()
-	defer db.Close()
-
-	// Prepare the SQL statements
-	projectQuery := "INSERT INTO projects (id, name, title, date, sapnumber, notes, branchId, statusId, serviceId) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	serviceQuery := "INSERT INTO services (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = ?"
+	for _, project := range projects {
+		if err := validateProject(project); err != nil {
+			utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	for _, project := range projects {
 		// Insert or update the service in the services table
-		_, err = db.Exec(serviceQuery, project.Service.ID, project.Service.Name, project.Service.Name)
+		err = c.store.Services.Upsert(r.Context(), project.Service)
 		if err != nil {
 			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 			return
 		}
 
 		// Insert project into the projects table
-		_, err = db.Exec(projectQuery, project.ID, project.Name, project.Title, project.Date, project.SAPNumber, project.Notes, project.BranchID, project.StatusID, project.Service.ID)
+		err = c.store.Projects.Create(r.Context(), project)
 		if err != nil {
-			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+			handleStoreError(w, err, "Service not found")
 			return
 		}
 	}
@@ -84,14 +95,12 @@ func InsertProject(w http.ResponseWriter, r *http.Request) {
 // @Param project body models.Project true "Project object to be updated"
 // @Success 200 {object} models.Project
 // @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
 // @Failure 500 {object} models.Response
 // @Router /api/projects/{id} [put]
-func UpdateProject(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	var response models.Response
 
-	db := database.GetDB()
-	defer db.Close()
-
 	// Read JSON request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -107,39 +116,38 @@ func UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Prepare the SQL statements
-	projectQuery := "UPDATE projects SET  name = ?, title = ?, sapnumber = ?, notes = ?, branchId = ?, statusId = ?, serviceId = ? WHERE id = ?"
-	serviceQuery := "INSERT INTO services (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = ?"
+	// The path identifies the project, the body only carries its fields
+	if idStr := chi.URLParam(r, "id"); idStr != "" {
+		project.ID, err = strconv.Atoi(idStr)
+		if err != nil {
+			utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+			return
+		}
+	}
 
-	// Update project data in the database
-	_, err = db.Exec(projectQuery, project.Name, project.Title, project.SAPNumber, project.Notes, project.BranchID, project.StatusID, project.Service.ID, project.ID)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+	if err := validateProject(project); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Update or insert the service data in the database
-	_, err = db.Exec(serviceQuery, project.Service.ID, project.Service.Name, project.Service.Name)
-	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+	// An unknown project must not leave a new or renamed service behind
+	if _, err := c.store.Projects.Get(r.Context(), project.ID); err != nil {
+		handleStoreError(w, err, "Project not found")
 		return
 	}
 
-	// Check if the serviceId exists in the services table
-	var count int
-	err = db.QueryRow("SELECT COUNT(*) FROM services WHERE id = ?", project.Service.ID).Scan(&count)
+	// Update or insert the service first so the project can reference it
+	err = c.store.Services.Upsert(r.Context(), project.Service)
 	if err != nil {
 		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 
-	if count == 0 {
-		// Insert a new row in the services table
-		_, err = db.Exec(serviceQuery, project.Service.ID, project.Service.Name, project.Service.Name)
-		if err != nil {
-			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
-			return
-		}
+	// Update project data in the database
+	err = c.store.Projects.Update(r.Context(), project)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
 	}
 
 	response = models.Response{
@@ -159,36 +167,14 @@ func UpdateProject(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} models.Project
 // @Failure 500 {object} models.Response
 // @Router /api/projects [get]
-func GetProject(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) GetProject(w http.ResponseWriter, r *http.Request) {
 	var response models.Response
 
-	db := database.GetDB()
-	defer db.Close()
-
-	rows, err := db.Query("SELECT p.id, p.name, p.title, p.sapnumber, p.notes, p.branchid, p.statusid, s.id, s.name " +
-		"FROM projects p " +
-		"JOIN services s ON p.serviceid = s.id")
+	arrProject, err := c.store.Projects.List(r.Context())
 	if err != nil {
 		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
-	defer rows.Close()
-
-	var arrProject []models.Project
-
-	for rows.Next() {
-		var project models.Project
-		var service models.Service
-		err = rows.Scan(&project.ID, &project.Name, &project.Title, &project.SAPNumber, &project.Notes, &project.BranchID, &project.StatusID, &service.ID, &service.Name)
-
-		if err != nil {
-			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
-			return
-		}
-
-		project.Service = service
-		arrProject = append(arrProject, project)
-	}
 
 	response.Status = http.StatusOK
 	response.Message = "Success"
@@ -207,7 +193,7 @@ func GetProject(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} models.Response
 // @Failure 500 {object} models.Response
 // @Router /api/projects/{id} [get]
-func GetProjectByID(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) GetProjectByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -215,18 +201,9 @@ func GetProjectByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db := database.GetDB()
-	defer db.Close()
-
-	project := models.Project{}
-	err = db.QueryRow("SELECT p.id, p.name, p.title, p.date, p.sapnumber, p.notes, p.branchId, p.statusId, p.serviceId, s.name FROM projects p JOIN services s ON p.serviceId = s.id WHERE p.id = ?", id).
-		Scan(&project.ID, &project.Name, &project.Title, &project.Date, &project.SAPNumber, &project.Notes, &project.BranchID, &project.StatusID, &project.Service.ID, &project.Service.Name)
+	project, err := c.store.Projects.Get(r.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			utils.HandleError(w, nil, http.StatusNotFound, "Project not found")
-		} else {
-			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
-		}
+		handleStoreError(w, err, "Project not found")
 		return
 	}
 
@@ -249,7 +226,7 @@ func GetProjectByID(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} models.Response
 // @Failure 500 {object} models.Response
 // @Router /api/projects/{id} [delete]
-func DeleteProject(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) DeleteProject(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -257,12 +234,9 @@ func DeleteProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db := database.GetDB()
-	defer db.Close()
-
-	_, err = db.Exec("DELETE FROM projects WHERE id = ?", id)
+	err = c.store.Projects.Delete(r.Context(), id)
 	if err != nil {
-		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		handleStoreError(w, err, "Project not found")
 		return
 	}
 
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/sensors"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// scalingRequest picks the datapoints to recalculate and what to change on
+// them before.
+type scalingRequest struct {
+	// PointIDs are the points to recalculate; every point with a sensor
+	// type when empty.
+	PointIDs []int `json:"pointIds"`
+	// SensorType replaces the sensor type of the points when set.
+	SensorType string `json:"sensorType"`
+	// Low and High replace the engineering range of the points when set.
+	Low  *float64 `json:"low"`
+	High *float64 `json:"high"`
+}
+
+// scaledPoint is a datapoint with its new scaling.
+type scaledPoint struct {
+	ID              int    `json:"id"`
+	PointName       string `json:"pointName"`
+	SensorType      string `json:"sensorType"`
+	EngineeringLow  string `json:"engineeringLow"`
+	EngineeringHigh string `json:"engineeringHigh"`
+	Slope           string `json:"slope"`
+	Intercept       string `json:"intercept"`
+}
+
+// scalingResult lists the points that were recalculated and those that
+// could not be.
+type scalingResult struct {
+	Updated []scaledPoint         `json:"updated"`
+	Skipped []models.PointProblem `json:"skipped"`
+}
+
+// GetSensors returns the sensor type catalogue.
+// @Summary Get the sensor types
+// @Description Get the sensor types Slope and Intercept can be computed for, with the range of their raw value. Temperature sensors report degrees Fahrenheit over their rated range.
+// @Tags scaling
+// @Produce json
+// @Success 200 {object} models.Response{data=[]sensors.Sensor}
+// @Router /api/sensors [get]
+func (c *Controller) GetSensors(w http.ResponseWriter, r *http.Request) {
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    sensors.Catalogue,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// ScaleDataPoints recalculates the Slope and Intercept of datapoints.
+// @Summary Recalculate the scaling of datapoints
+// @Description Compute Slope and Intercept from the raw range of the SensorType and the EngineeringLow and EngineeringHigh of each point, and store them.
+// @Description sensorType, low and high replace the stored values of the listed points first when given. Without pointIds every point of the project with a sensor type is recalculated. Points whose scaling cannot be computed are skipped.
+// @Tags scaling
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param scaling body scalingRequest true "Points and range"
+// @Success 200 {object} models.Response{data=scalingResult}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/scaling [post]
+func (c *Controller) ScaleDataPoints(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var req scalingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	if len(req.PointIDs) == 0 && (req.SensorType != "" || req.Low != nil || req.High != nil) {
+		utils.HandleError(w, nil, http.StatusBadRequest, "pointIds are required with sensorType, low or high")
+		return
+	}
+	if req.SensorType != "" {
+		sensor, ok := sensors.Lookup(req.SensorType)
+		if !ok {
+			utils.HandleError(w, nil, http.StatusBadRequest, fmt.Sprintf("unknown sensor type %q", req.SensorType))
+			return
+		}
+		req.SensorType = sensor.Name
+	}
+
+	points, ok := c.scalingPoints(w, r, projectID, req.PointIDs)
+	if !ok {
+		return
+	}
+
+	result := scalingResult{Updated: []scaledPoint{}, Skipped: []models.PointProblem{}}
+	var scaled []models.DataPoint
+	for _, dp := range points {
+		if len(req.PointIDs) == 0 && strings.TrimSpace(dp.SensorType) == "" {
+			continue
+		}
+		if req.SensorType != "" {
+			dp.SensorType = req.SensorType
+		}
+		if req.Low != nil {
+			dp.EngineeringLow = sensors.Format(*req.Low)
+		}
+		if req.High != nil {
+			dp.EngineeringHigh = sensors.Format(*req.High)
+		}
+
+		_, scaling, problem := sensors.Compute(dp)
+		if problem != nil {
+			result.Skipped = append(result.Skipped, *problem)
+			continue
+		}
+		sensors.Apply(&dp, scaling)
+
+		scaled = append(scaled, dp)
+		result.Updated = append(result.Updated, scaledPoint{
+			ID:              dp.ID,
+			PointName:       dp.PointName,
+			SensorType:      dp.SensorType,
+			EngineeringLow:  dp.EngineeringLow,
+			EngineeringHigh: dp.EngineeringHigh,
+			Slope:           dp.Slope,
+			Intercept:       dp.Intercept,
+		})
+	}
+
+	// All or nothing, like renaming. The scaling columns feed none of the
+	// derived equipment, networks or devices, so there is nothing to sync.
+	if len(scaled) > 0 {
+		if err := c.store.DataPoints.UpdateMany(r.Context(), scaled); err != nil {
+			handleStoreError(w, err, "Datapoint not found")
+			return
+		}
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Scaling updated successfully",
+		Data:    result,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// CheckScaling reports datapoints whose stored scaling disagrees with the
+// computed one.
+// @Summary Check the scaling of datapoints
+// @Description List the datapoints of a project whose Slope or Intercept differ from those computed from their SensorType, EngineeringLow and EngineeringHigh.
+// @Description Points with a sensor type or range whose scaling cannot be computed are listed as unchecked.
+// @Tags scaling
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param tolerance query number false "Allowed difference, relative to the slope and to the engineering span (default 0.001)"
+// @Success 200 {object} models.Response{data=sensors.Report}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/scaling [get]
+func (c *Controller) CheckScaling(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	tolerance := sensors.DefaultTolerance
+	if v := strings.TrimSpace(r.URL.Query().Get("tolerance")); v != "" {
+		tolerance, err = strconv.ParseFloat(v, 64)
+		if err != nil || tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+			utils.HandleError(w, err, http.StatusBadRequest, "tolerance must be a positive number")
+			return
+		}
+	}
+
+	points, ok := c.scalingPoints(w, r, projectID, nil)
+	if !ok {
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    sensors.Check(points, tolerance),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// scalingPoints loads the given datapoints of a project, or all of them
+// when ids is empty. It writes the error response and returns false when
+// that fails.
+func (c *Controller) scalingPoints(w http.ResponseWriter, r *http.Request, projectID int, ids []int) ([]models.DataPoint, bool) {
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return nil, false
+	}
+
+	if len(ids) == 0 {
+		points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+		if err != nil {
+			utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+			return nil, false
+		}
+		return points, true
+	}
+
+	points := make([]models.DataPoint, 0, len(ids))
+	for _, id := range ids {
+		dp, err := c.store.DataPoints.Get(r.Context(), projectID, id)
+		if err != nil {
+			handleStoreError(w, err, fmt.Sprintf("Datapoint %d not found", id))
+			return nil, false
+		}
+		points = append(points, dp)
+	}
+	return points, true
+}
@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/naming"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/templates"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// stampResult is the equipment stamped out of a template.
+type stampResult struct {
+	EquipIDs []string `json:"equipIds"`
+	Points   int      `json:"points"`
+}
+
+// GetTemplates returns the point template library.
+// @Summary Get the point templates
+// @Description Get the point template library, ordered by name
+// @Tags templates
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.PointTemplate}
+// @Failure 500 {object} models.Response
+// @Router /api/templates [get]
+func (c *Controller) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	list, err := c.store.Templates.List(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    list,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetTemplate returns a point template.
+// @Summary Get a point template
+// @Description Get a point template with its points
+// @Tags templates
+// @Produce json
+// @Param templateId path int true "Template ID"
+// @Success 200 {object} models.Response{data=models.PointTemplate}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/templates/{templateId} [get]
+func (c *Controller) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	t, err := c.store.Templates.Get(r.Context(), id)
+	if err != nil {
+		handleStoreError(w, err, "Template not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    t,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// CreateTemplate adds a point template to the library.
+// @Summary Create a point template
+// @Description Add the standard point set of an equipment type to the library. Every point needs a Function unique within the template.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body models.PointTemplate true "Template to be created"
+// @Success 201 {object} models.Response{data=models.PointTemplate}
+// @Failure 400 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/templates [post]
+func (c *Controller) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var t models.PointTemplate
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	t.ID = 0
+	if err := templates.Check(&t); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.store.Templates.Create(r.Context(), &t); err != nil {
+		handleStoreError(w, err, "Template not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusCreated,
+		Message: "Template created successfully",
+		Data:    t,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusCreated)
+}
+
+// UpdateTemplate replaces a point template.
+// @Summary Update a point template
+// @Description Replace a point template. Equipment stamped from it before keeps its points.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param templateId path int true "Template ID"
+// @Param template body models.PointTemplate true "Template to be stored"
+// @Success 200 {object} models.Response{data=models.PointTemplate}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/templates/{templateId} [put]
+func (c *Controller) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var t models.PointTemplate
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	t.ID = id
+	if err := templates.Check(&t); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.store.Templates.Update(r.Context(), t); err != nil {
+		handleStoreError(w, err, "Template not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Template updated successfully",
+		Data:    t,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// DeleteTemplate removes a point template from the library.
+// @Summary Delete a point template
+// @Description Remove a point template. Equipment stamped from it keeps its points.
+// @Tags templates
+// @Produce json
+// @Param templateId path int true "Template ID"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/templates/{templateId} [delete]
+func (c *Controller) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if err := c.store.Templates.Delete(r.Context(), id); err != nil {
+		handleStoreError(w, err, "Template not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Template deleted successfully",
+		Data:    nil,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// StampTemplate adds equipment made from a template to a project.
+// @Summary Stamp equipment out of a template
+// @Description Add count pieces of equipment to a project, each with the points of the template. EquipIDs are the prefix followed by a number counting up from start, zero padded to digits.
+// @Description Point names come from the project's point name template, or are EquipID.Function when it has none. Units are mapped onto the units catalogue like an import.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param templateId path int true "Template ID"
+// @Param stamp body templates.Stamp true "Equipment to be created"
+// @Success 201 {object} models.Response{data=stampResult}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/templates/{templateId}/stamp [post]
+func (c *Controller) StampTemplate(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	templateID, err := strconv.Atoi(chi.URLParam(r, "templateId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var stamp templates.Stamp
+	if err := json.NewDecoder(r.Body).Decode(&stamp); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	nc, err := c.namingConvention(r.Context(), projectID)
+	if err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+	t, err := c.store.Templates.Get(r.Context(), templateID)
+	if err != nil {
+		handleStoreError(w, err, "Template not found")
+		return
+	}
+
+	if err := stamp.Defaults(t); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// New equipment must not join equipment the project already has
+	existing, err := c.store.Equipment.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		taken[strings.ToLower(e.EquipID)] = true
+	}
+	equipIDs := stamp.EquipIDs()
+	for _, id := range equipIDs {
+		if taken[strings.ToLower(id)] {
+			utils.HandleError(w, store.ErrConflict, http.StatusConflict, fmt.Sprintf("EquipID %s is already used in the project", id))
+			return
+		}
+	}
+
+	convention, err := naming.Compile(nc)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	catalogue, err := c.unitCatalogue(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	points, err := stamp.Points(t)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	rows := make([][]string, len(points))
+	for i := range points {
+		dp := &points[i]
+		dp.ProjectID = projectID
+
+		dp.PointName, err = convention.Generate(*dp)
+		if errors.Is(err, naming.ErrNoTemplate) {
+			dp.PointName, err = dp.EquipID+"."+dp.Function, nil
+		}
+		if err == nil {
+			err = dp.Validate()
+		}
+		if err != nil {
+			err = fmt.Errorf("%s %s: %v", dp.EquipID, dp.Function, err)
+			utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if u, ok := catalogue.Lookup(dp.EngineeringUnits); ok {
+			dp.UnitId = &u.ID
+		}
+
+		rows[i] = make([]string, len(models.DataPointColumns))
+		for j, col := range models.DataPointColumns {
+			rows[i][j] = dp.Get(col.Name)
+		}
+	}
+
+	columns := make([]string, len(models.DataPointColumns))
+	for i, col := range models.DataPointColumns {
+		columns[i] = col.Name
+	}
+	if err := c.store.DataPoints.Import(r.Context(), projectID, columns, rows, store.ImportOptions{Mode: store.ImportAppend}); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+	c.syncDerived(r.Context(), projectID)
+
+	response := models.Response{
+		Status:  http.StatusCreated,
+		Message: "Equipment created successfully",
+		Data:    stampResult{EquipIDs: equipIDs, Points: len(points)},
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusCreated)
+}
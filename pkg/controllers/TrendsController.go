@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/trends"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// pointTrends is the trend configuration of a datapoint with the columns
+// that cannot be read.
+type pointTrends struct {
+	models.TrendConfig
+	Errors []string `json:"errors"`
+}
+
+// GetPointTrends returns the trend configuration of a datapoint.
+// @Summary Get the trends of a datapoint
+// @Description Get the change of value trend and the interval trend definitions of a datapoint, read from its trend columns.
+// @Description Columns that cannot be read are left out and listed in errors.
+// @Tags trends
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Success 200 {object} models.Response{data=pointTrends}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId}/trends [get]
+func (c *Controller) GetPointTrends(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	dp, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	cfg, errs := trends.Parse(dp)
+	if errs == nil {
+		errs = []string{}
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    pointTrends{TrendConfig: cfg, Errors: errs},
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// UpdatePointTrends replaces the trend configuration of a datapoint.
+// @Summary Update the trends of a datapoint
+// @Description Replace the change of value trend and the interval trend definitions of a datapoint. Every trend column is rewritten; definitions without a slot take the first free one.
+// @Description Intervals are HH:MM:SS, HH:MM, a number and unit such as 15 min or 1 hr, a duration such as 1h30m or a number of minutes. Collection is Auto, Manual or Off.
+// @Tags trends
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param pointId path int true "Datapoint ID"
+// @Param trends body models.TrendConfig true "Trend configuration"
+// @Success 200 {object} models.Response{data=models.TrendConfig}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/datapoints/{pointId}/trends [put]
+func (c *Controller) UpdatePointTrends(w http.ResponseWriter, r *http.Request) {
+	projectID, pointID, err := pointIDs(r)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var cfg models.TrendConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+	if cfg.Trends == nil {
+		cfg.Trends = []models.TrendDefinition{}
+	}
+
+	dp, err := c.store.DataPoints.Get(r.Context(), projectID, pointID)
+	if err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	if err := trends.Apply(&dp, &cfg); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Every trend column was rewritten, so all of them are checked
+	if !checkTrends(w, models.DataPoint{}, dp) {
+		return
+	}
+
+	if err := c.store.DataPoints.Update(r.Context(), dp); err != nil {
+		handleStoreError(w, err, "Datapoint not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Trends updated successfully",
+		Data:    cfg,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetTrendSummary adds up the trend load of a project per panel.
+// @Summary Get the trend load of a project
+// @Description Count the trended points, trend definitions, buffered panel samples and interval samples per day of every panel of a project.
+// @Description Points are assigned to panels by DeviceSysName, or NodeIdentifier when they have no system name. Panels buffering more samples than a panel holds are flagged.
+// @Tags trends
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} models.Response{data=trends.Summary}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/projects/{id}/trends [get]
+func (c *Controller) GetTrendSummary(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if _, err := c.store.Projects.Get(r.Context(), projectID); err != nil {
+		handleStoreError(w, err, "Project not found")
+		return
+	}
+
+	points, err := c.store.DataPoints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    trends.Summarize(points, trends.DefaultLimits),
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// checkTrends validates the trend columns of dp that differ from before, the
+// stored point. Values an edit leaves alone are not held against it. It
+// writes the error response and returns false when dp must not be stored.
+func checkTrends(w http.ResponseWriter, before, dp models.DataPoint) bool {
+	if errs := trends.CheckChanged(before, dp, trends.DefaultLimits); len(errs) > 0 {
+		msg := strings.Join(errs, "; ")
+		utils.HandleError(w, errors.New(msg), http.StatusBadRequest, msg)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/units"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// unitCatalogue loads the units catalogue with its aliases.
+func (c *Controller) unitCatalogue(ctx context.Context) (*units.Catalogue, error) {
+	list, err := c.store.Units.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := c.store.Units.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return units.New(list, aliases), nil
+}
+
+// mapUnit looks the UnitId of dp up again when its EngineeringUnits or
+// NavigatorUnits differ from before, the stored point, so that the exports
+// do not keep showing the old unit. A UnitId the body changes itself is
+// kept; one the catalogue has no unit for is cleared.
+func (c *Controller) mapUnit(ctx context.Context, before models.DataPoint, dp *models.DataPoint) error {
+	if dp.EngineeringUnits == before.EngineeringUnits && dp.NavigatorUnits == before.NavigatorUnits {
+		return nil
+	}
+	if dp.UnitId != nil && (before.UnitId == nil || *dp.UnitId != *before.UnitId) {
+		return nil
+	}
+
+	catalogue, err := c.unitCatalogue(ctx)
+	if err != nil {
+		return err
+	}
+	dp.UnitId = nil
+	for _, spelling := range []string{dp.EngineeringUnits, dp.NavigatorUnits} {
+		if u, ok := catalogue.Lookup(spelling); ok && spelling != "" {
+			dp.UnitId = &u.ID
+			break
+		}
+	}
+	return nil
+}
+
+// GetUnits returns the units catalogue.
+// @Summary Get the units catalogue
+// @Description Get the engineering units point list units are mapped onto, the BACnet engineering units enumeration
+// @Tags units
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.Unit}
+// @Failure 500 {object} models.Response
+// @Router /api/units [get]
+func (c *Controller) GetUnits(w http.ResponseWriter, r *http.Request) {
+	list, err := c.store.Units.List(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    list,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// GetUnitAliases returns the unit aliases.
+// @Summary Get the unit aliases
+// @Description Get the spellings mapped onto catalogue units besides their names and symbols
+// @Tags units
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.UnitAlias}
+// @Failure 500 {object} models.Response
+// @Router /api/units/aliases [get]
+func (c *Controller) GetUnitAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := c.store.Units.ListAliases(r.Context())
+	if err != nil {
+		utils.HandleError(w, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    aliases,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
+
+// CreateUnitAlias adds a unit alias.
+// @Summary Create a unit alias
+// @Description Map a spelling onto a catalogue unit for future imports. The alias is stored normalized: lower case, "°" as "deg" and without spaces or punctuation.
+// @Tags units
+// @Accept json
+// @Produce json
+// @Param alias body models.UnitAlias true "Alias to be created"
+// @Success 201 {object} models.Response{data=models.UnitAlias}
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 409 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/units/aliases [post]
+func (c *Controller) CreateUnitAlias(w http.ResponseWriter, r *http.Request) {
+	var alias models.UnitAlias
+	if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	alias.ID = 0
+	alias.Alias = units.Normalize(alias.Alias)
+	if alias.Alias == "" {
+		err := errors.New("alias is required")
+		utils.HandleError(w, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.store.Units.CreateAlias(r.Context(), &alias); err != nil {
+		handleStoreError(w, err, "Unit not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusCreated,
+		Message: "Unit alias created successfully",
+		Data:    alias,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusCreated)
+}
+
+// DeleteUnitAlias deletes a unit alias.
+// @Summary Delete a unit alias
+// @Description Remove a spelling from the unit aliases
+// @Tags units
+// @Produce json
+// @Param aliasId path int true "Alias ID"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.Response
+// @Failure 404 {object} models.Response
+// @Failure 500 {object} models.Response
+// @Router /api/units/aliases/{aliasId} [delete]
+func (c *Controller) DeleteUnitAlias(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "aliasId"))
+	if err != nil {
+		utils.HandleError(w, err, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if err := c.store.Units.DeleteAlias(r.Context(), id); err != nil {
+		handleStoreError(w, err, "Unit alias not found")
+		return
+	}
+
+	response := models.Response{
+		Status:  http.StatusOK,
+		Message: "Unit alias deleted successfully",
+		Data:    nil,
+	}
+
+	utils.SendJSONResponse(w, response, http.StatusOK)
+}
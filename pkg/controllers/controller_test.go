@@ -0,0 +1,461 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	controller "github.com/pufington-pixie/haver/pkg/controllers"
+	"github.com/pufington-pixie/haver/pkg/imports"
+	"github.com/pufington-pixie/haver/pkg/models"
+	routers "github.com/pufington-pixie/haver/pkg/routes"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/store/memstore"
+)
+
+// newServer returns the API over a memstore holding project 1 of service 1.
+func newServer(t *testing.T) (http.Handler, store.Store) {
+	t.Helper()
+	ctx := context.Background()
+	s := memstore.New()
+	if err := s.Services.Upsert(ctx, models.Service{ID: 1, Name: "Controls"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Projects.Create(ctx, models.Project{ID: 1, Name: "Project 1", Service: models.Service{ID: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := imports.NewService(s, imports.Options{UploadDir: t.TempDir()})
+	service.Start()
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+
+	c := controller.New(s, controller.Options{Imports: service})
+	return routers.NewRouter(c), s
+}
+
+// do sends a request and returns the status and decoded response.
+func do(t *testing.T, h http.Handler, method, path, body string) (int, models.Response) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	var response models.Response
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code, response
+}
+
+func TestProjectErrors(t *testing.T) {
+	h, _ := newServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"get unknown", http.MethodGet, "/api/projects/99", "", http.StatusNotFound},
+		{"get bad id", http.MethodGet, "/api/projects/abc", "", http.StatusBadRequest},
+		{"insert bad body", http.MethodPost, "/api/projects", "{", http.StatusBadRequest},
+		{"insert without name", http.MethodPost, "/api/projects", `[{"services":{"id":1}}]`, http.StatusBadRequest},
+		{"insert without service", http.MethodPost, "/api/projects", `[{"name":"P"}]`, http.StatusBadRequest},
+		{"insert duplicate", http.MethodPost, "/api/projects", `[{"id":1,"name":"P","services":{"id":1}}]`, http.StatusConflict},
+		{"update bad id", http.MethodPut, "/api/projects/abc", `{"name":"P","services":{"id":1}}`, http.StatusBadRequest},
+		{"update bad body", http.MethodPut, "/api/projects/1", "{", http.StatusBadRequest},
+		{"update without name", http.MethodPut, "/api/projects/1", `{"services":{"id":1}}`, http.StatusBadRequest},
+		{"update unknown", http.MethodPut, "/api/projects/99", `{"name":"P","services":{"id":1}}`, http.StatusNotFound},
+		{"delete unknown", http.MethodDelete, "/api/projects/99", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, response := do(t, h, tt.method, tt.path, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%s)", got, tt.want, response.Message)
+			}
+		})
+	}
+}
+
+func TestUpdateProjectUpsertsService(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	status, response := do(t, h, http.MethodPut, "/api/projects/1", `{"name":"Renamed","services":{"id":2,"serviceName":"Commissioning"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", status, response.Message)
+	}
+
+	service, err := s.Services.Get(ctx, 2)
+	if err != nil {
+		t.Fatalf("service 2 was not inserted: %v", err)
+	}
+	if service.Name != "Commissioning" {
+		t.Errorf("service name = %q, want Commissioning", service.Name)
+	}
+	project, err := s.Projects.Get(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "Renamed" || project.Service.ID != 2 {
+		t.Errorf("project = %q of service %d, want Renamed of service 2", project.Name, project.Service.ID)
+	}
+
+	// Renaming the service of an existing project
+	status, _ = do(t, h, http.MethodPut, "/api/projects/1", `{"name":"Renamed","services":{"id":2,"serviceName":"Service"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if service, _ := s.Services.Get(ctx, 2); service.Name != "Service" {
+		t.Errorf("service name = %q, want Service", service.Name)
+	}
+}
+
+func TestUpdateUnknownProjectKeepsServices(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	status, _ := do(t, h, http.MethodPut, "/api/projects/99", `{"name":"P","services":{"id":1,"serviceName":"Renamed"}}`)
+	if status != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", status)
+	}
+	status, _ = do(t, h, http.MethodPut, "/api/projects/99", `{"name":"P","services":{"id":3,"serviceName":"New"}}`)
+	if status != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", status)
+	}
+
+	if service, _ := s.Services.Get(ctx, 1); service.Name != "Controls" {
+		t.Errorf("service 1 renamed to %q", service.Name)
+	}
+	if _, err := s.Services.Get(ctx, 3); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("service 3: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDataPointErrors(t *testing.T) {
+	h, _ := newServer(t)
+
+	status, response := do(t, h, http.MethodPost, "/api/projects/1/datapoints", `{"EquipID":"AHU-1","point_name":"AHU-1.SAT"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201 (%s)", status, response.Message)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"list unknown project", http.MethodGet, "/api/projects/99/datapoints", "", http.StatusNotFound},
+		{"get unknown", http.MethodGet, "/api/projects/1/datapoints/99", "", http.StatusNotFound},
+		{"get other project", http.MethodGet, "/api/projects/99/datapoints/1", "", http.StatusNotFound},
+		{"get bad id", http.MethodGet, "/api/projects/1/datapoints/abc", "", http.StatusBadRequest},
+		{"create bad project id", http.MethodPost, "/api/projects/abc/datapoints", `{"EquipID":"AHU-1"}`, http.StatusBadRequest},
+		{"create bad body", http.MethodPost, "/api/projects/1/datapoints", "{", http.StatusBadRequest},
+		{"create without EquipID", http.MethodPost, "/api/projects/1/datapoints", `{"point_name":"AHU-1.RAT"}`, http.StatusBadRequest},
+		{"create too long", http.MethodPost, "/api/projects/1/datapoints", `{"EquipID":"` + strings.Repeat("x", 46) + `"}`, http.StatusBadRequest},
+		{"create unknown project", http.MethodPost, "/api/projects/99/datapoints", `{"EquipID":"AHU-1"}`, http.StatusNotFound},
+		{"update unknown", http.MethodPut, "/api/projects/1/datapoints/99", `{"EquipID":"AHU-1"}`, http.StatusNotFound},
+		{"update without EquipID", http.MethodPut, "/api/projects/1/datapoints/1", `{"point_name":"AHU-1.SAT"}`, http.StatusBadRequest},
+		{"patch unknown", http.MethodPatch, "/api/projects/1/datapoints/99", `{"descriptor":"x"}`, http.StatusNotFound},
+		{"patch bad body", http.MethodPatch, "/api/projects/1/datapoints/1", "{", http.StatusBadRequest},
+		{"delete unknown", http.MethodDelete, "/api/projects/1/datapoints/99", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, response := do(t, h, tt.method, tt.path, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%s)", got, tt.want, response.Message)
+			}
+		})
+	}
+}
+
+func TestPatchDataPointKeepsColumns(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	status, _ := do(t, h, http.MethodPost, "/api/projects/1/datapoints", `{"EquipID":"AHU-1","point_name":"AHU-1.SAT","descriptor":"Supply temp"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201", status)
+	}
+
+	status, response := do(t, h, http.MethodPatch, "/api/projects/1/datapoints/1", `{"descriptor":"Supply air temp"}`)
+	if status != http.StatusOK {
+		t.Fatalf("patch: status = %d, want 200 (%s)", status, response.Message)
+	}
+
+	dp, err := s.DataPoints.Get(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dp.Descriptor != "Supply air temp" {
+		t.Errorf("Descriptor = %q, want Supply air temp", dp.Descriptor)
+	}
+	if dp.EquipID != "AHU-1" || dp.PointName != "AHU-1.SAT" {
+		t.Errorf("patch changed other columns: EquipID %q, PointName %q", dp.EquipID, dp.PointName)
+	}
+}
+
+func TestEditKeepsLegacyTrends(t *testing.T) {
+	h, s := newServer(t)
+
+	// Stored before intervals were checked
+	dp := models.DataPoint{ProjectID: 1, EquipID: "AHU-1", PointName: "AHU-1.SAT", TrendInterval1: "every 15"}
+	if err := s.DataPoints.Create(context.Background(), &dp); err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/api/projects/1/datapoints/%d", dp.ID)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"patch another column", http.MethodPatch, `{"descriptor":"Supply"}`, http.StatusOK},
+		{"put keeping the interval", http.MethodPut, `{"EquipID":"AHU-1","TrendInterval1":"every 15"}`, http.StatusOK},
+		{"patch the interval", http.MethodPatch, `{"TrendInterval1":"every 20"}`, http.StatusBadRequest},
+		{"patch a readable interval", http.MethodPatch, `{"TrendInterval1":"15 min"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, response := do(t, h, tt.method, path, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%s)", got, tt.want, response.Message)
+			}
+		})
+	}
+}
+
+func TestCapacityThresholdErrors(t *testing.T) {
+	h, _ := newServer(t)
+
+	for _, query := range []string{"covPerHour=NaN", "blnMessages=Inf", "mstpMessages=-1", "panelSamples=lots"} {
+		t.Run(query, func(t *testing.T) {
+			if got, response := do(t, h, http.MethodGet, "/api/projects/1/capacity?"+query, ""); got != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", got, response.Message)
+			}
+		})
+	}
+	if got, response := do(t, h, http.MethodGet, "/api/projects/1/capacity?covPerHour=2.5", ""); got != http.StatusOK {
+		t.Errorf("status = %d, want 200 (%s)", got, response.Message)
+	}
+}
+
+func TestDeviceErrors(t *testing.T) {
+	h, _ := newServer(t)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"without name", `{"kind":"panel"}`, http.StatusBadRequest},
+		{"unknown kind", `{"name":"PXC-1","kind":"router"}`, http.StatusBadRequest},
+		{"name too long", `{"name":"` + strings.Repeat("x", 46) + `","kind":"panel"}`, http.StatusBadRequest},
+		{"network too long", `{"name":"PXC-1","kind":"panel","networkType":"BLN","network":"` + strings.Repeat("x", 65) + `"}`, http.StatusBadRequest},
+		{"address too long", `{"name":"PXC-1","kind":"panel","address":"` + strings.Repeat("x", 175) + `"}`, http.StatusBadRequest},
+		{"ip address and port", `{"name":"PXC-1","kind":"panel","address":"` + strings.Repeat("f", 45) + `:47808"}`, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, response := do(t, h, http.MethodPost, "/api/projects/1/devices", tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%s)", got, tt.want, response.Message)
+			}
+		})
+	}
+}
+
+func TestScaleDataPoints(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	for _, dp := range []models.DataPoint{
+		{ProjectID: 1, EquipID: "AHU-1", PointName: "SAT", SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100"},
+		{ProjectID: 1, EquipID: "AHU-1", PointName: "RAT", SensorType: "strain gauge"},
+	} {
+		if err := s.DataPoints.Create(ctx, &dp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status, response := do(t, h, http.MethodPost, "/api/projects/1/datapoints/scaling", `{}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", status, response.Message)
+	}
+	dp, err := s.DataPoints.Get(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dp.Slope != "6.25" || dp.Intercept != "-25" {
+		t.Errorf("scaling = %s, %s, want 6.25, -25", dp.Slope, dp.Intercept)
+	}
+
+	for _, query := range []string{"NaN", "Inf", "-1", "tight"} {
+		t.Run("tolerance "+query, func(t *testing.T) {
+			if got, response := do(t, h, http.MethodGet, "/api/projects/1/datapoints/scaling?tolerance="+query, ""); got != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", got, response.Message)
+			}
+		})
+	}
+}
+
+func TestEditKeepsLegacyAddressing(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	// Stored before addressing was checked: an invalid MAC and an object
+	// another point uses too
+	for _, dp := range []models.DataPoint{
+		{ProjectID: 1, EquipID: "AHU-1", PointName: "SAT", BACnetDeviceInstance: "100", BACnetObjectType: "AI", BACnetObjectInstance: "1", MSTPaddress: "200"},
+		{ProjectID: 1, EquipID: "AHU-1", PointName: "RAT", BACnetDeviceInstance: "100", BACnetObjectType: "AI", BACnetObjectInstance: "1"},
+	} {
+		if err := s.DataPoints.Create(ctx, &dp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"patch another column", `{"descriptor":"Supply"}`, http.StatusOK},
+		{"respell the object type", `{"BACnetObjectType":"analog-input"}`, http.StatusOK},
+		{"patch the MAC", `{"MSTPaddress":"201"}`, http.StatusBadRequest},
+		{"patch to a free object", `{"BACnetObjectInstance":"2"}`, http.StatusOK},
+		{"patch back to the used object", `{"BACnetObjectInstance":"1"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, response := do(t, h, http.MethodPatch, "/api/projects/1/datapoints/1", tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d (%s)", got, tt.want, response.Message)
+			}
+		})
+	}
+}
+
+func TestEditMapsUnits(t *testing.T) {
+	h, s := newServer(t)
+	ctx := context.Background()
+
+	status, response := do(t, h, http.MethodPost, "/api/projects/1/datapoints", `{"EquipID":"AHU-1","EngineeringUnits":"°F"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201 (%s)", status, response.Message)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   *int
+	}{
+		{"created", "", "", intPtr(64)},
+		{"patch another column", http.MethodPatch, `{"descriptor":"Supply"}`, intPtr(64)},
+		{"patch the units", http.MethodPatch, `{"EngineeringUnits":"%"}`, intPtr(98)},
+		{"patch to unknown units", http.MethodPatch, `{"EngineeringUnits":"furlongs"}`, nil},
+		{"put navigator units", http.MethodPut, `{"EquipID":"AHU-1","NavigatorUnits":"percent"}`, intPtr(98)},
+		{"patch units and id", http.MethodPatch, `{"EngineeringUnits":"°F","UnitId":62}`, intPtr(62)},
+	}
+	for _, tt := range tests {
+		if tt.method != "" {
+			if status, response := do(t, h, tt.method, "/api/projects/1/datapoints/1", tt.body); status != http.StatusOK {
+				t.Fatalf("%s: status = %d, want 200 (%s)", tt.name, status, response.Message)
+			}
+		}
+		dp, err := s.DataPoints.Get(ctx, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (dp.UnitId == nil) != (tt.want == nil) || dp.UnitId != nil && *dp.UnitId != *tt.want {
+			t.Errorf("%s: UnitId = %v, want %v", tt.name, fmtInt(dp.UnitId), fmtInt(tt.want))
+		}
+	}
+}
+
+func TestUpload(t *testing.T) {
+	// An unknown column and a row without its EquipID
+	const file = "EquipID,PointName,Colour\nAHU-1,SAT,red\n,RAT,blue\n"
+
+	tests := []struct {
+		name    string
+		query   string
+		want    int
+		points  []string
+		imports int
+	}{
+		{"rejected rows reject the file", "", http.StatusUnprocessableEntity, []string{"OAT"}, 1},
+		{"partial", "partial=true", http.StatusOK, []string{"OAT", "SAT"}, 1},
+		{"strict", "strict=true&partial=true", http.StatusBadRequest, []string{"OAT"}, 1},
+		{"replace", "replace=true&partial=true", http.StatusOK, []string{"SAT"}, 1},
+		{"dry run", "dryRun=true&replace=true&partial=true", http.StatusOK, []string{"OAT"}, 0},
+		{"strict dry run", "dryRun=true&strict=true", http.StatusBadRequest, []string{"OAT"}, 0},
+		{"bad preview size", "dryRun=true&previewRows=-1", http.StatusBadRequest, []string{"OAT"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, s := newServer(t)
+			ctx := context.Background()
+			if err := s.DataPoints.Create(ctx, &models.DataPoint{ProjectID: 1, EquipID: "AHU-1", PointName: "OAT"}); err != nil {
+				t.Fatal(err)
+			}
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("file", "points.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.WriteString(part, file)
+			form.Close()
+
+			r := httptest.NewRequest(http.MethodPost, "/api/upload/1?"+tt.query, &body)
+			r.Header.Set("Content-Type", form.FormDataContentType())
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.want, w.Body.String())
+			}
+
+			points, err := s.DataPoints.ListByProject(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, dp := range points {
+				names = append(names, dp.PointName)
+			}
+			if !reflect.DeepEqual(names, tt.points) {
+				t.Errorf("points = %q, want %q", names, tt.points)
+			}
+
+			imports, err := s.Imports.ListByProject(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(imports) != tt.imports {
+				t.Errorf("%d imports recorded, want %d", len(imports), tt.imports)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func fmtInt(n *int) string {
+	if n == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*n)
+}
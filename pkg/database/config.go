@@ -0,0 +1,177 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings used to build the shared connection pool.
+type Config struct {
+	// DSN is the go-sql-driver/mysql data source name.
+	DSN string
+
+	// MaxOpenConns is the maximum number of open connections in the pool.
+	MaxOpenConns int
+
+	// MaxIdleConns is the maximum number of idle connections kept in the pool.
+	MaxIdleConns int
+
+	// ConnMaxLifetime is the maximum amount of time a connection may be reused.
+	ConnMaxLifetime time.Duration
+
+	// ConnMaxIdleTime is the maximum amount of time a connection may be idle.
+	ConnMaxIdleTime time.Duration
+
+	// ConnectTimeout bounds dialing a new connection and the startup ping.
+	ConnectTimeout time.Duration
+
+	// ReadTimeout and WriteTimeout are the driver level I/O timeouts.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// DefaultConfig returns the pool settings used when nothing is configured.
+func DefaultConfig() Config {
+	return Config{
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: time.Minute,
+		ConnectTimeout:  5 * time.Second,
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+	}
+}
+
+// environment mirrors one environment block of dbconfig.yml. The datasource
+// key is shared with sql-migrate; the pool keys are optional.
+type environment struct {
+	Datasource      string `yaml:"datasource"`
+	MaxOpenConns    int    `yaml:"maxOpenConns"`
+	MaxIdleConns    int    `yaml:"maxIdleConns"`
+	ConnMaxLifetime string `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime string `yaml:"connMaxIdleTime"`
+}
+
+// LoadConfig builds a Config from the defaults, the environment block of
+// dbconfig.yml selected by DB_ENV (default "development") and finally the
+// DB_* environment variables, each layer overriding the previous one.
+//
+// The config file path can be changed with DB_CONFIG. A missing file is not
+// an error as long as DB_DSN is set.
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	path := getenv("DB_CONFIG", "dbconfig.yml")
+	env := getenv("DB_ENV", "development")
+	if err := cfg.loadFile(path, env); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return cfg, err
+	}
+
+	if cfg.DSN == "" {
+		return cfg, errors.New("database: no DSN configured, set DB_DSN or a datasource in " + path)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) loadFile(path, env string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("database: reading %s: %w", path, err)
+	}
+
+	var envs map[string]environment
+	if err := yaml.Unmarshal(data, &envs); err != nil {
+		return fmt.Errorf("database: parsing %s: %w", path, err)
+	}
+
+	e, ok := envs[env]
+	if !ok {
+		return nil
+	}
+
+	if e.Datasource != "" {
+		cfg.DSN = os.ExpandEnv(e.Datasource)
+	}
+	if e.MaxOpenConns > 0 {
+		cfg.MaxOpenConns = e.MaxOpenConns
+	}
+	if e.MaxIdleConns > 0 {
+		cfg.MaxIdleConns = e.MaxIdleConns
+	}
+	if err := parseDuration(e.ConnMaxLifetime, &cfg.ConnMaxLifetime); err != nil {
+		return fmt.Errorf("database: %s connMaxLifetime: %w", path, err)
+	}
+	if err := parseDuration(e.ConnMaxIdleTime, &cfg.ConnMaxIdleTime); err != nil {
+		return fmt.Errorf("database: %s connMaxIdleTime: %w", path, err)
+	}
+
+	return nil
+}
+
+func (cfg *Config) loadEnv() error {
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		cfg.DSN = dsn
+	}
+
+	ints := map[string]*int{
+		"DB_MAX_OPEN_CONNS": &cfg.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS": &cfg.MaxIdleConns,
+	}
+	for key, dst := range ints {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("database: %s: %w", key, err)
+			}
+			*dst = n
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"DB_CONN_MAX_LIFETIME":  &cfg.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME": &cfg.ConnMaxIdleTime,
+		"DB_CONNECT_TIMEOUT":    &cfg.ConnectTimeout,
+		"DB_READ_TIMEOUT":       &cfg.ReadTimeout,
+		"DB_WRITE_TIMEOUT":      &cfg.WriteTimeout,
+	}
+	for key, dst := range durations {
+		if err := parseDuration(os.Getenv(key), dst); err != nil {
+			return fmt.Errorf("database: %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func parseDuration(s string, dst *time.Duration) error {
+	if s == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
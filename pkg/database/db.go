@@ -1,43 +1,54 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
- 
-func ConnectDB() (*sql.DB,error) {
-	dbDriver := "mysql"
-    dbUser := "root"
-    dbPass := "ganesh"
-    dbName := "project"
-	
-    // Open the database connection
-    db, err := sql.Open(dbDriver, dbUser+":"+dbPass+"@/"+dbName+ "?parseTime=true")
-    if err != nil {
-        log.Fatal(err)
-    }
-   
-
-    // Ping the database to ensure the connection is established
-    err = db.Ping()
-    if err != nil {
-        log.Fatal(err)
-    }
-
-    // Connection established
-    fmt.Println("Connected to the database!")
-    return db,nil
-    
-}
 
-// GetDB returns a database object
-func GetDB() (*sql.DB) {
-	db,err := ConnectDB()
-	if err!=nil{
-		log.Fatal(err)
+// Open builds the long-lived connection pool described by cfg and verifies it
+// with a ping. The caller owns the returned pool and must close it on shutdown.
+func Open(cfg Config) (*sql.DB, error) {
+	dsn, err := mysql.ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("database: invalid DSN: %w", err)
+	}
+
+	// The models scan DATETIME columns straight into time.Time.
+	dsn.ParseTime = true
+	if cfg.ConnectTimeout > 0 {
+		dsn.Timeout = cfg.ConnectTimeout
+	}
+	if cfg.ReadTimeout > 0 {
+		dsn.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout > 0 {
+		dsn.WriteTimeout = cfg.WriteTimeout
+	}
+
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("database: open: %w", err)
 	}
-	return db
-  }
\ No newline at end of file
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx := context.Background()
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database: ping: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,194 @@
+// Package diff compares two point lists, such as a project's points and a
+// contractor's revised schedule, point by point.
+//
+// Points are matched on EquipID and PointName, ignoring case and surrounding
+// spaces. A key held by several points pairs them up in list order.
+package diff
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// The kinds of change, as written to the Change column of a CSV diff.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// Field is a column whose value differs between the two lists.
+type Field struct {
+	Column string `json:"column"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Point is a point found in one list only, or in both with different values.
+// BeforeID and AfterID are the IDs of the stored points, 0 for points read
+// from a file or missing from that side.
+type Point struct {
+	EquipID   string  `json:"EquipID"`
+	PointName string  `json:"point_name"`
+	BeforeID  int     `json:"beforeId,omitempty"`
+	AfterID   int     `json:"afterId,omitempty"`
+	Fields    []Field `json:"fields,omitempty"`
+}
+
+// Result is the difference between two point lists.
+type Result struct {
+	// Columns are the columns that were compared.
+	Columns   []string `json:"columns"`
+	Added     []Point  `json:"added"`
+	Removed   []Point  `json:"removed"`
+	Modified  []Point  `json:"modified"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// KeyColumns are the columns points are matched on. They are never compared.
+var KeyColumns = []string{"EquipID", "PointName"}
+
+// Columns returns every datapoints column but the key columns.
+func Columns() []string {
+	var columns []string
+	for _, c := range models.DataPointColumns {
+		if !isKey(c.Name) {
+			columns = append(columns, c.Name)
+		}
+	}
+	return columns
+}
+
+// Compare returns what changed going from before to after. Only the given
+// columns are compared; the key columns are skipped. Points are listed in
+// EquipID and PointName order.
+func Compare(before, after []models.DataPoint, columns []string) *Result {
+	res := &Result{
+		Added:    []Point{},
+		Removed:  []Point{},
+		Modified: []Point{},
+	}
+	for _, c := range columns {
+		if !isKey(c) {
+			res.Columns = append(res.Columns, c)
+		}
+	}
+
+	// The after points still to be paired, by key in list order
+	pending := make(map[string][]int)
+	for i := range after {
+		k := key(after[i])
+		pending[k] = append(pending[k], i)
+	}
+
+	for i := range before {
+		b := &before[i]
+		k := key(*b)
+		if len(pending[k]) == 0 {
+			res.Removed = append(res.Removed, point(*b, b.ID, 0))
+			continue
+		}
+		a := &after[pending[k][0]]
+		pending[k] = pending[k][1:]
+
+		p := point(*a, b.ID, a.ID)
+		for _, c := range res.Columns {
+			if bv, av := b.Get(c), a.Get(c); bv != av {
+				p.Fields = append(p.Fields, Field{Column: c, Before: bv, After: av})
+			}
+		}
+		if len(p.Fields) == 0 {
+			res.Unchanged++
+			continue
+		}
+		res.Modified = append(res.Modified, p)
+	}
+
+	for i := range after {
+		a := &after[i]
+		if k := key(*a); len(pending[k]) > 0 && pending[k][0] == i {
+			pending[k] = pending[k][1:]
+			res.Added = append(res.Added, point(*a, 0, a.ID))
+		}
+	}
+
+	for _, points := range [][]Point{res.Added, res.Removed, res.Modified} {
+		sort.SliceStable(points, func(i, j int) bool {
+			return less(points[i], points[j])
+		})
+	}
+
+	return res
+}
+
+// Collisions returns the points of after that share EquipID and PointName
+// with a point of before, with BeforeID set to that point. Appending after to
+// before would leave both in the list.
+func Collisions(before, after []models.DataPoint) []Point {
+	ids := make(map[string]int, len(before))
+	for i := range before {
+		if k := key(before[i]); ids[k] == 0 {
+			ids[k] = before[i].ID
+		}
+	}
+
+	points := []Point{}
+	for i := range after {
+		if id, ok := ids[key(after[i])]; ok {
+			points = append(points, point(after[i], id, after[i].ID))
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return less(points[i], points[j])
+	})
+	return points
+}
+
+// Records returns the result as rows of a flat table with a header, one row
+// per added or removed point and one per changed column of a modified point.
+func (r *Result) Records() [][]string {
+	records := [][]string{{"Change", "EquipID", "PointName", "Column", "Before", "After"}}
+	for _, p := range r.Added {
+		records = append(records, []string{ChangeAdded, p.EquipID, p.PointName, "", "", ""})
+	}
+	for _, p := range r.Removed {
+		records = append(records, []string{ChangeRemoved, p.EquipID, p.PointName, "", "", ""})
+	}
+	for _, p := range r.Modified {
+		for _, f := range p.Fields {
+			records = append(records, []string{ChangeModified, p.EquipID, p.PointName, f.Column, f.Before, f.After})
+		}
+	}
+	return records
+}
+
+func point(dp models.DataPoint, beforeID, afterID int) Point {
+	return Point{
+		EquipID:   strings.TrimSpace(dp.EquipID),
+		PointName: strings.TrimSpace(dp.PointName),
+		BeforeID:  beforeID,
+		AfterID:   afterID,
+	}
+}
+
+func key(dp models.DataPoint) string {
+	return strings.ToLower(strings.TrimSpace(dp.EquipID)) + "\x00" + strings.ToLower(strings.TrimSpace(dp.PointName))
+}
+
+func less(a, b Point) bool {
+	if ea, eb := strings.ToLower(a.EquipID), strings.ToLower(b.EquipID); ea != eb {
+		return ea < eb
+	}
+	return strings.ToLower(a.PointName) < strings.ToLower(b.PointName)
+}
+
+func isKey(column string) bool {
+	for _, k := range KeyColumns {
+		if strings.EqualFold(k, column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestCompare(t *testing.T) {
+	columns := []string{"EquipID", "Descriptor", "EngineeringUnits"}
+
+	tests := []struct {
+		name   string
+		before []models.DataPoint
+		after  []models.DataPoint
+		want   Result
+	}{
+		{
+			name: "empty",
+			want: Result{},
+		},
+		{
+			name:   "unchanged, matched ignoring case and spaces",
+			before: []models.DataPoint{{ID: 1, EquipID: "AHU-1", PointName: "SAT", Descriptor: "Supply"}},
+			after:  []models.DataPoint{{EquipID: " ahu-1 ", PointName: "sat", Descriptor: "Supply"}},
+			want:   Result{Unchanged: 1},
+		},
+		{
+			name:   "modified",
+			before: []models.DataPoint{{ID: 1, EquipID: "AHU-1", PointName: "SAT", Descriptor: "Supply", EngineeringUnits: "degF"}},
+			after:  []models.DataPoint{{ID: 7, EquipID: "AHU-1", PointName: "SAT", Descriptor: "Supply air", EngineeringUnits: "degF"}},
+			want: Result{Modified: []Point{{
+				EquipID: "AHU-1", PointName: "SAT", BeforeID: 1, AfterID: 7,
+				Fields: []Field{{Column: "Descriptor", Before: "Supply", After: "Supply air"}},
+			}}},
+		},
+		{
+			name: "added and removed, sorted",
+			before: []models.DataPoint{
+				{ID: 1, EquipID: "AHU-2", PointName: "SAT"},
+				{ID: 2, EquipID: "AHU-1", PointName: "RAT"},
+			},
+			after: []models.DataPoint{
+				{EquipID: "VAV-1", PointName: "ZN-T"},
+				{EquipID: "AHU-1", PointName: "MAT"},
+			},
+			want: Result{
+				Added:   []Point{{EquipID: "AHU-1", PointName: "MAT"}, {EquipID: "VAV-1", PointName: "ZN-T"}},
+				Removed: []Point{{EquipID: "AHU-1", PointName: "RAT", BeforeID: 2}, {EquipID: "AHU-2", PointName: "SAT", BeforeID: 1}},
+			},
+		},
+		{
+			name: "repeated key pairs in list order",
+			before: []models.DataPoint{
+				{ID: 1, EquipID: "AHU-1", PointName: "SAT", Descriptor: "first"},
+				{ID: 2, EquipID: "AHU-1", PointName: "SAT", Descriptor: "second"},
+			},
+			after: []models.DataPoint{
+				{EquipID: "AHU-1", PointName: "SAT", Descriptor: "first"},
+				{EquipID: "AHU-1", PointName: "SAT", Descriptor: "second"},
+				{EquipID: "AHU-1", PointName: "SAT", Descriptor: "third"},
+			},
+			want: Result{
+				Added:     []Point{{EquipID: "AHU-1", PointName: "SAT"}},
+				Unchanged: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.before, tt.after, columns)
+
+			want := tt.want
+			want.Columns = []string{"Descriptor", "EngineeringUnits"}
+			for _, list := range []*[]Point{&want.Added, &want.Removed, &want.Modified} {
+				if *list == nil {
+					*list = []Point{}
+				}
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("Compare() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestRecords(t *testing.T) {
+	r := Result{
+		Added:   []Point{{EquipID: "AHU-1", PointName: "MAT"}},
+		Removed: []Point{{EquipID: "AHU-1", PointName: "RAT"}},
+		Modified: []Point{{EquipID: "AHU-1", PointName: "SAT", Fields: []Field{
+			{Column: "Descriptor", Before: "Supply", After: "Supply air"},
+			{Column: "Slope", Before: "1", After: "2"},
+		}}},
+	}
+
+	want := [][]string{
+		{"Change", "EquipID", "PointName", "Column", "Before", "After"},
+		{ChangeAdded, "AHU-1", "MAT", "", "", ""},
+		{ChangeRemoved, "AHU-1", "RAT", "", "", ""},
+		{ChangeModified, "AHU-1", "SAT", "Descriptor", "Supply", "Supply air"},
+		{ChangeModified, "AHU-1", "SAT", "Slope", "1", "2"},
+	}
+	if got := r.Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Records() = %q, want %q", got, want)
+	}
+}
+
+func TestColumnsSkipsKeys(t *testing.T) {
+	for _, c := range Columns() {
+		if isKey(c) {
+			t.Errorf("Columns() contains key column %s", c)
+		}
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	before := []models.DataPoint{
+		{ID: 1, EquipID: "AHU-1", PointName: "SAT"},
+		{ID: 2, EquipID: "AHU-1", PointName: "SAT"},
+		{ID: 3, EquipID: "AHU-1", PointName: "RAT"},
+	}
+	after := []models.DataPoint{
+		{EquipID: "VAV-1", PointName: "ZN-T"},
+		{EquipID: " ahu-1", PointName: "sat "},
+		{EquipID: "AHU-1", PointName: "MAT"},
+		{EquipID: "AHU-1", PointName: "RAT"},
+	}
+
+	want := []Point{
+		{EquipID: "AHU-1", PointName: "RAT", BeforeID: 3},
+		{EquipID: "ahu-1", PointName: "sat", BeforeID: 1},
+	}
+	if got := Collisions(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Collisions() = %+v, want %+v", got, want)
+	}
+	if got := Collisions(nil, after); len(got) != 0 {
+		t.Errorf("Collisions(nil) = %+v, want none", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Package equipment derives the equipment of a project from its datapoints
+// and arranges it into the tree described by their EquipRef column.
+//
+// The equipment table is kept in step with the datapoints by calling Sync
+// whenever the points of a project change.
+package equipment
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// Node is a piece of equipment with the equipment it feeds.
+type Node struct {
+	models.Equipment
+	Children []*Node `json:"children"`
+}
+
+// Derive returns the equipment of a project's points, one per EquipID
+// compared ignoring case, ordered by EquipID. EquipType and System take the
+// most common value of the points, Parent the most common EquipRef that is
+// not the equipment itself.
+func Derive(points []models.DataPoint) []models.Equipment {
+	type tally struct {
+		equipment models.Equipment
+		types     utils.Counter
+		systems   utils.Counter
+		parents   utils.Counter
+	}
+
+	byKey := make(map[string]*tally)
+	for _, dp := range points {
+		id := strings.TrimSpace(dp.EquipID)
+		if id == "" {
+			continue
+		}
+		key := strings.ToLower(id)
+
+		t, ok := byKey[key]
+		if !ok {
+			t = &tally{equipment: models.Equipment{ProjectID: dp.ProjectID, EquipID: id}}
+			byKey[key] = t
+		}
+		t.equipment.Points++
+		t.types.Add(dp.EquipType)
+		t.systems.Add(dp.System)
+		if ref := strings.TrimSpace(dp.EquipRef); !strings.EqualFold(ref, id) {
+			t.parents.Add(ref)
+		}
+	}
+
+	list := make([]models.Equipment, 0, len(byKey))
+	for _, t := range byKey {
+		e := t.equipment
+		e.EquipType = t.types.Top()
+		e.System = t.systems.Top()
+		e.Parent = t.parents.Top()
+		// Spell a known parent the way its own points do
+		if parent, ok := byKey[strings.ToLower(e.Parent)]; ok {
+			e.Parent = parent.equipment.EquipID
+		}
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].EquipID) < strings.ToLower(list[j].EquipID)
+	})
+
+	return list
+}
+
+// Tree nests equipment under its parent. Equipment whose parent is not in
+// the list, or that would feed itself through a loop, is a root.
+func Tree(list []models.Equipment) []*Node {
+	nodes := make(map[string]*Node, len(list))
+	for _, e := range list {
+		nodes[strings.ToLower(e.EquipID)] = &Node{Equipment: e, Children: []*Node{}}
+	}
+
+	roots := []*Node{}
+	for _, e := range list {
+		node := nodes[strings.ToLower(e.EquipID)]
+		parent, ok := nodes[strings.ToLower(e.Parent)]
+		if !ok || loops(nodes, node) {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	return roots
+}
+
+// Find returns the node of an EquipID within a tree.
+func Find(roots []*Node, equipID string) (*Node, bool) {
+	for _, n := range roots {
+		if strings.EqualFold(n.EquipID, equipID) {
+			return n, true
+		}
+		if found, ok := Find(n.Children, equipID); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// loops reports whether following the parents of node leads back to it.
+func loops(nodes map[string]*Node, node *Node) bool {
+	seen := map[*Node]bool{node: true}
+	for n := node; ; {
+		parent, ok := nodes[strings.ToLower(n.Parent)]
+		if !ok {
+			return false
+		}
+		if seen[parent] {
+			return parent == node
+		}
+		seen[parent] = true
+		n = parent
+	}
+}
+
+// Sync derives the equipment of a project from its datapoints and stores it.
+func Sync(ctx context.Context, s store.Store, projectID int) error {
+	points, err := s.DataPoints.ListByProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	return s.Equipment.Sync(ctx, projectID, Derive(points))
+}
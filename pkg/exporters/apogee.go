@@ -0,0 +1,385 @@
+// Package exporters writes the point lists of projects in the import formats
+// of vendor tools, so they need not be keyed in by hand.
+//
+// Every exporter translates what it can and reports the rest: points that
+// cannot be expressed at all are skipped, values that cannot be expressed
+// are dropped or shortened with a warning.
+package exporters
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/sensors"
+	"github.com/pufington-pixie/haver/pkg/trends"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+// Apogee export formats.
+const (
+	// ApogeeCSV is one row per point, the layout of the point import
+	// spreadsheet.
+	ApogeeCSV = "csv"
+	// ApogeeText is one POINT block per point, the layout of a point
+	// definition text file.
+	ApogeeText = "txt"
+)
+
+// Limits of the fields of an Apogee point.
+const (
+	maxApogeeName       = 30
+	maxApogeeDescriptor = 16
+)
+
+// ApogeePoint is a datapoint as an Apogee point definition.
+type ApogeePoint struct {
+	Name       string
+	Type       string
+	Descriptor string
+	Panel      string
+	BLN        string
+	Node       string
+	FLNDevice  string
+	Virtual    bool
+	Units      string
+	Slope      string
+	Intercept  string
+	Sensor     string
+	COVLimit   string
+	COVTrend   bool
+	Trends     []ApogeeTrend
+}
+
+// ApogeeTrend is an interval trend definition of an Apogee point.
+type ApogeeTrend struct {
+	// Interval is HH:MM:SS.
+	Interval string
+	Samples  int
+}
+
+// Report tells how an export went.
+type Report struct {
+	Points   int `json:"points"`
+	Exported int `json:"exported"`
+	// Skipped are the points left out, one problem each.
+	Skipped []models.PointProblem `json:"skipped"`
+	// Warnings are the values of exported points that were dropped or
+	// changed.
+	Warnings []models.PointProblem `json:"warnings"`
+}
+
+// Apogee translates datapoints to Apogee point definitions. The catalogue
+// resolves the UnitId and EngineeringUnits of points; when it is nil only the
+// spellings of the translation table are known.
+func Apogee(points []models.DataPoint, catalogue *units.Catalogue) ([]ApogeePoint, Report) {
+	report := Report{Points: len(points), Skipped: []models.PointProblem{}, Warnings: []models.PointProblem{}}
+	list := make([]ApogeePoint, 0, len(points))
+	for _, dp := range points {
+		p, warnings, skip := apogeePoint(dp, catalogue)
+		if skip != nil {
+			report.Skipped = append(report.Skipped, *skip)
+			continue
+		}
+		list = append(list, p)
+		report.Warnings = append(report.Warnings, warnings...)
+	}
+	report.Exported = len(list)
+	return list, report
+}
+
+// apogeePoint translates one datapoint. skip is set when the point cannot be
+// expressed at all.
+func apogeePoint(dp models.DataPoint, catalogue *units.Catalogue) (p ApogeePoint, warnings []models.PointProblem, skip *models.PointProblem) {
+	problem := func(column, format string, args ...interface{}) models.PointProblem {
+		return models.PointProblem{PointID: dp.ID, PointName: dp.PointName, Column: column, Message: fmt.Sprintf(format, args...)}
+	}
+	warn := func(column, format string, args ...interface{}) {
+		warnings = append(warnings, problem(column, format, args...))
+	}
+
+	p.Name = strings.TrimSpace(dp.PointName)
+	switch {
+	case p.Name == "":
+		s := problem("PointName", "no point name")
+		return p, nil, &s
+	case len(p.Name) > maxApogeeName:
+		s := problem("PointName", "%q is longer than the %d characters of an Apogee point name", p.Name, maxApogeeName)
+		return p, nil, &s
+	case !printable(p.Name):
+		s := problem("PointName", "%q holds characters an Apogee point name cannot", p.Name)
+		return p, nil, &s
+	}
+
+	column, value := "ApogeePtType", dp.ApogeePtType
+	if strings.TrimSpace(value) == "" {
+		column, value = "PointType", dp.PointType
+	}
+	if strings.TrimSpace(value) == "" {
+		column, value = "BACnetObjectType", dp.BACnetObjectType
+	}
+	typ, virtual, ok := ApogeePointType(value)
+	if !ok {
+		s := problem(column, "no Apogee point type for %q", strings.TrimSpace(value))
+		if strings.TrimSpace(value) == "" {
+			s = problem("ApogeePtType", "no point type")
+		}
+		return p, nil, &s
+	}
+	p.Type = typ
+	p.Virtual = virtual || yes(dp.Virtual)
+
+	p.Panel = strings.TrimSpace(dp.DeviceSysName)
+	if p.Panel == "" {
+		s := problem("DeviceSysName", "no panel")
+		return p, nil, &s
+	}
+	p.BLN = strings.TrimSpace(dp.BLNSysName)
+	if dp.NodeIdentifier != nil {
+		p.Node = strconv.Itoa(*dp.NodeIdentifier)
+	} else if dp.InsightNodeNumber != nil {
+		p.Node = strconv.Itoa(*dp.InsightNodeNumber)
+	}
+	if field := strings.TrimSpace(dp.FLNdeviceSysName); field != "" {
+		if p.Virtual {
+			warn("FLNdeviceSysName", "virtual points are not on a field device, %q dropped", field)
+		} else {
+			p.FLNDevice = field
+		}
+	}
+
+	p.Descriptor = strings.TrimSpace(dp.Descriptor)
+	if !printable(p.Descriptor) {
+		p.Descriptor = strings.Map(func(r rune) rune {
+			if r == '"' || r > unicode.MaxASCII || !unicode.IsPrint(r) {
+				return -1
+			}
+			return r
+		}, p.Descriptor)
+		warn("Descriptor", "characters Apogee cannot hold dropped")
+	}
+	if len(p.Descriptor) > maxApogeeDescriptor {
+		warn("Descriptor", "%q shortened to the %d characters of an Apogee descriptor", p.Descriptor, maxApogeeDescriptor)
+		p.Descriptor = strings.TrimSpace(p.Descriptor[:maxApogeeDescriptor])
+	}
+
+	if analog(typ) {
+		p.Units = apogeeUnit(dp, catalogue, warn)
+		p.Slope = number(dp.Slope, "1", "Slope", warn)
+		p.Intercept = number(dp.Intercept, "0", "Intercept", warn)
+	} else {
+		for _, c := range []string{"Slope", "Intercept"} {
+			if v := strings.TrimSpace(dp.Get(c)); v != "" {
+				warn(c, "%s points have no %s, %q dropped", typ, strings.ToLower(c), v)
+			}
+		}
+	}
+
+	if sensor := strings.TrimSpace(dp.SensorType); sensor != "" {
+		switch s, ok := sensors.Lookup(sensor); {
+		case typ != ApogeeLAI && typ != ApogeeLAO:
+			warn("SensorType", "%s points have no sensor type, %q dropped", typ, sensor)
+		case p.Virtual:
+			warn("SensorType", "virtual points have no sensor type, %q dropped", sensor)
+		case !ok || s.Apogee == "":
+			warn("SensorType", "no Apogee sensor type for %q", sensor)
+		default:
+			p.Sensor = s.Apogee
+		}
+	}
+
+	cfg, errs := trends.Parse(dp)
+	for _, e := range errs {
+		c, msg, _ := strings.Cut(e, ": ")
+		warn(c, "%s, dropped", msg)
+	}
+	if cfg.COV != nil {
+		p.COVTrend = true
+		if cfg.COV.Limit != nil {
+			if analog(typ) {
+				p.COVLimit = strconv.FormatFloat(*cfg.COV.Limit, 'g', -1, 64)
+			} else {
+				warn("COVLimit", "%s points have no COV limit, dropped", typ)
+			}
+		}
+	}
+	for _, t := range cfg.Trends {
+		if t.Interval == "" {
+			continue
+		}
+		p.Trends = append(p.Trends, ApogeeTrend{Interval: t.Interval, Samples: t.PanelSamples})
+	}
+
+	return p, warnings, nil
+}
+
+// apogeeUnit spells the engineering units of a point for Apogee.
+func apogeeUnit(dp models.DataPoint, catalogue *units.Catalogue, warn func(column, format string, args ...interface{})) string {
+	raw := strings.TrimSpace(dp.EngineeringUnits)
+	var name string
+	if catalogue != nil {
+		if dp.UnitId != nil {
+			if u, ok := catalogue.Get(*dp.UnitId); ok {
+				name = u.Name
+			}
+		}
+		if name == "" && raw != "" {
+			if u, ok := catalogue.Lookup(raw); ok {
+				name = u.Name
+			}
+		}
+	}
+	if name == "" {
+		name = raw
+	}
+	if s, ok := apogeeUnits[strings.ToLower(name)]; ok {
+		return s
+	}
+
+	if raw == "" {
+		raw = name
+	}
+	if raw == "" {
+		return ""
+	}
+	if len(raw) > maxApogeeUnits || !printable(raw) {
+		warn("EngineeringUnits", "%q does not fit Apogee engineering units, dropped", raw)
+		return ""
+	}
+	warn("EngineeringUnits", "no Apogee spelling for %q, written as is", raw)
+	return strings.ToUpper(raw)
+}
+
+// number reads a slope or intercept, def when it is empty or unreadable.
+func number(s, def, column string, warn func(column, format string, args ...interface{})) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		warn(column, "%q is not a number, %s written", s, def)
+		return def
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// yes reads a yes/no column.
+func yes(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "x", "on":
+		return true
+	}
+	return false
+}
+
+// printable reports whether s is printable ASCII without double quotes.
+func printable(s string) bool {
+	for _, r := range s {
+		if r == '"' || r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApogeeColumns is the header of the CSV export.
+func ApogeeColumns() []string {
+	columns := []string{
+		"Point System Name", "Point Type", "Descriptor", "Panel", "BLN", "Node", "FLN Device", "Virtual",
+		"Engineering Units", "Slope", "Intercept", "Sensor Type", "COV Limit", "COV Trend",
+	}
+	for slot := 1; slot <= trends.Slots; slot++ {
+		n := strconv.Itoa(slot)
+		columns = append(columns, "Trend Interval "+n, "Trend Samples "+n)
+	}
+	return columns
+}
+
+// WriteApogee writes point definitions in the given format.
+func WriteApogee(w io.Writer, format string, points []ApogeePoint) error {
+	switch format {
+	case ApogeeCSV:
+		return writeApogeeCSV(w, points)
+	case ApogeeText:
+		return writeApogeeText(w, points)
+	}
+	return fmt.Errorf("unknown Apogee format %q", format)
+}
+
+func writeApogeeCSV(w io.Writer, points []ApogeePoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ApogeeColumns()); err != nil {
+		return err
+	}
+	for _, p := range points {
+		record := []string{
+			p.Name, p.Type, p.Descriptor, p.Panel, p.BLN, p.Node, p.FLNDevice, yesNo(p.Virtual),
+			p.Units, p.Slope, p.Intercept, p.Sensor, p.COVLimit, yesNo(p.COVTrend),
+		}
+		for slot := 0; slot < trends.Slots; slot++ {
+			if slot < len(p.Trends) {
+				record = append(record, p.Trends[slot].Interval, strconv.Itoa(p.Trends[slot].Samples))
+			} else {
+				record = append(record, "", "")
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeApogeeText(w io.Writer, points []ApogeePoint) error {
+	b := bufio.NewWriter(w)
+	field := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(b, "  %-11s %s\n", key, value)
+		}
+	}
+	quoted := func(key, value string) {
+		if value != "" {
+			field(key, `"`+value+`"`)
+		}
+	}
+
+	for _, p := range points {
+		fmt.Fprintf(b, "POINT \"%s\"\n", p.Name)
+		field("TYPE", p.Type)
+		quoted("DESCRIPTOR", p.Descriptor)
+		quoted("PANEL", p.Panel)
+		quoted("BLN", p.BLN)
+		field("NODE", p.Node)
+		if p.Virtual {
+			field("ADDRESS", "VIRTUAL")
+		}
+		quoted("FLN_DEVICE", p.FLNDevice)
+		quoted("UNITS", p.Units)
+		field("SLOPE", p.Slope)
+		field("INTERCEPT", p.Intercept)
+		field("SENSOR", p.Sensor)
+		field("COV_LIMIT", p.COVLimit)
+		if p.COVTrend {
+			field("TREND", "COV")
+		}
+		for _, t := range p.Trends {
+			field("TREND", fmt.Sprintf("INTERVAL %s SAMPLES %d", t.Interval, t.Samples))
+		}
+		b.WriteString("END POINT\n\n")
+	}
+	return b.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
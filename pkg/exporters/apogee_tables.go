@@ -0,0 +1,177 @@
+package exporters
+
+import (
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Apogee point types.
+const (
+	ApogeeLAI   = "LAI"
+	ApogeeLAO   = "LAO"
+	ApogeeLDI   = "LDI"
+	ApogeeLDO   = "LDO"
+	ApogeeL2SL  = "L2SL"
+	ApogeeL2SP  = "L2SP"
+	ApogeeLOOAL = "LOOAL"
+	ApogeeLOOAP = "LOOAP"
+	ApogeeLPACI = "LPACI"
+	ApogeeLENUM = "LENUM"
+)
+
+// apogeeType is how a point type spelling translates to Apogee.
+type apogeeType struct {
+	Type string
+	// Virtual is set for spellings of value objects, which only exist in the
+	// panel's database.
+	Virtual bool
+}
+
+// apogeeTypes translates the point type spellings of point lists, keyed by
+// normalized spelling.
+var apogeeTypes = map[string]apogeeType{
+	"lai":   {Type: ApogeeLAI},
+	"lao":   {Type: ApogeeLAO},
+	"ldi":   {Type: ApogeeLDI},
+	"ldo":   {Type: ApogeeLDO},
+	"l2sl":  {Type: ApogeeL2SL},
+	"l2sp":  {Type: ApogeeL2SP},
+	"looal": {Type: ApogeeLOOAL},
+	"looap": {Type: ApogeeLOOAP},
+	"lpaci": {Type: ApogeeLPACI},
+	"lenum": {Type: ApogeeLENUM},
+
+	"ai":  {Type: ApogeeLAI},
+	"ao":  {Type: ApogeeLAO},
+	"av":  {Type: ApogeeLAO, Virtual: true},
+	"di":  {Type: ApogeeLDI},
+	"do":  {Type: ApogeeLDO},
+	"dv":  {Type: ApogeeLDO, Virtual: true},
+	"bi":  {Type: ApogeeLDI},
+	"bo":  {Type: ApogeeLDO},
+	"bv":  {Type: ApogeeLDO, Virtual: true},
+	"mi":  {Type: ApogeeLENUM},
+	"mo":  {Type: ApogeeLENUM},
+	"mv":  {Type: ApogeeLENUM, Virtual: true},
+	"msi": {Type: ApogeeLENUM},
+	"mso": {Type: ApogeeLENUM},
+	"msv": {Type: ApogeeLENUM, Virtual: true},
+	"pi":  {Type: ApogeeLPACI},
+	"aci": {Type: ApogeeLPACI},
+}
+
+// apogeeObjectTypes translates BACnet object types.
+var apogeeObjectTypes = map[string]apogeeType{
+	"analog-input":       {Type: ApogeeLAI},
+	"analog-output":      {Type: ApogeeLAO},
+	"analog-value":       {Type: ApogeeLAO, Virtual: true},
+	"binary-input":       {Type: ApogeeLDI},
+	"binary-output":      {Type: ApogeeLDO},
+	"binary-value":       {Type: ApogeeLDO, Virtual: true},
+	"multi-state-input":  {Type: ApogeeLENUM},
+	"multi-state-output": {Type: ApogeeLENUM},
+	"multi-state-value":  {Type: ApogeeLENUM, Virtual: true},
+	"accumulator":        {Type: ApogeeLPACI},
+	"pulse-converter":    {Type: ApogeeLPACI},
+}
+
+// ApogeePointType translates a point type spelling, an Apogee type, a point
+// list abbreviation such as AI or DO, or a BACnet object type, to an Apogee
+// point type. virtual is set for value objects.
+func ApogeePointType(s string) (typ string, virtual, ok bool) {
+	key := models.Normalize(s)
+	if key == "" {
+		return "", false, false
+	}
+	if t, ok := apogeeTypes[key]; ok {
+		return t.Type, t.Virtual, true
+	}
+	if ot, err := bacnet.ParseObjectType(s); err == nil {
+		if t, ok := apogeeObjectTypes[ot.String()]; ok {
+			return t.Type, t.Virtual, true
+		}
+	}
+	return "", false, false
+}
+
+// analog reports whether points of an Apogee type have engineering units.
+func analog(typ string) bool {
+	return typ == ApogeeLAI || typ == ApogeeLAO || typ == ApogeeLPACI
+}
+
+// apogeeUnits spells BACnet engineering units the way Apogee does, within
+// its six characters.
+var apogeeUnits = map[string]string{
+	"no-units":                            "",
+	"percent":                             "PCT",
+	"percent-relative-humidity":           "%RH",
+	"degrees-fahrenheit":                  "DEG F",
+	"degrees-celsius":                     "DEG C",
+	"degrees-kelvin":                      "DEG K",
+	"delta-degrees-fahrenheit":            "DELT F",
+	"delta-degrees-kelvin":                "DELT K",
+	"inches-of-water":                     "IN WC",
+	"centimeters-of-water":                "CM WC",
+	"millimeters-of-water":                "MM WC",
+	"inches-of-mercury":                   "IN HG",
+	"pounds-force-per-square-inch":        "PSI",
+	"pascals":                             "PA",
+	"hectopascals":                        "HPA",
+	"kilopascals":                         "KPA",
+	"bars":                                "BAR",
+	"millibars":                           "MBAR",
+	"cubic-feet-per-minute":               "CFM",
+	"cubic-feet-per-hour":                 "CFH",
+	"cubic-meters-per-hour":               "M3/H",
+	"cubic-meters-per-second":             "M3/S",
+	"liters-per-second":                   "L/S",
+	"liters-per-minute":                   "L/MIN",
+	"liters-per-hour":                     "L/H",
+	"us-gallons-per-minute":               "GPM",
+	"us-gallons-per-hour":                 "GPH",
+	"feet-per-minute":                     "FPM",
+	"feet-per-second":                     "FPS",
+	"meters-per-second":                   "M/S",
+	"volts":                               "VOLTS",
+	"millivolts":                          "MV",
+	"milliamperes":                        "MA",
+	"amperes":                             "AMPS",
+	"ohms":                                "OHMS",
+	"kilohms":                             "KOHMS",
+	"hertz":                               "HZ",
+	"watts":                               "W",
+	"kilowatts":                           "KW",
+	"megawatts":                           "MW",
+	"kilowatt-hours":                      "KWH",
+	"megawatt-hours":                      "MWH",
+	"volt-amperes":                        "VA",
+	"kilovolt-amperes":                    "KVA",
+	"kilovolt-amperes-reactive":           "KVAR",
+	"power-factor":                        "PF",
+	"btus-per-hour":                       "BTUH",
+	"kilo-btus-per-hour":                  "MBH",
+	"tons-refrigeration":                  "TONS",
+	"ton-hours":                           "TONHRS",
+	"therms":                              "THERMS",
+	"parts-per-million":                   "PPM",
+	"revolutions-per-minute":              "RPM",
+	"seconds":                             "SEC",
+	"minutes":                             "MIN",
+	"hours":                               "HRS",
+	"days":                                "DAYS",
+	"luxes":                               "LUX",
+	"foot-candles":                        "FC",
+	"btus-per-pound-dry-air":              "BTU/LB",
+	"grams-of-water-per-kilogram-dry-air": "G/KG",
+	"kilojoules-per-kilogram-dry-air":     "KJ/KG",
+	"us-gallons":                          "GAL",
+	"cubic-feet":                          "FT3",
+	"cubic-meters":                        "M3",
+	"liters":                              "L",
+	"kilograms-per-hour":                  "KG/H",
+	"pounds-mass-per-hour":                "LB/H",
+	"decibels-a":                          "DBA",
+}
+
+// maxApogeeUnits is the length of the engineering units of an Apogee point.
+const maxApogeeUnits = 6
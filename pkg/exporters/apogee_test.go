@@ -0,0 +1,174 @@
+package exporters
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestApogeePointType(t *testing.T) {
+	tests := []struct {
+		in      string
+		typ     string
+		virtual bool
+		ok      bool
+	}{
+		{in: "LAI", typ: ApogeeLAI, ok: true},
+		{in: "l2sp", typ: ApogeeL2SP, ok: true},
+		{in: "AO", typ: ApogeeLAO, ok: true},
+		{in: "AV", typ: ApogeeLAO, virtual: true, ok: true},
+		{in: "B.O.", typ: ApogeeLDO, ok: true},
+		{in: "MSV", typ: ApogeeLENUM, virtual: true, ok: true},
+		{in: "analog-input", typ: ApogeeLAI, ok: true},
+		{in: "Binary Value", typ: ApogeeLDO, virtual: true, ok: true},
+		{in: "4", typ: ApogeeLDO, ok: true},
+		{in: "schedule"},
+		{in: "LXX"},
+		{in: ""},
+	}
+	for _, tt := range tests {
+		typ, virtual, ok := ApogeePointType(tt.in)
+		if typ != tt.typ || virtual != tt.virtual || ok != tt.ok {
+			t.Errorf("ApogeePointType(%q) = %q, %t, %t, want %q, %t, %t", tt.in, typ, virtual, ok, tt.typ, tt.virtual, tt.ok)
+		}
+	}
+}
+
+func TestApogeeUnit(t *testing.T) {
+	var aliases []models.UnitAlias
+	for alias, id := range units.DefaultAliases {
+		aliases = append(aliases, models.UnitAlias{Alias: units.Normalize(alias), UnitID: id})
+	}
+	catalogue := units.New(units.Standard, aliases)
+	percent := 98
+
+	tests := []struct {
+		name      string
+		dp        models.DataPoint
+		catalogue *units.Catalogue
+		want      string
+		warnings  int
+	}{
+		{name: "none", catalogue: catalogue},
+		{name: "by UnitId", dp: models.DataPoint{UnitId: &percent, EngineeringUnits: "°F"}, catalogue: catalogue, want: "PCT"},
+		{name: "by spelling", dp: models.DataPoint{EngineeringUnits: "°F"}, catalogue: catalogue, want: "DEG F"},
+		{name: "without catalogue", dp: models.DataPoint{EngineeringUnits: "degrees-fahrenheit"}, want: "DEG F"},
+		{name: "unknown, written as is", dp: models.DataPoint{EngineeringUnits: "bbl"}, catalogue: catalogue, want: "BBL", warnings: 1},
+		{name: "unknown and too long", dp: models.DataPoint{EngineeringUnits: "barrels"}, catalogue: catalogue, warnings: 1},
+		{name: "unknown and unprintable", dp: models.DataPoint{EngineeringUnits: "µS"}, catalogue: catalogue, warnings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings int
+			warn := func(column, format string, args ...interface{}) { warnings++ }
+			if got := apogeeUnit(tt.dp, tt.catalogue, warn); got != tt.want || warnings != tt.warnings {
+				t.Errorf("apogeeUnit() = %q with %d warnings, want %q with %d", got, warnings, tt.want, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestApogeeSkips(t *testing.T) {
+	valid := models.DataPoint{ID: 1, PointName: "AHU1.SAT", ApogeePtType: "LAI", DeviceSysName: "PXC-1"}
+
+	tests := []struct {
+		name   string
+		change func(dp *models.DataPoint)
+		column string
+	}{
+		{"no name", func(dp *models.DataPoint) { dp.PointName = " " }, "PointName"},
+		{"name too long", func(dp *models.DataPoint) { dp.PointName = "AHU1.SUPPLY.AIR.TEMPERATURE.SENSOR" }, "PointName"},
+		{"name unprintable", func(dp *models.DataPoint) { dp.PointName = `AHU1."SAT"` }, "PointName"},
+		{"no type", func(dp *models.DataPoint) { dp.ApogeePtType = "" }, "ApogeePtType"},
+		{"unknown type", func(dp *models.DataPoint) { dp.ApogeePtType = ""; dp.PointType = "schedule" }, "PointType"},
+		{"no panel", func(dp *models.DataPoint) { dp.DeviceSysName = "" }, "DeviceSysName"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := valid
+			tt.change(&dp)
+			_, _, skip := apogeePoint(dp, nil)
+			if skip == nil || skip.Column != tt.column || skip.PointID != 1 {
+				t.Errorf("apogeePoint() skip = %+v, want one for %s", skip, tt.column)
+			}
+		})
+	}
+
+	if _, warnings, skip := apogeePoint(valid, nil); skip != nil || len(warnings) != 0 {
+		t.Errorf("apogeePoint(valid) = %+v, %+v, want neither", warnings, skip)
+	}
+}
+
+func TestApogeeWarnings(t *testing.T) {
+	dp := models.DataPoint{
+		PointName: "AHU1.SAT", ApogeePtType: "AV", DeviceSysName: "PXC-1",
+		FLNdeviceSysName: "TEC-1", Descriptor: "Supply air temperature",
+		Slope: "steep", SensorType: "4-20mA", TrendInterval1: "every 15",
+	}
+	_, warnings, skip := apogeePoint(dp, nil)
+	if skip != nil {
+		t.Fatalf("skipped: %+v", skip)
+	}
+
+	var columns []string
+	for _, w := range warnings {
+		columns = append(columns, w.Column)
+	}
+	want := []string{"FLNdeviceSysName", "Descriptor", "Slope", "SensorType", "TrendInterval1"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("warnings on %q, want %q", columns, want)
+	}
+}
+
+func TestWriteApogee(t *testing.T) {
+	node := 3
+	points := []models.DataPoint{
+		{
+			ID: 1, PointName: "AHU1.SAT", ApogeePtType: "LAI", Descriptor: "Supply air temp",
+			DeviceSysName: "PXC-1", BLNSysName: "BLN1", NodeIdentifier: &node, FLNdeviceSysName: "TEC-1",
+			EngineeringUnits: "degrees-fahrenheit", Slope: "0.25", Intercept: "-40", SensorType: "10K",
+			COVTrend: "Yes", COVLimit: "0.5", TrendInterval1: "15 min", PanelSamples1: "672",
+		},
+		{ID: 2, PointName: "AHU1.SF.CMD", PointType: "BO", DeviceSysName: "PXC-1", BLNSysName: "BLN1"},
+		{ID: 3, PointName: "AHU1.SAT.STPT", BACnetObjectType: "analog-value", DeviceSysName: "PXC-1", EngineeringUnits: "%"},
+	}
+	list, report := Apogee(points, nil)
+	if report.Exported != 3 {
+		t.Fatalf("exported %d points, want 3: %+v", report.Exported, report)
+	}
+
+	for _, format := range []string{ApogeeCSV, ApogeeText} {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteApogee(&b, format, list); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "apogee."+format)
+			if *update {
+				if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b.Bytes(), want) {
+				t.Errorf("WriteApogee(%s) =\n%s\nwant\n%s", format, b.Bytes(), want)
+			}
+		})
+	}
+
+	if err := WriteApogee(&bytes.Buffer{}, "xml", list); err == nil {
+		t.Error("WriteApogee(xml) succeeded, want an error")
+	}
+}
@@ -0,0 +1,145 @@
+package haystack
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+// Project is what a project's grid is built from.
+type Project struct {
+	Project   models.Project
+	Equipment []models.Equipment
+	Points    []models.DataPoint
+	// Overrides are the user's changes to the derived point tags.
+	Overrides []models.PointTags
+	// Units resolves the UnitId of points; EngineeringUnits is used when nil
+	// or when a point has no UnitId.
+	Units *units.Catalogue
+}
+
+// Refs of the site, equipment and points of a project.
+func siteRef(p models.Project) Ref     { return Ref{ID: "site." + strconv.Itoa(p.ID), Dis: p.Name} }
+func equipRef(e models.Equipment) Ref  { return Ref{ID: "equip." + strconv.Itoa(e.ID), Dis: e.EquipID} }
+func pointRef(dp models.DataPoint) Ref { return Ref{ID: "point." + strconv.Itoa(dp.ID), Dis: dis(dp)} }
+
+// leading are the columns written before the marker tags.
+var leading = []string{"id", "dis", "siteRef", "equipRef", "kind", "unit"}
+
+// Grid builds the grid of a project: one row for the project as the site,
+// one per piece of equipment and one per datapoint, each with its marker
+// tags.
+func (t *Tagger) Grid(p Project) *Grid {
+	site := siteRef(p.Project)
+	markers := map[string]bool{"site": true}
+	rows := []Row{{"id": site, "dis": Str(p.Project.Name), "site": Marker{}}}
+
+	equips := make(map[string]Ref, len(p.Equipment))
+	for _, e := range p.Equipment {
+		equips[strings.ToLower(e.EquipID)] = equipRef(e)
+	}
+	for _, e := range p.Equipment {
+		row := Row{"id": equipRef(e), "dis": Str(e.EquipID), "siteRef": site}
+		if parent, ok := equips[strings.ToLower(e.Parent)]; ok {
+			row["equipRef"] = parent
+		}
+		for _, tag := range t.EquipTags(e.EquipType) {
+			if contains(leading, tag) {
+				continue
+			}
+			row[tag] = Marker{}
+			markers[tag] = true
+		}
+		rows = append(rows, row)
+	}
+
+	overrides := make(map[int]models.PointTags, len(p.Overrides))
+	for _, o := range p.Overrides {
+		overrides[o.PointID] = o
+	}
+	for _, dp := range p.Points {
+		row := Row{"id": pointRef(dp), "dis": Str(dis(dp)), "siteRef": site}
+		if ref, ok := equips[strings.ToLower(strings.TrimSpace(dp.EquipID))]; ok {
+			row["equipRef"] = ref
+		}
+		k := kind(dp)
+		row["kind"] = Str(k)
+		if u := unit(dp, p.Units); u != "" && k == "Number" {
+			row["unit"] = Str(u)
+		}
+		for _, tag := range Apply(t.PointTags(dp), overrides[dp.ID]) {
+			// Tags named like the value columns are left to those columns
+			if contains(leading, tag) {
+				continue
+			}
+			row[tag] = Marker{}
+			markers[tag] = true
+		}
+		rows = append(rows, row)
+	}
+
+	cols := append([]string{}, leading...)
+	for _, tag := range sorted(markers) {
+		if !contains(leading, tag) {
+			cols = append(cols, tag)
+		}
+	}
+
+	return &Grid{Cols: cols, Rows: rows}
+}
+
+// dis is the display name of a point.
+func dis(dp models.DataPoint) string {
+	for _, s := range []string{dp.PointName, dp.Descriptor, dp.BACnetObjectName} {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return "Point " + strconv.Itoa(dp.ID)
+}
+
+// kind returns the Haystack kind of a point's value from its BACnet object
+// type, or from its PointType when that is a BACnet abbreviation.
+func kind(dp models.DataPoint) string {
+	for _, s := range []string{dp.BACnetObjectType, dp.PointType} {
+		t, err := bacnet.ParseObjectType(s)
+		if err != nil {
+			continue
+		}
+		name := t.String()
+		switch {
+		case strings.HasPrefix(name, "binary-"):
+			return "Bool"
+		case name == "characterstring-value":
+			return "Str"
+		}
+		return "Number"
+	}
+	switch strings.ToUpper(strings.TrimSpace(dp.PointType)) {
+	case "DI", "DO", "LDI", "LDO":
+		return "Bool"
+	}
+	return "Number"
+}
+
+// unit returns the unit symbol of a point.
+func unit(dp models.DataPoint, catalogue *units.Catalogue) string {
+	if dp.UnitId != nil && catalogue != nil {
+		if u, ok := catalogue.Get(*dp.UnitId); ok && u.Symbol != "" {
+			return u.Symbol
+		}
+	}
+	return strings.TrimSpace(dp.EngineeringUnits)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package haystack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Version is the Haystack version the grids are written in.
+const Version = "3.0"
+
+// Value is the value of a grid cell: Marker, Str or Ref.
+type Value interface {
+	zinc() string
+	json() string
+}
+
+// Marker is a marker tag.
+type Marker struct{}
+
+// Str is a string.
+type Str string
+
+// Ref is a reference to another entity, with its display name.
+type Ref struct {
+	ID  string
+	Dis string
+}
+
+func (Marker) zinc() string { return "M" }
+func (Marker) json() string { return "m:" }
+
+func (s Str) zinc() string { return quote(string(s)) }
+
+// json prefixes a string that could be taken for a typed value.
+func (s Str) json() string {
+	if len(s) >= 2 && s[1] == ':' {
+		return "s:" + string(s)
+	}
+	return string(s)
+}
+
+func (r Ref) zinc() string {
+	if r.Dis == "" {
+		return "@" + r.ID
+	}
+	return "@" + r.ID + " " + quote(r.Dis)
+}
+
+func (r Ref) json() string {
+	if r.Dis == "" {
+		return "r:" + r.ID
+	}
+	return "r:" + r.ID + " " + r.Dis
+}
+
+// Row is a row of a grid keyed by column name. Missing cells are null.
+type Row map[string]Value
+
+// Grid is a Haystack grid.
+type Grid struct {
+	Cols []string
+	Rows []Row
+}
+
+// WriteZinc writes the grid in Zinc.
+func (g *Grid) WriteZinc(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ver:%s\n", quote(Version))
+	b.WriteString(strings.Join(g.Cols, ","))
+	b.WriteByte('\n')
+
+	for _, row := range g.Rows {
+		for i, col := range g.Cols {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if v, ok := row[col]; ok {
+				b.WriteString(v.zinc())
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the grid in the Haystack JSON encoding.
+func (g *Grid) WriteJSON(w io.Writer) error {
+	type col struct {
+		Name string `json:"name"`
+	}
+	out := struct {
+		Meta map[string]string   `json:"meta"`
+		Cols []col               `json:"cols"`
+		Rows []map[string]string `json:"rows"`
+	}{
+		Meta: map[string]string{"ver": Version},
+		Cols: make([]col, len(g.Cols)),
+		Rows: make([]map[string]string, len(g.Rows)),
+	}
+
+	for i, name := range g.Cols {
+		out.Cols[i] = col{Name: name}
+	}
+	for i, row := range g.Rows {
+		cells := make(map[string]string, len(row))
+		for name, v := range row {
+			cells[name] = v.json()
+		}
+		out.Rows[i] = cells
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+// quote returns s as a Zinc string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '$':
+			b.WriteString(`\$`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
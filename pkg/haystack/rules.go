@@ -0,0 +1,241 @@
+// Package haystack derives Project Haystack marker tags for the datapoints
+// and equipment of a project and writes them out as Haystack grids.
+//
+// Tags come from rules matching the EquipType, PointType, Function and
+// Descriptor columns; users can add or remove tags per point on top of them.
+package haystack
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// DefaultRules are the rules of a project that has not configured its own.
+var DefaultRules = []models.TagRule{
+	// Equipment
+	{Column: "EquipType", Pattern: `^AHU`, Tags: []string{"ahu"}},
+	{Column: "EquipType", Pattern: `^RTU`, Tags: []string{"ahu", "rtu"}},
+	{Column: "EquipType", Pattern: `^MAU`, Tags: []string{"ahu", "mau"}},
+	{Column: "EquipType", Pattern: `^VAV`, Tags: []string{"vav"}},
+	{Column: "EquipType", Pattern: `^FCU`, Tags: []string{"fcu"}},
+	{Column: "EquipType", Pattern: `^(CH|CHLR|CHILLER)\b`, Tags: []string{"chiller"}},
+	{Column: "EquipType", Pattern: `^(BLR|BOILER)\b`, Tags: []string{"boiler"}},
+	{Column: "EquipType", Pattern: `^(P|PUMP|CHWP|CWP|HWP)\b`, Tags: []string{"pump"}},
+	{Column: "EquipType", Pattern: `^(CT|COOLING ?TOWER)\b`, Tags: []string{"coolingTower"}},
+	{Column: "EquipType", Pattern: `^(EF|SF|RF|FAN)\b`, Tags: []string{"fan"}},
+	{Column: "EquipType", Pattern: `^(MTR|METER)\b`, Tags: []string{"meter"}},
+
+	// Point kind
+	{Column: "PointType", Pattern: `^(AI|BI|DI|MI|MSI|LAI|LDI)$`, Tags: []string{"sensor"}},
+	{Column: "PointType", Pattern: `^(AO|BO|DO|MO|MSO|LAO|LDO)$`, Tags: []string{"cmd"}},
+	{Column: "PointType", Pattern: `^(AV|BV|MV|MSV)$`, Tags: []string{"sp"}},
+
+	// What the point measures or controls
+	{Column: "Function", Pattern: `\b(discharge|supply|dat|sat)\b`, Tags: []string{"discharge"}},
+	{Column: "Descriptor", Pattern: `\b(discharge|supply)\b`, Tags: []string{"discharge"}},
+	{Column: "Function", Pattern: `\b(return|rat)\b`, Tags: []string{"return"}},
+	{Column: "Descriptor", Pattern: `\breturn\b`, Tags: []string{"return"}},
+	{Column: "Function", Pattern: `\b(mixed|mat)\b`, Tags: []string{"mixed"}},
+	{Column: "Descriptor", Pattern: `\bmixed\b`, Tags: []string{"mixed"}},
+	{Column: "Function", Pattern: `\b(outside|outdoor|oat|oa)\b`, Tags: []string{"outside"}},
+	{Column: "Descriptor", Pattern: `\b(outside|outdoor)\b`, Tags: []string{"outside"}},
+	{Column: "Descriptor", Pattern: `\bexhaust\b`, Tags: []string{"exhaust"}},
+	{Column: "Descriptor", Pattern: `\b(zone|space|room)\b`, Tags: []string{"zone"}},
+	{Column: "Function", Pattern: `\b(dat|sat|rat|mat|oat|zat)\b`, Tags: []string{"air", "temp"}},
+	{Column: "Descriptor", Pattern: `\bair\b`, Tags: []string{"air"}},
+	{Column: "Descriptor", Pattern: `\bwater\b`, Tags: []string{"water"}},
+	{Column: "Descriptor", Pattern: `\bchilled\b`, Tags: []string{"chilled", "water"}},
+	{Column: "Descriptor", Pattern: `\b(hot|heating) water\b`, Tags: []string{"hot", "water"}},
+	{Column: "Descriptor", Pattern: `\btemp(erature)?\b`, Tags: []string{"temp"}},
+	{Column: "Function", Pattern: `\btemp\b`, Tags: []string{"temp"}},
+	{Column: "Descriptor", Pattern: `\bhumidity\b`, Tags: []string{"humidity"}},
+	{Column: "Descriptor", Pattern: `\b(static )?press(ure)?\b`, Tags: []string{"pressure"}},
+	{Column: "Descriptor", Pattern: `\bflow\b`, Tags: []string{"flow"}},
+	{Column: "Descriptor", Pattern: `\bco2\b`, Tags: []string{"co2"}},
+	{Column: "Descriptor", Pattern: `\bdamper\b`, Tags: []string{"damper"}},
+	{Column: "Descriptor", Pattern: `\bvalve\b`, Tags: []string{"valve"}},
+	{Column: "Descriptor", Pattern: `\bfan\b`, Tags: []string{"fan"}},
+	{Column: "Descriptor", Pattern: `\bpump\b`, Tags: []string{"pump"}},
+	{Column: "Descriptor", Pattern: `\bspeed\b`, Tags: []string{"speed"}},
+	{Column: "Descriptor", Pattern: `\b(occupied|occupancy)\b`, Tags: []string{"occ"}},
+	{Column: "Descriptor", Pattern: `\balarm\b`, Tags: []string{"alarm"}},
+	{Column: "Descriptor", Pattern: `\benable\b`, Tags: []string{"enable"}},
+	{Column: "Descriptor", Pattern: `\b(status|proof)\b`, Tags: []string{"run"}},
+
+	// Role of the point
+	{Column: "Function", Pattern: `\b(sp|stpt|setpoint)\b`, Tags: []string{"sp"}},
+	{Column: "Descriptor", Pattern: `\b(setpoint|set point)\b`, Tags: []string{"sp"}},
+	{Column: "Function", Pattern: `\b(cmd|command)\b`, Tags: []string{"cmd"}},
+	{Column: "Descriptor", Pattern: `\b(command|start/stop)\b`, Tags: []string{"cmd"}},
+}
+
+// tagName is the form of a Haystack tag name.
+var tagName = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
+
+// roles are the tags giving the role of a point, in order of precedence. A
+// point has at most one.
+var roles = []string{"sp", "cmd", "sensor"}
+
+// Tagger is a compiled set of tagging rules.
+type Tagger struct {
+	equip []rule
+	point []rule
+}
+
+type rule struct {
+	models.TagRule
+	re *regexp.Regexp
+}
+
+// Compile checks the rules and prepares them for use.
+func Compile(rules []models.TagRule) (*Tagger, error) {
+	t := &Tagger{}
+
+	for i, r := range rules {
+		col, ok := models.DataPointColumn(r.Column)
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unknown datapoints column %q", i+1, r.Column)
+		}
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("rule %d: pattern is required", i+1)
+		}
+		re, err := regexp.Compile("(?i)" + r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		if len(r.Tags) == 0 {
+			return nil, fmt.Errorf("rule %d: tags are required", i+1)
+		}
+		if err := CheckTags(r.Tags); err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+
+		r.Column = col.Name
+		if col.Name == "EquipType" {
+			t.equip = append(t.equip, rule{TagRule: r, re: re})
+		} else {
+			t.point = append(t.point, rule{TagRule: r, re: re})
+		}
+	}
+
+	return t, nil
+}
+
+// CheckTags returns an error for the first name that is not a valid
+// Haystack tag name.
+func CheckTags(tags []string) error {
+	for _, tag := range tags {
+		if !tagName.MatchString(tag) {
+			return fmt.Errorf("%q is not a valid tag name", tag)
+		}
+	}
+	return nil
+}
+
+// PointTags returns the sorted marker tags the rules derive for dp. When
+// several role tags match, the first of sp, cmd and sensor wins, so a
+// setpoint held in an analog value is not also a sensor.
+func (t *Tagger) PointTags(dp models.DataPoint) []string {
+	tags := map[string]bool{"point": true}
+	for _, r := range t.point {
+		if value := dp.Get(r.Column); value != "" && r.re.MatchString(value) {
+			for _, tag := range r.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+
+	for i, role := range roles {
+		if tags[role] {
+			for _, other := range roles[i+1:] {
+				delete(tags, other)
+			}
+			break
+		}
+	}
+
+	return sorted(tags)
+}
+
+// EquipTags returns the sorted marker tags the rules derive for equipment
+// of the given type.
+func (t *Tagger) EquipTags(equipType string) []string {
+	tags := map[string]bool{"equip": true}
+	for _, r := range t.equip {
+		if equipType != "" && r.re.MatchString(equipType) {
+			for _, tag := range r.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+	return sorted(tags)
+}
+
+// Apply returns derived with the overrides of a point applied, sorted.
+func Apply(derived []string, o models.PointTags) []string {
+	tags := make(map[string]bool, len(derived)+len(o.Add))
+	for _, tag := range derived {
+		tags[tag] = true
+	}
+	for _, tag := range o.Add {
+		tags[tag] = true
+	}
+	for _, tag := range o.Remove {
+		delete(tags, tag)
+	}
+	return sorted(tags)
+}
+
+func sorted(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for tag := range set {
+		list = append(list, tag)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// dedupe returns the tag names without surrounding space or duplicates, in
+// their original order.
+func dedupe(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// Canonical trims the tag names of an override, drops duplicates and checks
+// the names. A tag cannot be both added and removed.
+func Canonical(o models.PointTags) (models.PointTags, error) {
+	o.Add = dedupe(o.Add)
+	o.Remove = dedupe(o.Remove)
+	if err := CheckTags(o.Add); err != nil {
+		return o, err
+	}
+	if err := CheckTags(o.Remove); err != nil {
+		return o, err
+	}
+
+	removed := make(map[string]bool, len(o.Remove))
+	for _, tag := range o.Remove {
+		removed[tag] = true
+	}
+	for _, tag := range o.Add {
+		if removed[tag] {
+			return o, fmt.Errorf("%q is both added and removed", tag)
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Aliases maps normalized header names onto datapoints column names.
+type Aliases map[string]string
+
+// defaultAliases are the header spellings seen in point schedules that do not
+// normalize onto a column name by themselves.
+var defaultAliases = map[string]string{
+	"Equipment":              "EquipID",
+	"Equipment ID":           "EquipID",
+	"Equip":                  "EquipID",
+	"Equipment Type":         "EquipType",
+	"Point":                  "PointName",
+	"Description":            "Descriptor",
+	"Desc":                   "Descriptor",
+	"Point Description":      "Descriptor",
+	"Pt Type":                "PointType",
+	"Units":                  "EngineeringUnits",
+	"Eng Units":              "EngineeringUnits",
+	"Engineering Unit":       "EngineeringUnits",
+	"Sensor":                 "SensorType",
+	"Eng Low":                "EngineeringLow",
+	"Range Low":              "EngineeringLow",
+	"Low Range":              "EngineeringLow",
+	"Eng High":               "EngineeringHigh",
+	"Range High":             "EngineeringHigh",
+	"High Range":             "EngineeringHigh",
+	"Object Type":            "BACnetObjectType",
+	"Obj Type":               "BACnetObjectType",
+	"BACnet Obj Type":        "BACnetObjectType",
+	"Object Instance":        "BACnetObjectInstance",
+	"Obj Instance":           "BACnetObjectInstance",
+	"BACnet Obj Instance":    "BACnetObjectInstance",
+	"Object Name":            "BACnetObjectName",
+	"BACnet Obj Name":        "BACnetObjectName",
+	"Device Instance":        "BACnetDeviceInstance",
+	"Device ID":              "BACnetDeviceInstance",
+	"BACnet Device ID":       "BACnetDeviceInstance",
+	"BACnet Network Number":  "BACnetNetwork",
+	"Network Number":         "BACnetNetwork",
+	"IP Address":             "IPaddress",
+	"IP":                     "IPaddress",
+	"UDP Port":               "UDPport",
+	"MAC Address":            "MSTPaddress",
+	"MSTP MAC":               "MSTPaddress",
+	"MSTP Network Number":    "MSTPnetwork",
+	"Node":                   "NodeIdentifier",
+	"Node ID":                "NodeIdentifier",
+	"Included":               "IncludedInProject",
+	"Include":                "IncludedInProject",
+	"FLN Device System Name": "FLNdeviceSysName",
+	"BLN System Name":        "BLNSysName",
+	"Device System Name":     "DeviceSysName",
+}
+
+// Normalize reduces a header to the form aliases are keyed by, see
+// models.Normalize.
+func Normalize(header string) string {
+	return models.Normalize(header)
+}
+
+// DefaultAliases returns the built-in alias table: every datapoints column
+// under its own name plus the common spellings above.
+func DefaultAliases() Aliases {
+	a := make(Aliases, len(models.DataPointColumns)+len(defaultAliases))
+	for _, c := range models.DataPointColumns {
+		a[Normalize(c.Name)] = c.Name
+	}
+	for header, column := range defaultAliases {
+		a[Normalize(header)] = column
+	}
+	return a
+}
+
+// LoadAliases returns the default aliases extended with the JSON object of
+// header to column names stored at path. An empty path returns the defaults.
+func LoadAliases(path string) (Aliases, error) {
+	a := DefaultAliases()
+	if path == "" {
+		return a, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("importer: reading aliases: %w", err)
+	}
+
+	var extra map[string]string
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("importer: parsing aliases %s: %w", path, err)
+	}
+
+	return a.With(extra)
+}
+
+// With returns a copy of a extended with the given header to column names.
+// Every column must name a datapoints column.
+func (a Aliases) With(extra map[string]string) (Aliases, error) {
+	out := make(Aliases, len(a)+len(extra))
+	for k, v := range a {
+		out[k] = v
+	}
+
+	for header, column := range extra {
+		c, ok := models.DataPointColumn(column)
+		if !ok {
+			return nil, fmt.Errorf("importer: alias %q refers to unknown column %q", header, column)
+		}
+		out[Normalize(header)] = c.Name
+	}
+
+	return out, nil
+}
+
+// Lookup returns the column a header maps to.
+func (a Aliases) Lookup(header string) (string, bool) {
+	column, ok := a[Normalize(header)]
+	return column, ok
+}
@@ -0,0 +1,365 @@
+// Package importer turns uploaded point lists into datapoints rows.
+//
+// The first record of a file is its header. Each header is mapped onto a
+// datapoints column through an alias table, values are checked against the
+// column definitions, the trend limits and the BACnet addressing rules, and
+// every row is reported as imported or rejected, with warnings for the trend
+// values that are imported without being understood. Free-text units are mapped
+// onto the units catalogue.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pufington-pixie/haver/pkg/bacnet"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/trends"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+// ErrNoHeader is returned for a file without a header row.
+var ErrNoHeader = errors.New("importer: file has no header row")
+
+// ColumnMapping records which column a file header was mapped to. Column is
+// empty for headers that could not be mapped.
+type ColumnMapping struct {
+	Header string `json:"header"`
+	Column string `json:"column,omitempty"`
+}
+
+// RowResult is the outcome for one data row of the file.
+type RowResult struct {
+	// Row is the 1-based record number in the file, counted from the
+	// header row.
+	Row      int      `json:"row"`
+	Imported bool     `json:"imported"`
+	Errors   []string `json:"errors,omitempty"`
+	// Warnings are the values that were imported as they are, such as
+	// trend settings that cannot be read.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Report describes what an import did with a file.
+type Report struct {
+	Columns  []ColumnMapping `json:"columns"`
+	Unknown  []string        `json:"unknownColumns"`
+	Imported int             `json:"imported"`
+	Rejected int             `json:"rejected"`
+	Rows     []RowResult     `json:"rows"`
+
+	// UnmappedUnits lists the units of imported rows that are not in the
+	// catalogue. The rows are imported without a UnitId.
+	UnmappedUnits []string `json:"unmappedUnits"`
+}
+
+// Result is a parsed file. Rows holds the accepted rows with their values in
+// Columns order, ready to be handed to the datapoint store.
+type Result struct {
+	Columns []string
+	Rows    [][]string
+	Report  Report
+}
+
+// Options controls how a file is parsed.
+type Options struct {
+	// Aliases maps headers onto columns. DefaultAliases is used when nil.
+	Aliases Aliases
+
+	// HeaderRow is the row number of the first record in the file, used
+	// to number the rows in the report. Defaults to 1.
+	HeaderRow int
+
+	// Existing are the points already in the project. Rows addressing the
+	// same BACnet object as one of them are rejected.
+	Existing []models.DataPoint
+
+	// Units maps EngineeringUnits, or failing that NavigatorUnits, onto
+	// UnitId for the rows that do not set it. Units are left alone when nil.
+	Units *units.Catalogue
+
+	// LegacyTrends imports trend values that cannot be read, as older tools
+	// export them, with a warning. Their rows are rejected otherwise.
+	LegacyTrends bool
+}
+
+// Parse maps the header of records onto datapoints columns and validates
+// every following row. Rows that fail validation are reported and left out
+// of the result; blank rows are skipped silently.
+func Parse(records [][]string, opts Options) (*Result, error) {
+	if len(records) == 0 || len(records[0]) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	aliases := opts.Aliases
+	if aliases == nil {
+		aliases = DefaultAliases()
+	}
+
+	headerRow := opts.HeaderRow
+	if headerRow < 1 {
+		headerRow = 1
+	}
+
+	res := &Result{
+		Report: Report{Unknown: []string{}, Rows: []RowResult{}, UnmappedUnits: []string{}},
+	}
+
+	// Map the header, remembering the file index and definition of every
+	// column that is kept.
+	var (
+		indexes []int
+		defs    []models.Column
+		seen    = make(map[string]bool)
+	)
+	for i, header := range records[0] {
+		mapping := ColumnMapping{Header: header}
+		column, ok := aliases.Lookup(header)
+		if ok && !seen[column] {
+			def, _ := models.DataPointColumn(column)
+			seen[column] = true
+			mapping.Column = def.Name
+			indexes = append(indexes, i)
+			defs = append(defs, def)
+			res.Columns = append(res.Columns, def.Name)
+		} else {
+			res.Report.Unknown = append(res.Report.Unknown, header)
+		}
+		res.Report.Columns = append(res.Report.Columns, mapping)
+	}
+
+	for _, c := range models.DataPointColumns {
+		if c.Required && !seen[c.Name] {
+			return res, fmt.Errorf("importer: required column %s is missing", c.Name)
+		}
+	}
+
+	unitMap := newUnitMapper(opts.Units, res)
+
+	// The BACnet objects addressed so far, by existing point or file row
+	objects := make(map[bacnet.Key]string)
+	for _, dp := range opts.Existing {
+		if key, ok := bacnet.KeyOf(dp); ok {
+			objects[key] = fmt.Sprintf("datapoint %d", dp.ID)
+		}
+	}
+
+	for n, record := range records[1:] {
+		if blank(record) {
+			continue
+		}
+
+		result := RowResult{Row: headerRow + n + 1}
+		if len(record) != len(records[0]) {
+			result.Errors = append(result.Errors, fmt.Sprintf("row has %d fields, expected %d", len(record), len(records[0])))
+		}
+
+		row := make([]string, len(indexes))
+		for i, idx := range indexes {
+			var raw string
+			if idx < len(record) {
+				raw = record[idx]
+			}
+
+			value, err := NormalizeValue(defs[i], raw)
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			row[i] = value
+		}
+
+		if len(result.Errors) == 0 {
+			result.Errors, result.Warnings = checkTrends(res.Columns, row, opts.LegacyTrends)
+		}
+		if len(result.Errors) == 0 {
+			result.Errors = checkAddress(res.Columns, row, result.Row, objects)
+		}
+
+		if len(result.Errors) > 0 {
+			res.Report.Rejected++
+		} else {
+			result.Imported = true
+			res.Report.Imported++
+			res.Rows = append(res.Rows, unitMap.apply(row))
+		}
+		res.Report.Rows = append(res.Report.Rows, result)
+	}
+
+	return res, nil
+}
+
+// Discard marks every row as not imported, for when the accepted rows are
+// not written after all.
+func (r *Report) Discard() {
+	r.Imported = 0
+	for i := range r.Rows {
+		r.Rows[i].Imported = false
+	}
+}
+
+// NormalizeValue checks raw against the column definition and returns the
+// value in the form the store expects: trimmed text, a decimal integer or
+// "1"/"0" for bit columns. Empty values are stored as NULL.
+func NormalizeValue(c models.Column, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		if c.Required {
+			return "", fmt.Errorf("%s is required", c.Name)
+		}
+		return "", nil
+	}
+
+	switch c.Type {
+	case models.ColumnInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %q is not an integer", c.Name, value)
+		}
+		return strconv.Itoa(n), nil
+
+	case models.ColumnBool:
+		switch strings.ToLower(value) {
+		case "1", "true", "yes", "y", "x":
+			return "1", nil
+		case "0", "false", "no", "n":
+			return "0", nil
+		}
+		return "", fmt.Errorf("%s: %q is not a yes/no value", c.Name, value)
+
+	default:
+		if n := utf8.RuneCountInString(value); c.Size > 0 && n > c.Size {
+			return "", fmt.Errorf("%s: value is %d characters, at most %d allowed", c.Name, n, c.Size)
+		}
+		return value, nil
+	}
+}
+
+// unitMapper fills in the UnitId of rows from their free-text units.
+type unitMapper struct {
+	catalogue *units.Catalogue
+	report    *Report
+
+	// The indexes of the unit columns in a row, -1 when missing
+	engineering, navigator, unitID int
+	// Whether UnitId was added to the columns of the file
+	added bool
+
+	unmapped map[string]bool
+}
+
+// newUnitMapper prepares the mapping for the columns of res, adding UnitId
+// to them if the file has units but no UnitId column.
+func newUnitMapper(catalogue *units.Catalogue, res *Result) *unitMapper {
+	m := &unitMapper{
+		report:      &res.Report,
+		engineering: -1,
+		navigator:   -1,
+		unitID:      -1,
+		unmapped:    make(map[string]bool),
+	}
+	for i, c := range res.Columns {
+		switch c {
+		case "EngineeringUnits":
+			m.engineering = i
+		case "NavigatorUnits":
+			m.navigator = i
+		case "UnitId":
+			m.unitID = i
+		}
+	}
+
+	if catalogue == nil || m.engineering < 0 && m.navigator < 0 {
+		return m
+	}
+	m.catalogue = catalogue
+	if m.unitID < 0 {
+		m.unitID = len(res.Columns)
+		m.added = true
+		res.Columns = append(res.Columns, "UnitId")
+	}
+	return m
+}
+
+// apply returns row with its UnitId set from the first of its units found in
+// the catalogue, recording the units that are not.
+func (m *unitMapper) apply(row []string) []string {
+	if m.catalogue == nil {
+		return row
+	}
+	if m.added {
+		row = append(row, "")
+	}
+	if row[m.unitID] != "" {
+		return row
+	}
+
+	var spellings []string
+	for _, i := range []int{m.engineering, m.navigator} {
+		if i >= 0 && row[i] != "" {
+			spellings = append(spellings, row[i])
+		}
+	}
+	for _, spelling := range spellings {
+		if u, ok := m.catalogue.Lookup(spelling); ok {
+			row[m.unitID] = strconv.Itoa(u.ID)
+			return row
+		}
+	}
+
+	for _, spelling := range spellings {
+		if !m.unmapped[spelling] {
+			m.unmapped[spelling] = true
+			m.report.UnmappedUnits = append(m.report.UnmappedUnits, spelling)
+		}
+	}
+	return row
+}
+
+// checkAddress checks the BACnet addressing of a row and claims the object it
+// addresses in objects.
+func checkAddress(columns, row []string, n int, objects map[bacnet.Key]string) []string {
+	var dp models.DataPoint
+	for i, v := range row {
+		dp.Set(columns[i], v)
+	}
+
+	errs := bacnet.Check(dp)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if key, ok := bacnet.KeyOf(dp); ok {
+		if owner, dup := objects[key]; dup {
+			return []string{fmt.Sprintf("BACnet object %s is already used by %s", key, owner)}
+		}
+		objects[key] = fmt.Sprintf("row %d", n)
+	}
+	return nil
+}
+
+// checkTrends checks the trend configuration of a row against the limits of
+// a field panel. Values that cannot be read are only warned about when
+// legacy is set.
+func checkTrends(columns, row []string, legacy bool) (errs, warnings []string) {
+	var dp models.DataPoint
+	for i, v := range row {
+		dp.Set(columns[i], v)
+	}
+	if !legacy {
+		return trends.Check(dp, trends.DefaultLimits), nil
+	}
+	return trends.CheckImport(dp, trends.DefaultLimits)
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,274 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+func TestParseTrends(t *testing.T) {
+	records := [][]string{
+		{"EquipID", "TrendInterval1", "PanelSamples1"},
+		{"AHU-1", "15 min", "672"},
+		{"AHU-1", "every 15", "672"},
+		{"AHU-1", "15 min", "20000"},
+	}
+
+	tests := []struct {
+		name     string
+		legacy   bool
+		imported []bool
+		warnings [][]string
+	}{
+		{
+			name:     "unreadable values reject the row",
+			imported: []bool{true, false, false},
+			warnings: [][]string{nil, nil, nil},
+		},
+		{
+			name:     "legacy values are imported with a warning",
+			legacy:   true,
+			imported: []bool{true, true, false},
+			warnings: [][]string{nil, {`TrendInterval1: "every 15" is not an interval`}, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(records, Options{LegacyTrends: tt.legacy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var imported []bool
+			var warnings [][]string
+			for _, row := range res.Report.Rows {
+				imported = append(imported, row.Imported)
+				warnings = append(warnings, row.Warnings)
+			}
+			if !reflect.DeepEqual(imported, tt.imported) {
+				t.Errorf("imported = %v, want %v", imported, tt.imported)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	catalogue := units.New(units.Standard, nil)
+
+	tests := []struct {
+		name    string
+		records [][]string
+		opts    Options
+		columns []string
+		rows    [][]string
+		unknown []string
+		report  []RowResult
+		wantErr error
+	}{
+		{
+			name: "headers are matched through aliases",
+			records: [][]string{
+				{"Equipment", " point name ", "Desc", "Included"},
+				{"AHU-1", "SAT", "Supply air", "yes"},
+			},
+			columns: []string{"EquipID", "PointName", "Descriptor", "IncludedInProject"},
+			rows:    [][]string{{"AHU-1", "SAT", "Supply air", "1"}},
+			unknown: []string{},
+			report:  []RowResult{{Row: 2, Imported: true}},
+		},
+		{
+			name: "extra aliases",
+			records: [][]string{
+				{"Asset", "Tag"},
+				{"AHU-1", "SAT"},
+			},
+			opts:    Options{Aliases: mustAliases(t, map[string]string{"Asset": "EquipID", "Tag": "PointName"})},
+			columns: []string{"EquipID", "PointName"},
+			rows:    [][]string{{"AHU-1", "SAT"}},
+			unknown: []string{},
+			report:  []RowResult{{Row: 2, Imported: true}},
+		},
+		{
+			name: "duplicate and unknown headers are reported",
+			records: [][]string{
+				{"EquipID", "Colour", "Equipment", "PointName"},
+				{"AHU-1", "red", "AHU-2", "SAT"},
+			},
+			columns: []string{"EquipID", "PointName"},
+			rows:    [][]string{{"AHU-1", "SAT"}},
+			unknown: []string{"Colour", "Equipment"},
+			report:  []RowResult{{Row: 2, Imported: true}},
+		},
+		{
+			name: "rows failing validation are rejected",
+			records: [][]string{
+				{"EquipID", "NodeIdentifier", "IncludedInProject"},
+				{"AHU-1", "3", "no"},
+				{"", "3", "no"},
+				{"AHU-1", "three", "no"},
+				{"AHU-1", "3", "maybe"},
+				{"AHU-1", "3"},
+				{" ", "", ""},
+				{strings.Repeat("A", 46), "", ""},
+			},
+			columns: []string{"EquipID", "NodeIdentifier", "IncludedInProject"},
+			rows:    [][]string{{"AHU-1", "3", "0"}},
+			unknown: []string{},
+			report: []RowResult{
+				{Row: 2, Imported: true},
+				{Row: 3, Errors: []string{"EquipID is required"}},
+				{Row: 4, Errors: []string{`NodeIdentifier: "three" is not an integer`}},
+				{Row: 5, Errors: []string{`IncludedInProject: "maybe" is not a yes/no value`}},
+				{Row: 6, Errors: []string{"row has 2 fields, expected 3"}},
+				{Row: 8, Errors: []string{"EquipID: value is 46 characters, at most 45 allowed"}},
+			},
+		},
+		{
+			name: "rows are numbered from the header row",
+			records: [][]string{
+				{"EquipID"},
+				{"AHU-1"},
+				{""},
+				{"AHU-2"},
+			},
+			opts:    Options{HeaderRow: 3},
+			columns: []string{"EquipID"},
+			rows:    [][]string{{"AHU-1"}, {"AHU-2"}},
+			unknown: []string{},
+			report:  []RowResult{{Row: 4, Imported: true}, {Row: 6, Imported: true}},
+		},
+		{
+			name: "BACnet objects are claimed once",
+			records: [][]string{
+				{"EquipID", "BACnetDeviceInstance", "BACnetObjectType", "BACnetObjectInstance"},
+				{"AHU-1", "100", "AI", "1"},
+				{"AHU-1", "100", "analog-input", "1"},
+				{"AHU-1", "100", "AI", "2"},
+				{"AHU-1", "100", "AV", "1"},
+				{"AHU-1", "100", "XX", "3"},
+			},
+			opts: Options{Existing: []models.DataPoint{
+				{ID: 7, BACnetDeviceInstance: "100", BACnetObjectType: "AI", BACnetObjectInstance: "2"},
+			}},
+			columns: []string{"EquipID", "BACnetDeviceInstance", "BACnetObjectType", "BACnetObjectInstance"},
+			rows: [][]string{
+				{"AHU-1", "100", "AI", "1"},
+				{"AHU-1", "100", "AV", "1"},
+			},
+			unknown: []string{},
+			report: []RowResult{
+				{Row: 2, Imported: true},
+				{Row: 3, Errors: []string{"BACnet object device 100, analog-input 1 is already used by row 2"}},
+				{Row: 4, Errors: []string{"BACnet object device 100, analog-input 2 is already used by datapoint 7"}},
+				{Row: 5, Imported: true},
+				{Row: 6, Errors: []string{`BACnetObjectType: "XX" is not a standard BACnet object type`}},
+			},
+		},
+		{
+			name: "units are mapped onto the catalogue",
+			records: [][]string{
+				{"EquipID", "Units", "NavigatorUnits"},
+				{"AHU-1", "°F", ""},
+				{"AHU-1", "furlongs", "%"},
+				{"AHU-1", "furlongs", ""},
+			},
+			opts:    Options{Units: catalogue},
+			columns: []string{"EquipID", "EngineeringUnits", "NavigatorUnits", "UnitId"},
+			rows: [][]string{
+				{"AHU-1", "°F", "", "64"},
+				{"AHU-1", "furlongs", "%", "98"},
+				{"AHU-1", "furlongs", "", ""},
+			},
+			unknown: []string{},
+			report:  []RowResult{{Row: 2, Imported: true}, {Row: 3, Imported: true}, {Row: 4, Imported: true}},
+		},
+		{
+			name:    "required column missing",
+			records: [][]string{{"PointName"}, {"SAT"}},
+			wantErr: errors.New("importer: required column EquipID is missing"),
+		},
+		{
+			name:    "no header",
+			records: [][]string{},
+			wantErr: ErrNoHeader,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.records, tt.opts)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res.Columns, tt.columns) {
+				t.Errorf("Columns = %q, want %q", res.Columns, tt.columns)
+			}
+			if !reflect.DeepEqual(res.Rows, tt.rows) {
+				t.Errorf("Rows = %q, want %q", res.Rows, tt.rows)
+			}
+			if !reflect.DeepEqual(res.Report.Unknown, tt.unknown) {
+				t.Errorf("Unknown = %q, want %q", res.Report.Unknown, tt.unknown)
+			}
+			if !reflect.DeepEqual(res.Report.Rows, tt.report) {
+				t.Errorf("Report.Rows = %+v, want %+v", res.Report.Rows, tt.report)
+			}
+			if res.Report.Imported != len(tt.rows) || res.Report.Rejected != len(tt.report)-len(tt.rows) {
+				t.Errorf("imported %d and rejected %d, want %d and %d", res.Report.Imported, res.Report.Rejected, len(tt.rows), len(tt.report)-len(tt.rows))
+			}
+		})
+	}
+}
+
+func TestParseReportsUnmappedUnitsOnce(t *testing.T) {
+	records := [][]string{
+		{"EquipID", "EngineeringUnits"},
+		{"AHU-1", "furlongs"},
+		{"AHU-2", "furlongs"},
+		{"AHU-3", "°F"},
+	}
+	res, err := Parse(records, Options{Units: units.New(units.Standard, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"furlongs"}; !reflect.DeepEqual(res.Report.UnmappedUnits, want) {
+		t.Errorf("UnmappedUnits = %q, want %q", res.Report.UnmappedUnits, want)
+	}
+}
+
+func TestAliasesWith(t *testing.T) {
+	if _, err := DefaultAliases().With(map[string]string{"Tag": "Colour"}); err == nil {
+		t.Error("With() accepted an alias for an unknown column")
+	}
+
+	a, err := DefaultAliases().With(map[string]string{"Asset Tag": "pointname"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if column, ok := a.Lookup("asset_tag"); !ok || column != "PointName" {
+		t.Errorf("Lookup(asset_tag) = %q, %t, want PointName", column, ok)
+	}
+	if _, ok := DefaultAliases().Lookup("Asset Tag"); ok {
+		t.Error("With() changed the aliases it extends")
+	}
+}
+
+func mustAliases(t *testing.T, extra map[string]string) Aliases {
+	t.Helper()
+	a, err := DefaultAliases().With(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
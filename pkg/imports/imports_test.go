@@ -0,0 +1,147 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/store/memstore"
+)
+
+// newService returns a Service over a memstore holding project 1. Its
+// workers are not started.
+func newService(t *testing.T, opts Options) (*Service, store.Store) {
+	t.Helper()
+	ctx := context.Background()
+	s := memstore.New()
+	if err := s.Services.Upsert(ctx, models.Service{ID: 1, Name: "Controls"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Projects.Create(ctx, models.Project{ID: 1, Name: "Project 1", Service: models.Service{ID: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	opts.UploadDir = t.TempDir()
+	service := NewService(s, opts)
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+	return service, s
+}
+
+func TestRun(t *testing.T) {
+	const file = "EquipID,PointName,Colour\nAHU-1,SAT,red\n,RAT,blue\n"
+
+	tests := []struct {
+		name     string
+		options  models.ImportOptions
+		status   string
+		err      error
+		imported int
+	}{
+		{name: "rejected rows reject the file", status: models.ImportRejected, err: ErrRowsRejected},
+		{name: "partial", options: models.ImportOptions{Partial: true}, status: models.ImportSucceeded, imported: 1},
+		{name: "strict", options: models.ImportOptions{Strict: true, Partial: true}, status: models.ImportRejected, err: ErrFileRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, s := newService(t, Options{})
+			ctx := context.Background()
+
+			imp := models.Import{ProjectID: 1, Options: tt.options}
+			if err := service.Create(ctx, &imp, "points.csv", strings.NewReader(file)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := service.Run(ctx, &imp)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Run() error = %v, want %v", err, tt.err)
+			}
+
+			stored, err := s.Imports.Get(ctx, imp.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != tt.status || stored.ImportedRows != tt.imported {
+				t.Errorf("import is %s with %d rows, want %s with %d", stored.Status, stored.ImportedRows, tt.status, tt.imported)
+			}
+			points, err := s.DataPoints.ListByProject(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) != tt.imported {
+				t.Errorf("%d points stored, want %d", len(points), tt.imported)
+			}
+		})
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	service, _ := newService(t, Options{QueueSize: 1})
+
+	if err := service.Enqueue(models.Import{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Enqueue(models.Import{ID: 2}); err != ErrQueueFull {
+		t.Errorf("Enqueue() on a full queue = %v, want ErrQueueFull", err)
+	}
+
+	if err := service.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Enqueue(models.Import{ID: 3}); err != ErrClosed {
+		t.Errorf("Enqueue() after Shutdown = %v, want ErrClosed", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	service, s := newService(t, Options{})
+	ctx := context.Background()
+
+	// Left running and left queued by the previous run
+	running := models.Import{ProjectID: 1}
+	if err := service.Create(ctx, &running, "running.csv", strings.NewReader("EquipID\nAHU-1\n")); err != nil {
+		t.Fatal(err)
+	}
+	running.Status = models.ImportRunning
+	if err := s.Imports.Update(ctx, running); err != nil {
+		t.Fatal(err)
+	}
+	queued := models.Import{ProjectID: 1}
+	if err := service.Create(ctx, &queued, "queued.csv", strings.NewReader("EquipID\nAHU-2\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.Recover(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Imports.Get(ctx, running.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"import was interrupted by a restart"}; got.Status != models.ImportFailed || !reflect.DeepEqual(got.Errors, want) || got.FinishedAt == nil {
+		t.Errorf("interrupted import is %s with %q, want failed with %q", got.Status, got.Errors, want)
+	}
+
+	// The queued import is run once the workers start
+	service.Start()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err = s.Imports.Get(ctx, queued.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != models.ImportQueued && got.Status != models.ImportRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued import is still %s", got.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got.Status != models.ImportSucceeded || got.ImportedRows != 1 {
+		t.Errorf("queued import is %s with %d rows, want succeeded with 1", got.Status, got.ImportedRows)
+	}
+}
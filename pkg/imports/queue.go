@@ -0,0 +1,164 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when every queue slot is taken.
+	ErrQueueFull = errors.New("imports: queue is full")
+
+	// ErrClosed is returned by Enqueue once Shutdown has been called.
+	ErrClosed = errors.New("imports: service is shut down")
+)
+
+// Start starts the background workers.
+func (s *Service) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+}
+
+// Enqueue schedules a queued import for a background worker.
+func (s *Service) Enqueue(imp models.Import) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	select {
+	case s.jobs <- imp.ID:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Recover picks up the imports left behind by the previous run. Queued
+// imports never started and are queued again. Imports that were running
+// when the process died are marked failed: their transaction was rolled
+// back, but whether it committed just before the crash cannot be told.
+func (s *Service) Recover(ctx context.Context) error {
+	imports, err := s.store.Imports.ListByStatus(ctx, models.ImportQueued, models.ImportRunning)
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range imports {
+		if imp.Status == models.ImportRunning {
+			finished := time.Now().UTC()
+			imp.Status = models.ImportFailed
+			imp.Errors = append(imp.Errors, "import was interrupted by a restart")
+			imp.ProcessedRows = 0
+			imp.FinishedAt = &finished
+			if err := s.store.Imports.Update(ctx, imp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.Enqueue(imp); err != nil {
+			// It stays queued and is picked up by the next restart.
+			log.Printf("imports: cannot resume import %d: %v", imp.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting imports and waits for the running ones to
+// finish. If ctx expires first the running imports are cancelled, rolled
+// back and left queued so Recover resumes them on the next start. Imports
+// still waiting in the queue stay queued as well.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.quit)
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *Service) work() {
+	defer s.wg.Done()
+
+	for {
+		// Check quit first so no new import starts once shutting down.
+		select {
+		case <-s.quit:
+			return
+		default:
+		}
+
+		select {
+		case <-s.quit:
+			return
+		case id := <-s.jobs:
+			s.process(id)
+		}
+	}
+}
+
+// process runs one queued import.
+func (s *Service) process(id int) {
+	imp, err := s.store.Imports.Get(s.ctx, id)
+	if err != nil {
+		log.Printf("imports: loading import %d: %v", id, err)
+		return
+	}
+	if imp.Status != models.ImportQueued {
+		return
+	}
+
+	s.Run(s.ctx, &imp)
+	if imp.Status != models.ImportFailed || s.ctx.Err() == nil {
+		return
+	}
+
+	// Cancelled by Shutdown: nothing was committed, so resume it later.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	imp.Status = models.ImportQueued
+	imp.ProcessedRows = 0
+	imp.FinishedAt = nil
+	imp.DurationMs = 0
+	imp.Errors = []string{"import was interrupted by a shutdown and will resume"}
+	if err := s.store.Imports.Update(ctx, imp); err != nil {
+		log.Printf("imports: requeueing import %d: %v", id, err)
+	}
+}
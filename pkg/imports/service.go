@@ -0,0 +1,376 @@
+// Package imports runs point list imports: it keeps the uploaded files,
+// records every import in the import store and loads the files into their
+// project either straight away or on a bounded pool of background workers.
+package imports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pufington-pixie/haver/pkg/equipment"
+	"github.com/pufington-pixie/haver/pkg/importer"
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/pointlist"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/topology"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+// maxErrors caps the number of errors kept on an import record.
+const maxErrors = 100
+
+var (
+	// ErrRowsRejected rejects a file because some of its rows failed
+	// validation.
+	ErrRowsRejected = errors.New("file has rejected rows, nothing was imported")
+
+	// ErrFileRejected wraps problems with the file as a whole, such as a
+	// missing header or a malformed CSV.
+	ErrFileRejected = errors.New("file rejected")
+)
+
+// Options configures a Service.
+type Options struct {
+	// Aliases maps point list headers onto datapoints columns. The
+	// importer defaults are used when nil.
+	Aliases importer.Aliases
+
+	// UploadDir is where uploaded point lists are kept. Defaults to
+	// "uploads".
+	UploadDir string
+
+	// Workers is the number of background imports run at once. Defaults
+	// to 2.
+	Workers int
+
+	// QueueSize is the number of background imports that may wait for a
+	// worker. Defaults to 100.
+	QueueSize int
+}
+
+// Service runs point list imports.
+type Service struct {
+	store     store.Store
+	aliases   importer.Aliases
+	uploadDir string
+	workers   int
+
+	jobs   chan int
+	quit   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+// NewService returns a Service storing imports in s. Background imports
+// only run once Start has been called.
+func NewService(s store.Store, opts Options) *Service {
+	if opts.Aliases == nil {
+		opts.Aliases = importer.DefaultAliases()
+	}
+	if opts.UploadDir == "" {
+		opts.UploadDir = "uploads"
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		store:     s,
+		aliases:   opts.Aliases,
+		uploadDir: opts.UploadDir,
+		workers:   opts.Workers,
+		jobs:      make(chan int, opts.QueueSize),
+		quit:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Aliases returns the alias table imports start from.
+func (s *Service) Aliases() importer.Aliases {
+	return s.aliases
+}
+
+// Create keeps the uploaded file under a unique name in the upload directory
+// and records imp for it. imp must carry the project, uploader, mode and
+// options; the file name, checksum, size and status are filled in.
+func (s *Service) Create(ctx context.Context, imp *models.Import, fileName string, file io.Reader) error {
+	imp.FileName = filepath.Base(fileName)
+	imp.Status = models.ImportQueued
+	imp.StartedAt = time.Now().UTC()
+	imp.Errors = []string{}
+	if imp.Mode == "" {
+		imp.Mode = models.ImportModeAppend
+	}
+
+	// Create the uploads directory if it doesn't exist
+	if err := os.MkdirAll(s.uploadDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	ext := strings.ToLower(filepath.Ext(imp.FileName))
+	if ext == "" {
+		ext = ".csv"
+	}
+
+	// Create the destination file on the server
+	dst, err := os.CreateTemp(s.uploadDir, fmt.Sprintf("%d-*%s", imp.ProjectID, ext))
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	imp.StoredName = filepath.Base(dst.Name())
+
+	// Copy the contents of the uploaded file, hashing it on the way
+	hash := sha256.New()
+	imp.Size, err = io.Copy(io.MultiWriter(dst, hash), file)
+	if err == nil {
+		err = dst.Close()
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return err
+	}
+	imp.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.store.Imports.Create(ctx, imp); err != nil {
+		os.Remove(dst.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Open opens the stored file of an import.
+func (s *Service) Open(imp models.Import) (*os.File, error) {
+	return os.Open(filepath.Join(s.uploadDir, filepath.Base(imp.StoredName)))
+}
+
+// Run loads the stored file of imp into its project and records the outcome
+// on imp and in the import store. The returned error explains why the file
+// was rejected or the import failed; imp.Status tells which.
+func (s *Service) Run(ctx context.Context, imp *models.Import) (*importer.Result, error) {
+	imp.Status = models.ImportRunning
+	imp.StartedAt = time.Now().UTC()
+	imp.ProcessedRows = 0
+	imp.Errors = []string{}
+	if err := s.store.Imports.Update(ctx, *imp); err != nil {
+		return nil, err
+	}
+
+	result, err := s.load(ctx, imp)
+
+	switch {
+	case err == nil:
+		imp.Status = models.ImportSucceeded
+	case errors.Is(err, ErrRowsRejected), errors.Is(err, ErrFileRejected):
+		imp.Status = models.ImportRejected
+	default:
+		imp.Status = models.ImportFailed
+	}
+	s.record(imp, result, err)
+
+	finished := time.Now().UTC()
+	imp.FinishedAt = &finished
+	imp.DurationMs = finished.Sub(imp.StartedAt).Milliseconds()
+
+	// The outcome is recorded even if ctx was cancelled part way.
+	uctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if uerr := s.store.Imports.Update(uctx, *imp); uerr != nil && err == nil {
+		err = uerr
+	}
+
+	return result, err
+}
+
+// units loads the units catalogue.
+func (s *Service) units(ctx context.Context) (*units.Catalogue, error) {
+	list, err := s.store.Units.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.store.Units.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return units.New(list, aliases), nil
+}
+
+// record copies the row counts, errors and warnings of result and err onto
+// imp.
+func (s *Service) record(imp *models.Import, result *importer.Result, err error) {
+	imp.Errors = []string{}
+	imp.Warnings = []string{}
+	if err != nil {
+		imp.Errors = append(imp.Errors, err.Error())
+	}
+	if result == nil {
+		return
+	}
+
+	var warnings []string
+	for _, spelling := range result.Report.UnmappedUnits {
+		warnings = append(warnings, fmt.Sprintf("unit %q is not in the units catalogue", spelling))
+	}
+	for _, row := range result.Report.Rows {
+		for _, msg := range row.Warnings {
+			warnings = append(warnings, fmt.Sprintf("row %d: %s", row.Row, msg))
+		}
+	}
+	for _, msg := range warnings {
+		if len(imp.Warnings) >= maxErrors {
+			imp.Warnings = append(imp.Warnings, "too many warnings, the rest were left out")
+			break
+		}
+		imp.Warnings = append(imp.Warnings, msg)
+	}
+
+	imp.TotalRows = len(result.Report.Rows)
+	imp.ImportedRows = result.Report.Imported
+	imp.RejectedRows = result.Report.Rejected
+
+	for _, row := range result.Report.Rows {
+		for _, msg := range row.Errors {
+			if len(imp.Errors) >= maxErrors {
+				imp.Errors = append(imp.Errors, "too many errors, the rest were left out")
+				return
+			}
+			imp.Errors = append(imp.Errors, fmt.Sprintf("row %d: %s", row.Row, msg))
+		}
+	}
+}
+
+// Parse reads a point list for imp and validates its rows against the
+// project without storing anything. Problems with the file as a whole are
+// wrapped in ErrFileRejected.
+func (s *Service) Parse(ctx context.Context, imp models.Import, file io.Reader) (*importer.Result, error) {
+	aliases, err := s.aliases.With(imp.Options.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileRejected, err)
+	}
+
+	// Read the CSV or XLSX file
+	records, err := pointlist.Read(file, imp.FileName, pointlist.ReadOptions{
+		Sheet:     imp.Options.Sheet,
+		HeaderRow: imp.Options.HeaderRow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileRejected, err)
+	}
+
+	// Rows must not address the BACnet objects of the points they are
+	// added to
+	var existing []models.DataPoint
+	if imp.Mode != models.ImportModeReplace {
+		existing, err = s.store.DataPoints.ListByProject(ctx, imp.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	catalogue, err := s.units(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the header and validate the rows
+	result, err := importer.Parse(records, importer.Options{
+		Aliases:      aliases,
+		HeaderRow:    imp.Options.HeaderRow,
+		Existing:     existing,
+		Units:        catalogue,
+		LegacyTrends: imp.Options.LegacyTrends,
+	})
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrFileRejected, err)
+	}
+
+	if imp.Options.Strict && len(result.Report.Unknown) > 0 {
+		return result, fmt.Errorf("%w: file has unknown columns", ErrFileRejected)
+	}
+
+	return result, nil
+}
+
+// load parses, validates and stores the file of imp.
+func (s *Service) load(ctx context.Context, imp *models.Import) (*importer.Result, error) {
+	file, err := s.Open(*imp)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result, err := s.Parse(ctx, *imp, file)
+	if err != nil {
+		if result != nil {
+			result.Report.Discard()
+		}
+		return result, err
+	}
+
+	// A point list is only loaded as a whole unless the caller opts out
+	if result.Report.Rejected > 0 && !imp.Options.Partial {
+		result.Report.Discard()
+		return result, ErrRowsRejected
+	}
+
+	// Make the errors found so far visible while the rows are written
+	s.record(imp, result, nil)
+	imp.ImportedRows = 0
+	if err := s.store.Imports.Update(ctx, *imp); err != nil {
+		return result, err
+	}
+
+	// Insert the accepted rows into the database in one transaction
+	opts := store.ImportOptions{
+		Mode: store.ImportAppend,
+		Progress: func(rows int) {
+			imp.ProcessedRows = rows
+			if err := s.store.Imports.UpdateProgress(ctx, imp.ID, rows); err != nil {
+				// Only the progress shown while polling is stale
+				log.Printf("imports: recording the progress of import %d: %v", imp.ID, err)
+			}
+		},
+	}
+	if imp.Mode == models.ImportModeReplace {
+		opts.Mode = store.ImportReplace
+	}
+
+	err = s.store.DataPoints.Import(ctx, imp.ProjectID, result.Columns, result.Rows, opts)
+	if err != nil {
+		imp.ProcessedRows = 0
+		result.Report.Discard()
+		return result, err
+	}
+
+	// The points are in, a stale equipment list or topology is not worth
+	// failing for
+	if err := equipment.Sync(ctx, s.store, imp.ProjectID); err != nil {
+		log.Printf("imports: updating the equipment of project %d: %v", imp.ProjectID, err)
+	}
+	if err := topology.Sync(ctx, s.store, imp.ProjectID); err != nil {
+		log.Printf("imports: updating the topology of project %d: %v", imp.ProjectID, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ColumnType is the kind of value stored in a datapoints column.
+type ColumnType int
+
+const (
+	// ColumnText is a varchar column limited to Size characters.
+	ColumnText ColumnType = iota
+	// ColumnInt is an int column.
+	ColumnInt
+	// ColumnBool is a bit(1) column.
+	ColumnBool
+)
+
+// Column describes one user editable column of the datapoints table.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Size     int
+	Required bool
+}
+
+// DataPointColumns lists the datapoints columns in table order. Id and
+// ProjectId are managed by the API and are not part of the list.
+var DataPointColumns = []Column{
+	{Name: "EquipID", Type: ColumnText, Size: 45, Required: true},
+	{Name: "System", Type: ColumnText, Size: 45},
+	{Name: "EquipType", Type: ColumnText, Size: 45},
+	{Name: "PointName", Type: ColumnText, Size: 128},
+	{Name: "Descriptor", Type: ColumnText, Size: 128},
+	{Name: "Function", Type: ColumnText, Size: 128},
+	{Name: "PointType", Type: ColumnText, Size: 45},
+	{Name: "EngineeringUnits", Type: ColumnText, Size: 45},
+	{Name: "Slope", Type: ColumnText, Size: 45},
+	{Name: "Intercept", Type: ColumnText, Size: 45},
+	{Name: "SensorType", Type: ColumnText, Size: 45},
+	{Name: "EngineeringLow", Type: ColumnText, Size: 45},
+	{Name: "EngineeringHigh", Type: ColumnText, Size: 45},
+	{Name: "COVTrend", Type: ColumnText, Size: 45},
+	{Name: "Collection", Type: ColumnText, Size: 45},
+	{Name: "PanelSamples", Type: ColumnText, Size: 45},
+	{Name: "PCSamples", Type: ColumnText, Size: 45},
+	{Name: "COVLimit", Type: ColumnText, Size: 45},
+	{Name: "TrendInterval1", Type: ColumnText, Size: 45},
+	{Name: "Collection1", Type: ColumnText, Size: 45},
+	{Name: "PanelSamples1", Type: ColumnText, Size: 45},
+	{Name: "PCDays1", Type: ColumnText, Size: 45},
+	{Name: "TrendInterval2", Type: ColumnText, Size: 45},
+	{Name: "Collection2", Type: ColumnText, Size: 45},
+	{Name: "PanelSamples2", Type: ColumnText, Size: 45},
+	{Name: "PCDays2", Type: ColumnText, Size: 45},
+	{Name: "TrendInterval3", Type: ColumnText, Size: 45},
+	{Name: "Collection3", Type: ColumnText, Size: 45},
+	{Name: "PanelSamples3", Type: ColumnText, Size: 45},
+	{Name: "PCDays3", Type: ColumnText, Size: 45},
+	{Name: "TrendInterval4", Type: ColumnText, Size: 45},
+	{Name: "Collection4", Type: ColumnText, Size: 45},
+	{Name: "PanelSamples4", Type: ColumnText, Size: 45},
+	{Name: "PCDays4", Type: ColumnText, Size: 45},
+	{Name: "EquipRef", Type: ColumnText, Size: 45},
+	{Name: "DisplayMode", Type: ColumnText, Size: 45},
+	{Name: "BLNType", Type: ColumnText, Size: 45},
+	{Name: "BLNSysName", Type: ColumnText, Size: 45},
+	{Name: "NodeIdentifier", Type: ColumnInt, Size: 0},
+	{Name: "DeviceSysName", Type: ColumnText, Size: 45},
+	{Name: "BACnetObjectType", Type: ColumnText, Size: 128},
+	{Name: "BACnetObjectInstance", Type: ColumnText, Size: 128},
+	{Name: "BACnetObjectName", Type: ColumnText, Size: 128},
+	{Name: "BACnetDeviceInstance", Type: ColumnText, Size: 128},
+	{Name: "AddressTypeDHCP", Type: ColumnText, Size: 45},
+	{Name: "IPaddress", Type: ColumnText, Size: 45},
+	{Name: "UDPport", Type: ColumnText, Size: 128},
+	{Name: "BACnetNetwork", Type: ColumnInt, Size: 0},
+	{Name: "MSTPaddress", Type: ColumnText, Size: 45},
+	{Name: "MSTPnetwork", Type: ColumnText, Size: 45},
+	{Name: "ApogeePtType", Type: ColumnText, Size: 45},
+	{Name: "Virtual", Type: ColumnText, Size: 45},
+	{Name: "IPport", Type: ColumnText, Size: 128},
+	{Name: "InsightNodeNumber", Type: ColumnInt, Size: 0},
+	{Name: "FLNdeviceSysName", Type: ColumnText, Size: 45},
+	{Name: "IncludedInProject", Type: ColumnBool, Size: 0},
+	{Name: "MediumId", Type: ColumnInt, Size: 0},
+	{Name: "ReportGroupId", Type: ColumnInt, Size: 0},
+	{Name: "Medium", Type: ColumnText, Size: 45},
+	{Name: "ReportGroup", Type: ColumnText, Size: 128},
+	{Name: "UnitId", Type: ColumnInt, Size: 0},
+	{Name: "NavigatorUnits", Type: ColumnText, Size: 45},
+	{Name: "MatchingRatio", Type: ColumnText, Size: 10},
+	{Name: "LocalRef", Type: ColumnText, Size: 10},
+	{Name: "AppInstanceId", Type: ColumnText, Size: 10},
+	{Name: "Memo", Type: ColumnText, Size: 10},
+}
+
+// DataPointColumn looks up a datapoints column by name, ignoring case the
+// same way MySQL does.
+func DataPointColumn(name string) (Column, bool) {
+	for _, c := range DataPointColumns {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Normalize reduces a spelling from a point list to the form lookup tables
+// are keyed by: lower case letters and digits only, so "Point Name",
+// "point_name" and "Point Name?" all become "pointname".
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
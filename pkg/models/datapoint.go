@@ -0,0 +1,230 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DataPoint represents a point of a project's point list. Every datapoints
+// column is a field tagged with its column name. Text columns hold "" for
+// NULL; the nullable int and bit columns are pointers.
+//
+// swagger:model
+type DataPoint struct {
+	// The unique identifier of the datapoint.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The project the datapoint belongs to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// Equipment and point identification.
+	EquipID    string `json:"EquipID" db:"EquipID"`
+	System     string `json:"System" db:"System"`
+	EquipType  string `json:"EquipType" db:"EquipType"`
+	PointName  string `json:"point_name" db:"PointName"`
+	Descriptor string `json:"descriptor" db:"Descriptor"`
+	Function   string `json:"Function" db:"Function"`
+	PointType  string `json:"point_type" db:"PointType"`
+
+	// Engineering units and sensor scaling.
+	EngineeringUnits string `json:"EngineeringUnits" db:"EngineeringUnits"`
+	Slope            string `json:"Slope" db:"Slope"`
+	Intercept        string `json:"Intercept" db:"Intercept"`
+	SensorType       string `json:"SensorType" db:"SensorType"`
+	// The engineering values at the low and high end of the sensor's raw
+	// range, which Slope and Intercept are computed from.
+	EngineeringLow  string `json:"EngineeringLow" db:"EngineeringLow"`
+	EngineeringHigh string `json:"EngineeringHigh" db:"EngineeringHigh"`
+
+	// Change of value and trend settings.
+	COVTrend       string `json:"COVTrend" db:"COVTrend"`
+	Collection     string `json:"Collection" db:"Collection"`
+	PanelSamples   string `json:"PanelSamples" db:"PanelSamples"`
+	PCSamples      string `json:"PCSamples" db:"PCSamples"`
+	COVLimit       string `json:"COVLimit" db:"COVLimit"`
+	TrendInterval1 string `json:"TrendInterval1" db:"TrendInterval1"`
+	Collection1    string `json:"Collection1" db:"Collection1"`
+	PanelSamples1  string `json:"PanelSamples1" db:"PanelSamples1"`
+	PCDays1        string `json:"PCDays1" db:"PCDays1"`
+	TrendInterval2 string `json:"TrendInterval2" db:"TrendInterval2"`
+	Collection2    string `json:"Collection2" db:"Collection2"`
+	PanelSamples2  string `json:"PanelSamples2" db:"PanelSamples2"`
+	PCDays2        string `json:"PCDays2" db:"PCDays2"`
+	TrendInterval3 string `json:"TrendInterval3" db:"TrendInterval3"`
+	Collection3    string `json:"Collection3" db:"Collection3"`
+	PanelSamples3  string `json:"PanelSamples3" db:"PanelSamples3"`
+	PCDays3        string `json:"PCDays3" db:"PCDays3"`
+	TrendInterval4 string `json:"TrendInterval4" db:"TrendInterval4"`
+	Collection4    string `json:"Collection4" db:"Collection4"`
+	PanelSamples4  string `json:"PanelSamples4" db:"PanelSamples4"`
+	PCDays4        string `json:"PCDays4" db:"PCDays4"`
+
+	// Equipment reference and display.
+	EquipRef    string `json:"EquipRef" db:"EquipRef"`
+	DisplayMode string `json:"DisplayMode" db:"DisplayMode"`
+
+	// Apogee building level network addressing.
+	BLNType        string `json:"BLNType" db:"BLNType"`
+	BLNSysName     string `json:"BLNSysName" db:"BLNSysName"`
+	NodeIdentifier *int   `json:"NodeIdentifier" db:"NodeIdentifier"`
+	DeviceSysName  string `json:"DeviceSysName" db:"DeviceSysName"`
+
+	// BACnet addressing.
+	BACnetObjectType     string `json:"BACnetObjectType" db:"BACnetObjectType"`
+	BACnetObjectInstance string `json:"BACnetObjectInstance" db:"BACnetObjectInstance"`
+	BACnetObjectName     string `json:"BACnetObjectName" db:"BACnetObjectName"`
+	BACnetDeviceInstance string `json:"BACnetDeviceInstance" db:"BACnetDeviceInstance"`
+	AddressTypeDHCP      string `json:"AddressTypeDHCP" db:"AddressTypeDHCP"`
+	IPaddress            string `json:"IPaddress" db:"IPaddress"`
+	UDPport              string `json:"UDPport" db:"UDPport"`
+	BACnetNetwork        *int   `json:"BACnetNetwork" db:"BACnetNetwork"`
+	MSTPaddress          string `json:"MSTPaddress" db:"MSTPaddress"`
+	MSTPnetwork          string `json:"MSTPnetwork" db:"MSTPnetwork"`
+
+	// Apogee point definition.
+	ApogeePtType      string `json:"ApogeePtType" db:"ApogeePtType"`
+	Virtual           string `json:"Virtual" db:"Virtual"`
+	IPport            string `json:"IPport" db:"IPport"`
+	InsightNodeNumber *int   `json:"InsightNodeNumber" db:"InsightNodeNumber"`
+	FLNdeviceSysName  string `json:"FLNdeviceSysName" db:"FLNdeviceSysName"`
+
+	// Project bookkeeping.
+	IncludedInProject *bool  `json:"IncludedInProject" db:"IncludedInProject"`
+	MediumId          *int   `json:"MediumId" db:"MediumId"`
+	ReportGroupId     *int   `json:"ReportGroupId" db:"ReportGroupId"`
+	Medium            string `json:"Medium" db:"Medium"`
+	ReportGroup       string `json:"ReportGroup" db:"ReportGroup"`
+	UnitId            *int   `json:"UnitId" db:"UnitId"`
+	NavigatorUnits    string `json:"NavigatorUnits" db:"NavigatorUnits"`
+	MatchingRatio     string `json:"MatchingRatio" db:"MatchingRatio"`
+	LocalRef          string `json:"LocalRef" db:"LocalRef"`
+	AppInstanceId     string `json:"AppInstanceId" db:"AppInstanceId"`
+	Memo              string `json:"Memo" db:"Memo"`
+}
+
+// dataPointFields maps a lower-cased column name onto the index of its
+// DataPoint field.
+var dataPointFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(DataPoint{})
+	for i := 0; i < t.NumField(); i++ {
+		if col := t.Field(i).Tag.Get("db"); col != "" {
+			fields[strings.ToLower(col)] = i
+		}
+	}
+	for _, c := range DataPointColumns {
+		if _, ok := fields[strings.ToLower(c.Name)]; !ok {
+			panic("models: DataPoint has no field for column " + c.Name)
+		}
+	}
+	return fields
+}()
+
+// Get returns the value of a column in the form the importer produces:
+// text as is, integers in decimal, bits as "1" or "0" and "" for NULL.
+func (dp *DataPoint) Get(column string) string {
+	i, ok := dataPointFields[strings.ToLower(column)]
+	if !ok {
+		return ""
+	}
+
+	switch v := reflect.ValueOf(dp).Elem().Field(i).Interface().(type) {
+	case string:
+		return v
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case *bool:
+		if v == nil {
+			return ""
+		}
+		if *v {
+			return "1"
+		}
+		return "0"
+	}
+	return ""
+}
+
+// Set stores a value given in the form Get returns.
+func (dp *DataPoint) Set(column, value string) error {
+	i, ok := dataPointFields[strings.ToLower(column)]
+	if !ok {
+		return fmt.Errorf("unknown datapoints column %q", column)
+	}
+
+	field := reflect.ValueOf(dp).Elem().Field(i)
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case *int:
+		if value == "" {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not an integer", column, value)
+		}
+		field.Set(reflect.ValueOf(&n))
+	case *bool:
+		if value == "" {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		b := value == "1"
+		field.Set(reflect.ValueOf(&b))
+	}
+	return nil
+}
+
+// Clone returns a copy of dp that shares none of its nullable values, so
+// that decoding into the copy leaves dp alone.
+func (dp DataPoint) Clone() DataPoint {
+	v := reflect.ValueOf(&dp).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Ptr && !f.IsNil() {
+			p := reflect.New(f.Type().Elem())
+			p.Elem().Set(f.Elem())
+			f.Set(p)
+		}
+	}
+	return dp
+}
+
+// Validate checks the datapoint against the column definitions.
+func (dp *DataPoint) Validate() error {
+	var errs []string
+	for _, c := range DataPointColumns {
+		value := dp.Get(c.Name)
+		if c.Required && strings.TrimSpace(value) == "" {
+			errs = append(errs, c.Name+" is required")
+		}
+		if c.Type == ColumnText && c.Size > 0 && utf8.RuneCountInString(value) > c.Size {
+			errs = append(errs, fmt.Sprintf("%s: value is %d characters, at most %d allowed", c.Name, utf8.RuneCountInString(value), c.Size))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// PointProblem is a value of a datapoint that could not be used.
+type PointProblem struct {
+	PointID   int    `json:"pointId"`
+	PointName string `json:"pointName"`
+	Column    string `json:"column"`
+	Message   string `json:"message"`
+}
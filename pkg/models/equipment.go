@@ -0,0 +1,43 @@
+package models
+
+// Equipment is a piece of equipment of a project, derived from the
+// datapoints sharing its EquipID.
+//
+// swagger:model
+type Equipment struct {
+	// The unique identifier of the equipment.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The project the equipment belongs to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// The EquipID of its datapoints.
+	//
+	// example: AHU-1
+	EquipID string `json:"equipId"`
+
+	// The most common EquipType of its datapoints.
+	//
+	// example: AHU
+	EquipType string `json:"equipType"`
+
+	// The most common System of its datapoints.
+	//
+	// example: HVAC
+	System string `json:"system"`
+
+	// The EquipID of the equipment feeding this one, from the EquipRef of its
+	// datapoints.
+	//
+	// example: CH-1
+	Parent string `json:"parent,omitempty"`
+
+	// The number of datapoints of the equipment.
+	//
+	// example: 12
+	Points int `json:"points"`
+}
@@ -0,0 +1,64 @@
+package models
+
+// TaggingRules holds the rules deriving the Haystack tags of a project.
+//
+// swagger:model
+type TaggingRules struct {
+	// The project the rules apply to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// The rules, applied in order. Every matching rule adds its tags.
+	Rules []TagRule `json:"rules"`
+}
+
+// TagRule adds Haystack marker tags to the datapoints whose column matches a
+// pattern. Rules on EquipType tag the equipment rather than its points.
+//
+// swagger:model
+type TagRule struct {
+	// The datapoints column the rule looks at.
+	//
+	// required: true
+	// example: Descriptor
+	Column string `json:"column"`
+
+	// A regular expression found anywhere in the value, ignoring case.
+	//
+	// required: true
+	// example: \b(discharge|supply)\b
+	Pattern string `json:"pattern"`
+
+	// The marker tags added when the pattern matches.
+	//
+	// required: true
+	// example: ["discharge"]
+	Tags []string `json:"tags"`
+}
+
+// PointTags holds the tags a user added to or removed from the derived tags
+// of a datapoint.
+//
+// swagger:model
+type PointTags struct {
+	// The datapoint the tags belong to.
+	//
+	// example: 12
+	PointID int `json:"pointId"`
+
+	// The project of the datapoint.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// Marker tags added to the derived tags.
+	//
+	// example: ["fan"]
+	Add []string `json:"add"`
+
+	// Derived marker tags removed from the point.
+	//
+	// example: ["sensor"]
+	Remove []string `json:"remove"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import "time"
+
+// Import statuses.
+const (
+	// ImportQueued means the import waits for a background worker.
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportSucceeded = "succeeded"
+	// ImportRejected means the file was refused, for example because rows
+	// failed validation, and nothing was written.
+	ImportRejected = "rejected"
+	ImportFailed   = "failed"
+)
+
+// Import modes.
+const (
+	ImportModeAppend  = "append"
+	ImportModeReplace = "replace"
+)
+
+// Import records one point list upload into a project.
+//
+// swagger:model
+type Import struct {
+	// The unique identifier of the import.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The project the file was imported into.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// The name of the file as uploaded.
+	//
+	// example: points.csv
+	FileName string `json:"fileName"`
+
+	// The name the file is kept under in the upload directory.
+	//
+	// example: 1-3861530372.csv
+	StoredName string `json:"storedName"`
+
+	// The hex encoded SHA-256 checksum of the file.
+	Checksum string `json:"checksum"`
+
+	// The size of the file in bytes.
+	//
+	// example: 20480
+	Size int64 `json:"size"`
+
+	// Who uploaded the file.
+	//
+	// example: jdoe
+	Uploader string `json:"uploader"`
+
+	// Whether the points were appended or replaced the project's point list.
+	//
+	// example: append
+	Mode string `json:"mode"`
+
+	// The options the import was started with, kept so a queued import can
+	// be resumed after a restart.
+	Options ImportOptions `json:"options"`
+
+	// The state of the import: queued, running, succeeded, rejected or failed.
+	//
+	// example: succeeded
+	Status string `json:"status"`
+
+	// The number of data rows in the file.
+	TotalRows int `json:"totalRows"`
+
+	// The number of rows written to the project.
+	ImportedRows int `json:"importedRows"`
+
+	// The number of rows that failed validation.
+	RejectedRows int `json:"rejectedRows"`
+
+	// The number of rows written so far while the import is running.
+	ProcessedRows int `json:"processedRows"`
+
+	// The errors found in the file, or why the import failed.
+	Errors []string `json:"errors"`
+
+	// Problems that did not stop rows from being imported, such as units
+	// missing from the catalogue.
+	Warnings []string `json:"warnings"`
+
+	// When the import started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// When the import finished, if it has.
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// How long the import took in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
+
+// ImportOptions are the caller's choices for an import.
+//
+// swagger:model
+type ImportOptions struct {
+	// Reject files with unknown columns.
+	Strict bool `json:"strict"`
+
+	// Import the valid rows even if some rows are rejected.
+	Partial bool `json:"partial"`
+
+	// Import trend values that cannot be read as they are, with a warning,
+	// instead of rejecting their rows.
+	LegacyTrends bool `json:"legacyTrends"`
+
+	// Request specific header to column aliases.
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// The worksheet of an XLSX file to read, by name or 1-based position.
+	Sheet string `json:"sheet,omitempty"`
+
+	// The 1-based row holding the header.
+	HeaderRow int `json:"headerRow,omitempty"`
+}
@@ -96,6 +96,32 @@ type Response struct {
 
 	// The data payload of the response.
 	Data interface{} `json:"data"`
+
+	// Paging information for list responses.
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Meta describes the page of a list returned in a Response.
+//
+// swagger:model
+type Meta struct {
+	// The number of items matching the request over all pages.
+	//
+	// example: 1250
+	Total int `json:"total"`
+
+	// The number of items in this page.
+	//
+	// example: 100
+	Count int `json:"count"`
+
+	// The page size that was applied, if any.
+	//
+	// example: 100
+	Limit int `json:"limit,omitempty"`
+
+	// The cursor to pass to fetch the next page, absent on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 type ErrorResponse struct {
 	Message string `json:"message"`
@@ -0,0 +1,43 @@
+package models
+
+// NamingConvention holds the naming rules of a project.
+//
+// swagger:model
+type NamingConvention struct {
+	// The project the rules apply to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// The template point names are generated from. Placeholders name a
+	// datapoints column, optionally with an upper or lower modifier.
+	//
+	// example: {System:upper}.{EquipType}.{EquipID}.{Function}
+	PointNameTemplate string `json:"pointNameTemplate"`
+
+	// The rules the values of the datapoints must follow.
+	Rules []NamingRule `json:"rules"`
+}
+
+// NamingRule requires the values of a datapoints column to match a pattern.
+//
+// swagger:model
+type NamingRule struct {
+	// The datapoints column the rule checks.
+	//
+	// required: true
+	// example: PointName
+	Column string `json:"column"`
+
+	// A regular expression the whole value must match. Empty values are not
+	// checked.
+	//
+	// required: true
+	// example: [A-Z]+\.[0-9]{2}\.[A-Z]{3}[0-9]{2}\.[A-Z]+
+	Pattern string `json:"pattern"`
+
+	// What the rule asks for, reported with its violations.
+	//
+	// example: Building.Floor.Equipment.Point
+	Description string `json:"description,omitempty"`
+}
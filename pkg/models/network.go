@@ -0,0 +1,127 @@
+package models
+
+// Network types.
+const (
+	NetworkBLN      = "BLN"
+	NetworkFLN      = "FLN"
+	NetworkMSTP     = "MS/TP"
+	NetworkBACnetIP = "BACnet/IP"
+)
+
+// Device kinds.
+const (
+	// DevicePanel is a field panel on a building level network.
+	DevicePanel = "panel"
+	// DeviceField is a field device on the trunk of a panel.
+	DeviceField = "field"
+)
+
+// Column sizes of the devices table. Derived values always fit: names and
+// networks come from columns of 45 characters, addresses from an IPaddress
+// and a port.
+const (
+	DeviceNameSize    = 45
+	DeviceNetworkSize = 64
+	DeviceAddressSize = 174
+)
+
+// Network is a trunk or IP network of a project, derived from the
+// addressing columns of its datapoints.
+//
+// swagger:model
+type Network struct {
+	// The unique identifier of the network.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The project the network belongs to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// BLN, FLN, MS/TP or BACnet/IP.
+	//
+	// example: BLN
+	Type string `json:"type"`
+
+	// The BLNSysName, MSTPnetwork or BACnetNetwork of the network. FLN
+	// trunks are named after their panel.
+	//
+	// example: BLN1
+	Name string `json:"name"`
+
+	// The number of devices on the network.
+	//
+	// example: 4
+	Devices int `json:"devices"`
+
+	// The number of datapoints of the devices on the network.
+	//
+	// example: 120
+	Points int `json:"points"`
+}
+
+// Device is a field panel or field device of a project. Devices are derived
+// from the DeviceSysName and FLNdeviceSysName of the datapoints, or declared
+// through the API before any point refers to them.
+//
+// swagger:model
+type Device struct {
+	// The unique identifier of the device.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The project the device belongs to.
+	//
+	// example: 1
+	ProjectID int `json:"projectId"`
+
+	// The system name of the device, unique within the project.
+	//
+	// required: true
+	// example: PXC-1
+	Name string `json:"name"`
+
+	// panel or field.
+	//
+	// required: true
+	// example: panel
+	Kind string `json:"kind"`
+
+	// The type of the network the device is on.
+	//
+	// example: BLN
+	NetworkType string `json:"networkType"`
+
+	// The name of the network the device is on.
+	//
+	// example: BLN1
+	Network string `json:"network"`
+
+	// The panel a field device is connected to.
+	//
+	// example: PXC-1
+	Parent string `json:"parent,omitempty"`
+
+	// The node number, MS/TP MAC address or IP address of the device.
+	//
+	// example: 10.0.0.21:47808
+	Address string `json:"address,omitempty"`
+
+	// The BACnet device instance.
+	//
+	// example: 2001
+	Instance *int `json:"instance"`
+
+	// Whether the device was declared through the API.
+	//
+	// example: false
+	Declared bool `json:"declared"`
+
+	// The number of datapoints of the device.
+	//
+	// example: 24
+	Points int `json:"points"`
+}
@@ -0,0 +1,62 @@
+package models
+
+// PointTemplate is the standard point set of an equipment type, stamped out
+// into projects one piece of equipment at a time.
+//
+// swagger:model
+type PointTemplate struct {
+	// The unique identifier of the template.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The unique name of the template.
+	//
+	// required: true
+	// example: VAV with reheat
+	Name string `json:"name"`
+
+	// The EquipType of the equipment stamped from the template.
+	//
+	// required: true
+	// example: VAV
+	EquipType string `json:"equipType"`
+
+	// What the template is for.
+	//
+	// example: Pressure independent VAV box with hot water reheat
+	Description string `json:"description"`
+
+	// The points of every piece of equipment.
+	//
+	// required: true
+	Points []TemplatePoint `json:"points"`
+}
+
+// TemplatePoint is a point of a template. Its values are copied into the
+// datapoints columns of the same name, its trend configuration into the trend
+// columns.
+//
+// swagger:model
+type TemplatePoint struct {
+	// Unique within the template; used to generate the point name.
+	//
+	// required: true
+	// example: ZN-T
+	Function string `json:"Function"`
+
+	// example: Zone Temperature
+	Descriptor string `json:"descriptor"`
+
+	// example: AI
+	PointType string `json:"point_type"`
+
+	// example: degF
+	EngineeringUnits string `json:"EngineeringUnits"`
+
+	// example: AI
+	BACnetObjectType string `json:"BACnetObjectType"`
+
+	// The change of value and interval trends, none when null.
+	Trend *TrendConfig `json:"trend"`
+}
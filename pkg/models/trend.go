@@ -0,0 +1,86 @@
+package models
+
+// Trend collection modes.
+const (
+	// CollectionAuto uploads the panel's samples to the PC automatically.
+	CollectionAuto = "Auto"
+	// CollectionManual keeps the samples in the panel until collected.
+	CollectionManual = "Manual"
+	// CollectionOff does not collect the samples.
+	CollectionOff = "Off"
+)
+
+// TrendConfig is the trend configuration of a datapoint. It is stored
+// flattened in the COVTrend, COVLimit, Collection, PanelSamples and PCSamples
+// columns and the four TrendInterval, Collection, PanelSamples and PCDays
+// column sets.
+//
+// swagger:model
+type TrendConfig struct {
+	// Change of value trending, null when COVTrend is off.
+	COV *COVTrend `json:"cov"`
+
+	// The interval trend definitions, at most four.
+	Trends []TrendDefinition `json:"trends"`
+}
+
+// COVTrend samples a point whenever it changes by more than its limit.
+//
+// swagger:model
+type COVTrend struct {
+	// The change that triggers a sample, in engineering units.
+	//
+	// example: 0.5
+	Limit *float64 `json:"limit"`
+
+	// Auto, Manual or Off.
+	//
+	// example: Auto
+	Collection string `json:"collection"`
+
+	// The number of samples kept in the panel.
+	//
+	// example: 500
+	PanelSamples int `json:"panelSamples"`
+
+	// The number of samples kept on the PC.
+	//
+	// example: 10000
+	PCSamples int `json:"pcSamples"`
+}
+
+// TrendDefinition samples a point at a fixed interval.
+//
+// swagger:model
+type TrendDefinition struct {
+	// The column set the definition is stored in, 1 to 4.
+	//
+	// example: 1
+	Slot int `json:"slot"`
+
+	// The sampling interval as HH:MM:SS.
+	//
+	// required: true
+	// example: 00:15:00
+	Interval string `json:"interval"`
+
+	// The sampling interval in seconds, ignored on input.
+	//
+	// example: 900
+	IntervalSeconds int `json:"intervalSeconds"`
+
+	// Auto, Manual or Off.
+	//
+	// example: Auto
+	Collection string `json:"collection"`
+
+	// The number of samples kept in the panel.
+	//
+	// example: 672
+	PanelSamples int `json:"panelSamples"`
+
+	// The number of days of samples kept on the PC.
+	//
+	// example: 365
+	PCDays int `json:"pcDays"`
+}
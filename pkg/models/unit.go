@@ -0,0 +1,44 @@
+package models
+
+// Unit is an entry of the engineering units catalogue. The ID is the value
+// of the unit in the BACnet engineering units enumeration.
+//
+// swagger:model
+type Unit struct {
+	// The BACnet engineering units value.
+	//
+	// example: 64
+	ID int `json:"id"`
+
+	// The BACnet name of the unit.
+	//
+	// example: degrees-fahrenheit
+	Name string `json:"name"`
+
+	// The usual symbol of the unit, if it has one.
+	//
+	// example: °F
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// UnitAlias maps a free-text spelling of a unit onto a catalogue entry.
+//
+// swagger:model
+type UnitAlias struct {
+	// The unique identifier of the alias.
+	//
+	// example: 1
+	ID int `json:"id"`
+
+	// The spelling, stored normalized.
+	//
+	// required: true
+	// example: degf
+	Alias string `json:"alias"`
+
+	// The unit the spelling stands for.
+	//
+	// required: true
+	// example: 64
+	UnitID int `json:"unitId"`
+}
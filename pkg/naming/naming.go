@@ -0,0 +1,169 @@
+// Package naming checks datapoints against the naming convention of their
+// project and generates point names from its template.
+//
+// Rules are regular expressions that the whole value of a column must match.
+// Templates are text with placeholders such as "{EquipID}" naming a
+// datapoints column; "{System:upper}" and "{System:lower}" change the case
+// of the value.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// ErrNoTemplate is returned when generating names without a template.
+var ErrNoTemplate = errors.New("naming: the project has no point name template")
+
+// Violation is a datapoint value breaking a rule.
+type Violation struct {
+	PointID     int    `json:"pointId"`
+	Column      string `json:"column"`
+	Value       string `json:"value"`
+	Pattern     string `json:"pattern"`
+	Description string `json:"description,omitempty"`
+}
+
+// Convention is a compiled naming convention.
+type Convention struct {
+	rules    []rule
+	template []part
+}
+
+type rule struct {
+	models.NamingRule
+	re *regexp.Regexp
+}
+
+// part is a piece of a template: literal text, or the value of column.
+type part struct {
+	text   string
+	column string
+	modify func(string) string
+}
+
+// Compile checks the rules and template of nc and prepares them for use.
+func Compile(nc models.NamingConvention) (*Convention, error) {
+	c := &Convention{}
+
+	for i, r := range nc.Rules {
+		col, ok := models.DataPointColumn(r.Column)
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unknown datapoints column %q", i+1, r.Column)
+		}
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("rule %d: pattern is required", i+1)
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		re := regexp.MustCompile("^(?:" + r.Pattern + ")$")
+		r.Column = col.Name
+		c.rules = append(c.rules, rule{NamingRule: r, re: re})
+	}
+
+	template, err := parseTemplate(nc.PointNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	c.template = template
+
+	return c, nil
+}
+
+// Check returns the rules dp breaks.
+func (c *Convention) Check(dp models.DataPoint) []Violation {
+	var violations []Violation
+	for _, r := range c.rules {
+		value := dp.Get(r.Column)
+		if value == "" || r.re.MatchString(value) {
+			continue
+		}
+		violations = append(violations, Violation{
+			PointID:     dp.ID,
+			Column:      r.Column,
+			Value:       value,
+			Pattern:     r.Pattern,
+			Description: r.Description,
+		})
+	}
+	return violations
+}
+
+// Generate builds the point name of dp from the template. Every column the
+// template uses must have a value.
+func (c *Convention) Generate(dp models.DataPoint) (string, error) {
+	if len(c.template) == 0 {
+		return "", ErrNoTemplate
+	}
+
+	var (
+		b       strings.Builder
+		missing []string
+	)
+	for _, p := range c.template {
+		if p.column == "" {
+			b.WriteString(p.text)
+			continue
+		}
+		value := strings.TrimSpace(dp.Get(p.column))
+		if value == "" {
+			missing = append(missing, p.column)
+			continue
+		}
+		if p.modify != nil {
+			value = p.modify(value)
+		}
+		b.WriteString(value)
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%s not set", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// parseTemplate splits a template into its literal text and placeholders.
+func parseTemplate(template string) ([]part, error) {
+	var parts []part
+	rest := template
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			parts = append(parts, part{text: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, part{text: rest[:open]})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("template: unclosed placeholder %q", rest[open:])
+		}
+		placeholder := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		name, modifier, _ := strings.Cut(placeholder, ":")
+		col, ok := models.DataPointColumn(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("template: unknown datapoints column %q", name)
+		}
+		p := part{column: col.Name}
+		switch strings.ToLower(strings.TrimSpace(modifier)) {
+		case "":
+		case "upper":
+			p.modify = strings.ToUpper
+		case "lower":
+			p.modify = strings.ToLower
+		default:
+			return nil, fmt.Errorf("template: unknown modifier %q, use upper or lower", modifier)
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
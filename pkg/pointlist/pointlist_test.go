@@ -0,0 +1,186 @@
+package pointlist
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// workbook returns an XLSX file with a title sheet ahead of the point list,
+// which has its header on row 3 and a trailing empty cell on row 4.
+func workbook(t *testing.T) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", "Title"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellValue("Title", "A1", "Project 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.NewSheet(SheetName); err != nil {
+		t.Fatal(err)
+	}
+	rows := map[string][]interface{}{
+		"A1": {"Point schedule"},
+		"A3": {"EquipID", "PointName", "Descriptor"},
+		"A4": {"AHU-1", "SAT"},
+		"A5": {"AHU-1", "007", "Zone 7"},
+	}
+	for cell, row := range rows {
+		if err := f.SetSheetRow(SheetName, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b bytes.Buffer
+	if _, err := f.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	xlsx := workbook(t)
+	points := [][]string{
+		{"EquipID", "PointName", "Descriptor"},
+		{"AHU-1", "SAT", ""},
+		{"AHU-1", "007", "Zone 7"},
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		data    []byte
+		opts    ReadOptions
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "csv",
+			file: "points.csv",
+			data: []byte("EquipID,PointName\nAHU-1,SAT\nAHU-1\n"),
+			want: [][]string{{"EquipID", "PointName"}, {"AHU-1", "SAT"}, {"AHU-1"}},
+		},
+		{
+			name: "csv header row",
+			file: "points.csv",
+			data: []byte("Point schedule\n\nEquipID,PointName\nAHU-1,SAT\n"),
+			opts: ReadOptions{HeaderRow: 2},
+			want: [][]string{{"EquipID", "PointName"}, {"AHU-1", "SAT"}},
+		},
+		{
+			name:    "csv header row past the end",
+			file:    "points.csv",
+			data:    []byte("EquipID\nAHU-1\n"),
+			opts:    ReadOptions{HeaderRow: 3},
+			wantErr: true,
+		},
+		{
+			name: "xlsx first sheet",
+			file: "points.xlsx",
+			data: xlsx,
+			want: [][]string{{"Project 1"}},
+		},
+		{
+			name: "xlsx sheet by name",
+			file: "points.xlsx",
+			data: xlsx,
+			opts: ReadOptions{Sheet: "points", HeaderRow: 3},
+			want: points,
+		},
+		{
+			name: "xlsx sheet by position",
+			file: "points.xlsx",
+			data: xlsx,
+			opts: ReadOptions{Sheet: "2", HeaderRow: 3},
+			want: points,
+		},
+		{
+			name: "xlsx uploaded as csv",
+			file: "points.csv",
+			data: xlsx,
+			opts: ReadOptions{Sheet: "Points", HeaderRow: 3},
+			want: points,
+		},
+		{
+			name:    "xlsx unknown sheet",
+			file:    "points.xlsx",
+			data:    xlsx,
+			opts:    ReadOptions{Sheet: "3"},
+			wantErr: true,
+		},
+		{
+			name:    "xlsx header row past the end",
+			file:    "points.xlsx",
+			data:    xlsx,
+			opts:    ReadOptions{Sheet: "Points", HeaderRow: 6},
+			wantErr: true,
+		},
+		{
+			name:    "legacy xls",
+			file:    "points.XLS",
+			data:    []byte{0xd0, 0xcf, 0x11, 0xe0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data), tt.file, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Read() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	records := [][]string{
+		{"EquipID", "PointName", "BACnetObjectInstance"},
+		{"AHU-1", "SAT", "0001"},
+		{"AHU-1", "RAT, zone", ""},
+	}
+
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			w, err := NewWriter(&b, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, record := range records {
+				if err := w.Write(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Read(&b, "points."+format, ReadOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, records) {
+				t.Errorf("read back %q, want %q", got, records)
+			}
+		})
+	}
+
+	if _, err := NewWriter(&bytes.Buffer{}, "xls"); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("NewWriter(xls) error = %v, want unsupported format", err)
+	}
+}
@@ -0,0 +1,142 @@
+package pointlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// zipMagic starts every XLSX file.
+var zipMagic = []byte("PK\x03\x04")
+
+// ReadOptions controls which part of a file holds the point list.
+type ReadOptions struct {
+	// Sheet is the XLSX worksheet to read, by name or 1-based position.
+	// The first sheet is read when empty.
+	Sheet string
+
+	// HeaderRow is the 1-based row holding the header; rows above it are
+	// skipped. Defaults to 1.
+	HeaderRow int
+}
+
+// Read returns the records of a point list starting at the header row. The
+// format is taken from the content, falling back to the file name, so an
+// XLSX file uploaded as .csv is still read correctly.
+func Read(r io.Reader, name string, opts ReadOptions) ([][]string, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(zipMagic))
+
+	switch {
+	case bytes.Equal(head, zipMagic):
+		return ReadXLSX(br, opts)
+	case strings.EqualFold(filepath.Ext(name), ".xls"):
+		return nil, fmt.Errorf("pointlist: legacy .xls workbooks are not supported, save the file as .xlsx or .csv")
+	}
+	return ReadCSV(br, opts)
+}
+
+// ReadCSV reads a CSV point list. Rows may have differing numbers of fields;
+// the importer reports them.
+func ReadCSV(r io.Reader, opts ReadOptions) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	skip := opts.HeaderRow - 1
+	if skip < 0 {
+		skip = 0
+	}
+	if skip >= len(records) {
+		return nil, fmt.Errorf("pointlist: header row %d is past the end of the file", opts.HeaderRow)
+	}
+	return records[skip:], nil
+}
+
+// ReadXLSX reads a point list from a worksheet. Cells are read as displayed
+// in Excel, so text keeps its leading zeros. Rows are padded to the width of
+// the header since Excel does not store trailing empty cells.
+func ReadXLSX(r io.Reader, opts ReadOptions) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("pointlist: reading workbook: %w", err)
+	}
+	defer f.Close()
+
+	sheet, err := findSheet(f, opts.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	headerRow := opts.HeaderRow
+	if headerRow < 1 {
+		headerRow = 1
+	}
+
+	var (
+		records [][]string
+		width   int
+	)
+	for n := 1; rows.Next(); n++ {
+		if n < headerRow {
+			continue
+		}
+		record, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if n == headerRow {
+			width = len(record)
+		}
+		for len(record) < width {
+			record = append(record, "")
+		}
+		records = append(records, record)
+	}
+	if err := rows.Error(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("pointlist: header row %d is past the end of sheet %q", headerRow, sheet)
+	}
+	return records, nil
+}
+
+// findSheet resolves a sheet given by name or 1-based position.
+func findSheet(f *excelize.File, sheet string) (string, error) {
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return "", fmt.Errorf("pointlist: workbook has no sheets")
+	}
+	if sheet == "" {
+		return sheets[0], nil
+	}
+
+	for _, name := range sheets {
+		if strings.EqualFold(name, sheet) {
+			return name, nil
+		}
+	}
+	if n, err := strconv.Atoi(sheet); err == nil && n >= 1 && n <= len(sheets) {
+		return sheets[n-1], nil
+	}
+
+	return "", fmt.Errorf("pointlist: workbook has no sheet %q, it has %s", sheet, strings.Join(sheets, ", "))
+}
@@ -0,0 +1,106 @@
+// Package pointlist reads and writes point list files. Rows are plain string
+// records, the first one being the header, so the same files go through the
+// importer's column mapping whichever format they are in.
+package pointlist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats supported by NewWriter.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// SheetName is the name of the worksheet XLSX point lists are written to.
+const SheetName = "Points"
+
+// Writer writes the records of a point list.
+type Writer interface {
+	Write(record []string) error
+	// Close flushes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter returns a Writer for the given format writing to w.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch strings.ToLower(format) {
+	case "", FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("pointlist: unsupported format %q", format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvWriter) Write(record []string) error {
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// xlsxWriter streams rows into a single worksheet. Every cell is written as
+// text so leading zeros and instance numbers survive a round trip.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(out io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", SheetName); err != nil {
+		return nil, err
+	}
+	stream, err := f.NewStreamWriter(SheetName)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: f, stream: stream}, nil
+}
+
+func (w *xlsxWriter) Write(record []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(record))
+	for i, v := range record {
+		values[i] = v
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.out)
+	return err
+}
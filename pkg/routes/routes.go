@@ -1,7 +1,6 @@
 package routers
 
 import (
-	"log"
 	"net/http"
 
 	// Import the generated Swagger docs
@@ -13,13 +12,13 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-// SetRoutes sets up the routing for the API
-func SetRoutes() {
+// NewRouter sets up the routing for the API
+func NewRouter(c *controller.Controller) http.Handler {
 	r := chi.NewRouter()
 
 	cors := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"X-PINGOTHER","Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -28,24 +27,128 @@ func SetRoutes() {
 	r.Use(cors.Handler) 
 
 	
-	r.Get("/api/projects", controller.GetProject)
+	r.Get("/api/projects", c.GetProject)
 
 	
-	r.Post("/api/projects", controller.InsertProject)
+	r.Post("/api/projects", c.InsertProject)
 
 	
-	r.Get("/api/projects/{id}", controller.GetProjectByID)
+	r.Get("/api/projects/{id}", c.GetProjectByID)
 
 	
-	r.Put("/api/projects/{id}", controller.UpdateProject)
+	r.Put("/api/projects/{id}", c.UpdateProject)
 
 
-	r.Delete("/api/projects/{id}", controller.DeleteProject)
+	r.Delete("/api/projects/{id}", c.DeleteProject)
 
 	
-	r.Post("/api/upload/{id}", controller.UploadHandler)
+	r.Post("/api/upload/{id}", c.UploadHandler)
 
-	r.Get("/api/data/{id}",controller.GetData)
+	r.Get("/api/data/{id}",c.GetData)
+
+	r.Get("/api/projects/{id}/datapoints", c.GetData)
+
+	r.Post("/api/projects/{id}/datapoints", c.CreateDataPoint)
+
+	r.Get("/api/projects/{id}/datapoints/export", c.ExportDataPoints)
+
+	r.Get("/api/projects/{id}/datapoints/scaling", c.CheckScaling)
+
+	r.Post("/api/projects/{id}/datapoints/scaling", c.ScaleDataPoints)
+
+	r.Get("/api/projects/{id}/datapoints/{pointId}", c.GetDataPoint)
+
+	r.Put("/api/projects/{id}/datapoints/{pointId}", c.UpdateDataPoint)
+
+	r.Patch("/api/projects/{id}/datapoints/{pointId}", c.PatchDataPoint)
+
+	r.Delete("/api/projects/{id}/datapoints/{pointId}", c.DeleteDataPoint)
+
+	r.Get("/api/projects/{id}/conflicts", c.GetConflicts)
+
+	r.Get("/api/projects/{id}/diff/{otherId}", c.DiffProjects)
+
+	r.Post("/api/projects/{id}/diff", c.DiffFile)
+
+	r.Get("/api/projects/{id}/equipment", c.GetEquipment)
+
+	r.Get("/api/projects/{id}/equipment/{equipId}", c.GetEquipmentByID)
+
+	r.Get("/api/projects/{id}/equipment/{equipId}/points", c.GetEquipmentPoints)
+
+	r.Get("/api/projects/{id}/naming", c.GetNamingConvention)
+
+	r.Put("/api/projects/{id}/naming", c.UpdateNamingConvention)
+
+	r.Get("/api/projects/{id}/naming/violations", c.GetNamingViolations)
+
+	r.Post("/api/projects/{id}/naming/generate", c.GenerateNames)
+
+	r.Get("/api/projects/{id}/datapoints/{pointId}/tags", c.GetPointTags)
+
+	r.Put("/api/projects/{id}/datapoints/{pointId}/tags", c.UpdatePointTags)
+
+	r.Get("/api/projects/{id}/datapoints/{pointId}/trends", c.GetPointTrends)
+
+	r.Put("/api/projects/{id}/datapoints/{pointId}/trends", c.UpdatePointTrends)
+
+	r.Get("/api/projects/{id}/trends", c.GetTrendSummary)
+
+	r.Get("/api/projects/{id}/capacity", c.GetCapacity)
+
+	r.Get("/api/projects/{id}/networks", c.GetNetworks)
+
+	r.Get("/api/projects/{id}/devices", c.GetDevices)
+
+	r.Post("/api/projects/{id}/devices", c.CreateDevice)
+
+	r.Delete("/api/projects/{id}/devices/{deviceId}", c.DeleteDevice)
+
+	r.Get("/api/projects/{id}/topology", c.GetTopology)
+
+	r.Post("/api/projects/{id}/topology/sync", c.SyncTopology)
+
+	r.Get("/api/projects/{id}/haystack/rules", c.GetTaggingRules)
+
+	r.Put("/api/projects/{id}/haystack/rules", c.UpdateTaggingRules)
+
+	r.Get("/api/projects/{id}/haystack/tags", c.GetProjectTags)
+
+	r.Get("/api/projects/{id}/export/haystack", c.ExportHaystack)
+
+	r.Get("/api/projects/{id}/export/brick", c.ExportBrick)
+
+	r.Get("/api/projects/{id}/export/apogee", c.ExportApogee)
+
+	r.Get("/api/projects/{id}/export/apogee/report", c.GetApogeeReport)
+
+	r.Post("/api/projects/{id}/templates/{templateId}/stamp", c.StampTemplate)
+
+	r.Get("/api/projects/{id}/imports", c.GetProjectImports)
+
+	r.Get("/api/imports/{importId}", c.GetImport)
+
+	r.Get("/api/imports/{importId}/file", c.DownloadImport)
+
+	r.Get("/api/units", c.GetUnits)
+
+	r.Get("/api/units/aliases", c.GetUnitAliases)
+
+	r.Post("/api/units/aliases", c.CreateUnitAlias)
+
+	r.Delete("/api/units/aliases/{aliasId}", c.DeleteUnitAlias)
+
+	r.Get("/api/sensors", c.GetSensors)
+
+	r.Get("/api/templates", c.GetTemplates)
+
+	r.Post("/api/templates", c.CreateTemplate)
+
+	r.Get("/api/templates/{templateId}", c.GetTemplate)
+
+	r.Put("/api/templates/{templateId}", c.UpdateTemplate)
+
+	r.Delete("/api/templates/{templateId}", c.DeleteTemplate)
 
 	// Swagger UI route
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -53,8 +156,5 @@ func SetRoutes() {
 	))
 
 
-	// Start the HTTP server
-	port := ":8080"
-	log.Printf("Server listening on port %s", port)
-	log.Fatal(http.ListenAndServe(port, r))
+	return r
 }
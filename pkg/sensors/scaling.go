@@ -0,0 +1,157 @@
+package sensors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// DefaultTolerance is how far a stored slope may be off, relative to the
+// computed one, and a stored intercept, relative to the engineering span,
+// before Check reports it.
+const DefaultTolerance = 0.001
+
+// Scaling is the Slope and Intercept of a datapoint.
+type Scaling struct {
+	Slope     float64
+	Intercept float64
+}
+
+// Scale returns the scaling that maps the raw range of the sensor onto the
+// engineering range low to high. A high below low scales reverse acting.
+func (s Sensor) Scale(low, high float64) (Scaling, error) {
+	if low == high {
+		return Scaling{}, errors.New("the engineering range is empty")
+	}
+	slope := (high - low) / (s.RawHigh - s.RawLow)
+	return Scaling{Slope: slope, Intercept: low - slope*s.RawLow}, nil
+}
+
+// Compute returns the scaling of dp from its SensorType, EngineeringLow and
+// EngineeringHigh. The problem is set when they do not tell.
+func Compute(dp models.DataPoint) (Sensor, Scaling, *models.PointProblem) {
+	problem := func(column, format string, args ...interface{}) *models.PointProblem {
+		return &models.PointProblem{PointID: dp.ID, PointName: dp.PointName, Column: column, Message: fmt.Sprintf(format, args...)}
+	}
+
+	name := strings.TrimSpace(dp.SensorType)
+	if name == "" {
+		return Sensor{}, Scaling{}, problem("SensorType", "no sensor type")
+	}
+	sensor, ok := Lookup(name)
+	if !ok {
+		return Sensor{}, Scaling{}, problem("SensorType", "unknown sensor type %q", name)
+	}
+
+	low, err := parse(dp.EngineeringLow)
+	if err != nil {
+		return sensor, Scaling{}, problem("EngineeringLow", "%v", err)
+	}
+	high, err := parse(dp.EngineeringHigh)
+	if err != nil {
+		return sensor, Scaling{}, problem("EngineeringHigh", "%v", err)
+	}
+
+	scaling, err := sensor.Scale(low, high)
+	if err != nil {
+		return sensor, Scaling{}, problem("EngineeringHigh", "%v", err)
+	}
+	return sensor, scaling, nil
+}
+
+// Apply writes the scaling to the Slope and Intercept of dp.
+func Apply(dp *models.DataPoint, s Scaling) {
+	dp.Slope = Format(s.Slope)
+	dp.Intercept = Format(s.Intercept)
+}
+
+// Format writes a slope or intercept without the noise of float arithmetic.
+func Format(f float64) string {
+	if f == 0 {
+		// Never -0
+		return "0"
+	}
+	return strconv.FormatFloat(f, 'g', 10, 64)
+}
+
+// Mismatch is a datapoint whose stored scaling disagrees with the computed
+// one.
+type Mismatch struct {
+	PointID           int    `json:"pointId"`
+	PointName         string `json:"pointName"`
+	SensorType        string `json:"sensorType"`
+	EngineeringLow    string `json:"engineeringLow"`
+	EngineeringHigh   string `json:"engineeringHigh"`
+	Slope             string `json:"slope"`
+	Intercept         string `json:"intercept"`
+	ExpectedSlope     string `json:"expectedSlope"`
+	ExpectedIntercept string `json:"expectedIntercept"`
+}
+
+// Report is the outcome of Check.
+type Report struct {
+	// Checked is the number of points whose scaling could be computed.
+	Checked    int        `json:"checked"`
+	Mismatches []Mismatch `json:"mismatches"`
+	// Unchecked are the points with a sensor type or engineering range
+	// whose scaling cannot be computed.
+	Unchecked []models.PointProblem `json:"unchecked"`
+}
+
+// Check compares the stored Slope and Intercept of points with the computed
+// ones. Points without a sensor type or engineering range are left out.
+func Check(points []models.DataPoint, tolerance float64) Report {
+	report := Report{Mismatches: []Mismatch{}, Unchecked: []models.PointProblem{}}
+	for _, dp := range points {
+		if strings.TrimSpace(dp.SensorType+dp.EngineeringLow+dp.EngineeringHigh) == "" {
+			continue
+		}
+		_, scaling, problem := Compute(dp)
+		if problem != nil {
+			report.Unchecked = append(report.Unchecked, *problem)
+			continue
+		}
+		report.Checked++
+
+		// Compute succeeded, so the range parses
+		low, _ := parse(dp.EngineeringLow)
+		high, _ := parse(dp.EngineeringHigh)
+		slope, slopeErr := strconv.ParseFloat(strings.TrimSpace(dp.Slope), 64)
+		intercept, interceptErr := strconv.ParseFloat(strings.TrimSpace(dp.Intercept), 64)
+		if slopeErr == nil && interceptErr == nil &&
+			math.Abs(slope-scaling.Slope) <= tolerance*math.Abs(scaling.Slope) &&
+			math.Abs(intercept-scaling.Intercept) <= tolerance*math.Abs(high-low) {
+			continue
+		}
+
+		report.Mismatches = append(report.Mismatches, Mismatch{
+			PointID:           dp.ID,
+			PointName:         dp.PointName,
+			SensorType:        dp.SensorType,
+			EngineeringLow:    dp.EngineeringLow,
+			EngineeringHigh:   dp.EngineeringHigh,
+			Slope:             dp.Slope,
+			Intercept:         dp.Intercept,
+			ExpectedSlope:     Format(scaling.Slope),
+			ExpectedIntercept: Format(scaling.Intercept),
+		})
+	}
+	return report
+}
+
+// parse reads an end of the engineering range.
+func parse(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("no engineering range")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return f, nil
+}
@@ -0,0 +1,61 @@
+// Package sensors holds the catalogue of sensor types and computes the Slope
+// and Intercept that scale the raw range of a sensor onto the engineering
+// range of a datapoint:
+//
+//	value = Slope * raw + Intercept
+//
+// The engineering range is kept in the EngineeringLow and EngineeringHigh
+// columns. Temperature sensors are linearized by the panel, so their raw
+// range is the temperature range they report, in degrees Fahrenheit.
+package sensors
+
+import "github.com/pufington-pixie/haver/pkg/models"
+
+// Sensor is a sensor type with the range of its raw value.
+type Sensor struct {
+	// Name is the canonical spelling, written to SensorType.
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Signal is the unit of the raw range.
+	Signal  string  `json:"signal"`
+	RawLow  float64 `json:"rawLow"`
+	RawHigh float64 `json:"rawHigh"`
+	// Aliases are other spellings of the type seen in point lists.
+	Aliases []string `json:"aliases"`
+	// Apogee is the sensor type of Apogee point definitions.
+	Apogee string `json:"apogee"`
+}
+
+// Catalogue lists the sensor types the scaling is known for.
+var Catalogue = []Sensor{
+	{Name: "4-20mA", Description: "Current loop", Signal: "mA", RawLow: 4, RawHigh: 20, Aliases: []string{"4-20 mA", "mA", "Current", "I"}, Apogee: "I"},
+	{Name: "0-20mA", Description: "Current loop without live zero", Signal: "mA", RawLow: 0, RawHigh: 20, Apogee: "I"},
+	{Name: "0-10V", Description: "Voltage", Signal: "V", RawLow: 0, RawHigh: 10, Aliases: []string{"0-10 VDC", "0-10VDC", "Voltage", "V"}, Apogee: "V"},
+	{Name: "2-10V", Description: "Voltage with live zero", Signal: "V", RawLow: 2, RawHigh: 10, Aliases: []string{"2-10 VDC", "2-10VDC"}, Apogee: "V"},
+	{Name: "0-5V", Description: "Voltage", Signal: "V", RawLow: 0, RawHigh: 5, Aliases: []string{"0-5 VDC", "0-5VDC"}, Apogee: "V"},
+	{Name: "1-5V", Description: "Voltage with live zero", Signal: "V", RawLow: 1, RawHigh: 5, Aliases: []string{"1-5 VDC", "1-5VDC"}, Apogee: "V"},
+	{Name: "Pneumatic", Description: "Pneumatic transducer", Signal: "psi", RawLow: 3, RawHigh: 15, Aliases: []string{"3-15 psi", "P"}, Apogee: "P"},
+	{Name: "1K Ni RTD", Description: "1000 ohm nickel RTD", Signal: "°F", RawLow: -40, RawHigh: 250, Aliases: []string{"1K Ni", "Nickel", "Ni1000"}, Apogee: "1K NI"},
+	{Name: "1K Pt RTD", Description: "1000 ohm platinum RTD", Signal: "°F", RawLow: -40, RawHigh: 250, Aliases: []string{"1K Pt", "Platinum", "Pt1000"}, Apogee: "1K PT"},
+	{Name: "10K Thermistor", Description: "10K ohm type II thermistor", Signal: "°F", RawLow: -40, RawHigh: 230, Aliases: []string{"10K", "10K T", "10K Type II", "Thermistor"}, Apogee: "10K T"},
+	{Name: "10K Thermistor Type III", Description: "10K ohm type III thermistor", Signal: "°F", RawLow: -40, RawHigh: 230, Aliases: []string{"10K Type III", "10K T3"}, Apogee: "10K T3"},
+	{Name: "100K Thermistor", Description: "100K ohm thermistor", Signal: "°F", RawLow: -40, RawHigh: 300, Aliases: []string{"100K", "100K T"}, Apogee: "100K T"},
+}
+
+// byKey looks sensors up by normalized name and alias.
+var byKey = func() map[string]Sensor {
+	m := make(map[string]Sensor)
+	for _, s := range Catalogue {
+		m[models.Normalize(s.Name)] = s
+		for _, a := range s.Aliases {
+			m[models.Normalize(a)] = s
+		}
+	}
+	return m
+}()
+
+// Lookup returns the sensor type spelled s.
+func Lookup(s string) (Sensor, bool) {
+	sensor, ok := byKey[models.Normalize(s)]
+	return sensor, ok
+}
@@ -0,0 +1,170 @@
+package sensors
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		apogee string
+	}{
+		{in: "4-20mA", want: "4-20mA", apogee: "I"},
+		{in: "4-20 MA", want: "4-20mA", apogee: "I"},
+		{in: "current", want: "4-20mA", apogee: "I"},
+		{in: "0-5 VDC", want: "0-5V", apogee: "V"},
+		{in: "1-5V", want: "1-5V", apogee: "V"},
+		{in: "1k_ni", want: "1K Ni RTD", apogee: "1K NI"},
+		{in: "10K T", want: "10K Thermistor", apogee: "10K T"},
+		{in: "10K Type III", want: "10K Thermistor Type III", apogee: "10K T3"},
+		{in: "100K T", want: "100K Thermistor", apogee: "100K T"},
+		{in: "strain gauge"},
+		{in: ""},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(tt.in)
+		if ok != (tt.want != "") || got.Name != tt.want || got.Apogee != tt.apogee {
+			t.Errorf("Lookup(%q) = %q (%q), %t, want %q (%q)", tt.in, got.Name, got.Apogee, ok, tt.want, tt.apogee)
+		}
+	}
+
+	// Every spelling names one sensor and every sensor exports to Apogee
+	seen := make(map[string]string)
+	for _, s := range Catalogue {
+		if s.Apogee == "" {
+			t.Errorf("%s has no Apogee sensor type", s.Name)
+		}
+		for _, spelling := range append([]string{s.Name}, s.Aliases...) {
+			key := models.Normalize(spelling)
+			if other, ok := seen[key]; ok && other != s.Name {
+				t.Errorf("%q of %s is also a spelling of %s", spelling, s.Name, other)
+			}
+			seen[key] = s.Name
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name    string
+		dp      models.DataPoint
+		want    Scaling
+		problem *models.PointProblem
+	}{
+		{
+			name: "current loop",
+			dp:   models.DataPoint{SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100"},
+			want: Scaling{Slope: 6.25, Intercept: -25},
+		},
+		{
+			name: "reverse acting",
+			dp:   models.DataPoint{SensorType: "0-10V", EngineeringLow: "100", EngineeringHigh: "0"},
+			want: Scaling{Slope: -10, Intercept: 100},
+		},
+		{
+			name: "temperature",
+			dp:   models.DataPoint{SensorType: "10K", EngineeringLow: "-40", EngineeringHigh: "230"},
+			want: Scaling{Slope: 1},
+		},
+		{
+			name:    "no sensor type",
+			dp:      models.DataPoint{ID: 1, PointName: "SAT", EngineeringLow: "0", EngineeringHigh: "100"},
+			problem: &models.PointProblem{PointID: 1, PointName: "SAT", Column: "SensorType", Message: "no sensor type"},
+		},
+		{
+			name:    "unknown sensor type",
+			dp:      models.DataPoint{ID: 1, SensorType: "strain gauge"},
+			problem: &models.PointProblem{PointID: 1, Column: "SensorType", Message: `unknown sensor type "strain gauge"`},
+		},
+		{
+			name:    "no range",
+			dp:      models.DataPoint{ID: 1, SensorType: "4-20mA", EngineeringHigh: "100"},
+			problem: &models.PointProblem{PointID: 1, Column: "EngineeringLow", Message: "no engineering range"},
+		},
+		{
+			name:    "unreadable range",
+			dp:      models.DataPoint{ID: 1, SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "lots"},
+			problem: &models.PointProblem{PointID: 1, Column: "EngineeringHigh", Message: `"lots" is not a number`},
+		},
+		{
+			name:    "empty range",
+			dp:      models.DataPoint{ID: 1, SensorType: "4-20mA", EngineeringLow: "5", EngineeringHigh: "5"},
+			problem: &models.PointProblem{PointID: 1, Column: "EngineeringHigh", Message: "the engineering range is empty"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, problem := Compute(tt.dp)
+			if !reflect.DeepEqual(problem, tt.problem) {
+				t.Fatalf("Compute() problem = %+v, want %+v", problem, tt.problem)
+			}
+			if got != tt.want {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{math.Copysign(0, -1), "0"},
+		{6.25, "6.25"},
+		{0.1 + 0.2, "0.3"},
+		{-25, "-25"},
+		{1e-7, "1e-07"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	points := []models.DataPoint{
+		{ID: 1, SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100", Slope: "6.25", Intercept: "-25"},
+		// Off by less than the tolerance
+		{ID: 2, SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100", Slope: "6.2501", Intercept: "-25.01"},
+		{ID: 3, PointName: "RAT", SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100", Slope: "1", Intercept: "0"},
+		{ID: 4, SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100"},
+		{ID: 5, SensorType: "strain gauge"},
+		// Not scaled by sensor type
+		{ID: 6, Slope: "2", Intercept: "1"},
+	}
+
+	report := Check(points, DefaultTolerance)
+
+	if report.Checked != 4 {
+		t.Errorf("Checked = %d, want 4", report.Checked)
+	}
+	want := []Mismatch{
+		{
+			PointID: 3, PointName: "RAT", SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100",
+			Slope: "1", Intercept: "0", ExpectedSlope: "6.25", ExpectedIntercept: "-25",
+		},
+		{
+			PointID: 4, SensorType: "4-20mA", EngineeringLow: "0", EngineeringHigh: "100",
+			ExpectedSlope: "6.25", ExpectedIntercept: "-25",
+		},
+	}
+	if !reflect.DeepEqual(report.Mismatches, want) {
+		t.Errorf("Mismatches = %+v, want %+v", report.Mismatches, want)
+	}
+	if len(report.Unchecked) != 1 || report.Unchecked[0].PointID != 5 {
+		t.Errorf("Unchecked = %+v, want point 5", report.Unchecked)
+	}
+
+	// Without tolerance the point off by a little is a mismatch too
+	if got := Check(points, 0); len(got.Mismatches) != 3 {
+		t.Errorf("Check(0) = %d mismatches, want 3", len(got.Mismatches))
+	}
+}
@@ -0,0 +1,287 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type dataPointStore struct {
+	*db
+}
+
+func (s *dataPointStore) ListByProject(ctx context.Context, projectID int) ([]models.DataPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data := []models.DataPoint{}
+	for _, dp := range s.dataPoints {
+		if dp.ProjectID == projectID {
+			data = append(data, copyDataPoint(dp))
+		}
+	}
+
+	return data, nil
+}
+
+func (s *dataPointStore) Query(ctx context.Context, projectID int, q store.DataPointQuery) ([]models.DataPoint, int, error) {
+	for name := range q.Equals {
+		if _, ok := models.DataPointColumn(name); !ok {
+			return nil, 0, fmt.Errorf("memstore: unknown datapoints column %q", name)
+		}
+	}
+	sortCol, hasSort := models.DataPointColumn(q.Sort)
+	if q.Sort != "" && !hasSort && !strings.EqualFold(q.Sort, "Id") {
+		return nil, 0, fmt.Errorf("memstore: unknown datapoints column %q", q.Sort)
+	}
+
+	all, err := s.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	search := strings.ToLower(q.Search)
+	matched := []models.DataPoint{}
+	for _, dp := range all {
+		if matches(dp, q.Equals, search) {
+			matched = append(matched, dp)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if q.Desc {
+			a, b = b, a
+		}
+		if hasSort {
+			if c := compareColumn(sortCol, a.Get(sortCol.Name), b.Get(sortCol.Name)); c != 0 {
+				return c < 0
+			}
+		}
+		return a.ID < b.ID
+	})
+
+	total := len(matched)
+	if q.Offset >= len(matched) {
+		return []models.DataPoint{}, total, nil
+	}
+	matched = matched[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(matched) {
+		matched = matched[:q.Limit]
+	}
+
+	return matched, total, nil
+}
+
+func matches(dp models.DataPoint, equals map[string]string, search string) bool {
+	for name, value := range equals {
+		if !strings.EqualFold(dp.Get(name), value) {
+			return false
+		}
+	}
+
+	if search != "" &&
+		!strings.Contains(strings.ToLower(dp.PointName), search) &&
+		!strings.Contains(strings.ToLower(dp.Descriptor), search) {
+		return false
+	}
+
+	return true
+}
+
+// compareColumn orders two values of a column like MySQL does: NULLs
+// first, integers numerically and text ignoring case.
+func compareColumn(c models.Column, a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+
+	if c.Type != models.ColumnText {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func (s *dataPointStore) Get(ctx context.Context, projectID, id int) (models.DataPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.findPoint(projectID, id)
+	if i < 0 {
+		return models.DataPoint{}, store.ErrNotFound
+	}
+
+	return copyDataPoint(s.dataPoints[i]), nil
+}
+
+func (s *dataPointStore) Create(ctx context.Context, dp *models.DataPoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[dp.ProjectID]; !ok {
+		return store.ErrNotFound
+	}
+
+	dp.ID = s.nextPointID
+	s.nextPointID++
+	s.dataPoints = append(s.dataPoints, copyDataPoint(*dp))
+
+	return nil
+}
+
+func (s *dataPointStore) Update(ctx context.Context, dp models.DataPoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.findPoint(dp.ProjectID, dp.ID)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	s.dataPoints[i] = copyDataPoint(dp)
+
+	return nil
+}
+
+func (s *dataPointStore) UpdateMany(ctx context.Context, points []models.DataPoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Look every point up before changing any
+	indexes := make([]int, len(points))
+	for n, dp := range points {
+		indexes[n] = s.findPoint(dp.ProjectID, dp.ID)
+		if indexes[n] < 0 {
+			return store.ErrNotFound
+		}
+	}
+	for n, dp := range points {
+		s.dataPoints[indexes[n]] = copyDataPoint(dp)
+	}
+
+	return nil
+}
+
+func (s *dataPointStore) Delete(ctx context.Context, projectID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.findPoint(projectID, id)
+	if i < 0 {
+		return store.ErrNotFound
+	}
+	s.dataPoints = append(s.dataPoints[:i], s.dataPoints[i+1:]...)
+	delete(s.pointTags, id)
+
+	return nil
+}
+
+func (s *dataPointStore) Import(ctx context.Context, projectID int, columns []string, rows [][]string, opts store.ImportOptions) error {
+	if err := s.importRows(projectID, columns, rows, opts.Mode); err != nil {
+		return err
+	}
+
+	// Report progress without holding the lock, the callback may well use
+	// the store itself.
+	if opts.Progress != nil {
+		opts.Progress(len(rows))
+	}
+
+	return nil
+}
+
+func (s *dataPointStore) importRows(projectID int, columns []string, rows [][]string, mode store.ImportMode) error {
+	// Build every point before touching the table so a bad row leaves it
+	// unchanged, like the rolled back transaction in sqlstore.
+	points := make([]models.DataPoint, 0, len(rows))
+	for _, row := range rows {
+		// Columns missing from the file keep their table default.
+		included := true
+		dp := models.DataPoint{ProjectID: projectID, IncludedInProject: &included}
+		for i, c := range columns {
+			if _, ok := models.DataPointColumn(c); !ok {
+				return fmt.Errorf("memstore: unknown datapoints column %q", c)
+			}
+			if i < len(row) {
+				if err := dp.Set(c, row[i]); err != nil {
+					return err
+				}
+			}
+		}
+		points = append(points, dp)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[projectID]; !ok {
+		return store.ErrNotFound
+	}
+
+	if mode == store.ImportReplace {
+		s.deleteProjectPoints(projectID)
+	}
+
+	for _, dp := range points {
+		dp.ID = s.nextPointID
+		s.nextPointID++
+		s.dataPoints = append(s.dataPoints, dp)
+	}
+
+	return nil
+}
+
+// findPoint returns the index of a datapoint or -1. The caller must hold the
+// lock.
+func (d *db) findPoint(projectID, id int) int {
+	for i, dp := range d.dataPoints {
+		if dp.ID == id && dp.ProjectID == projectID {
+			return i
+		}
+	}
+	return -1
+}
+
+// deleteProjectPoints removes the datapoints of a project with their tag
+// overrides. The caller must hold the write lock.
+func (d *db) deleteProjectPoints(projectID int) {
+	kept := d.dataPoints[:0]
+	for _, dp := range d.dataPoints {
+		if dp.ProjectID != projectID {
+			kept = append(kept, dp)
+		} else {
+			delete(d.pointTags, dp.ID)
+		}
+	}
+	d.dataPoints = kept
+}
+
+// copyDataPoint returns dp without sharing its pointer fields with the
+// caller.
+func copyDataPoint(dp models.DataPoint) models.DataPoint {
+	var out models.DataPoint
+	out.ID = dp.ID
+	out.ProjectID = dp.ProjectID
+	for _, c := range models.DataPointColumns {
+		out.Set(c.Name, dp.Get(c.Name))
+	}
+	return out
+}
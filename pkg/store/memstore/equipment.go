@@ -0,0 +1,61 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type equipmentStore struct {
+	*db
+}
+
+func (s *equipmentStore) ListByProject(ctx context.Context, projectID int) ([]models.Equipment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []models.Equipment{}
+	for _, e := range s.equipment {
+		if e.ProjectID == projectID {
+			list = append(list, e)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].EquipID) < strings.ToLower(list[j].EquipID)
+	})
+
+	return list, nil
+}
+
+func (s *equipmentStore) Sync(ctx context.Context, projectID int, list []models.Equipment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[projectID]; !ok {
+		return store.ErrNotFound
+	}
+
+	existing := make(map[string]int)
+	for id, e := range s.equipment {
+		if e.ProjectID == projectID {
+			existing[strings.ToLower(e.EquipID)] = id
+			delete(s.equipment, id)
+		}
+	}
+
+	for _, e := range list {
+		e.ProjectID = projectID
+		if id, ok := existing[strings.ToLower(e.EquipID)]; ok {
+			e.ID = id
+		} else {
+			e.ID = s.nextEquipmentID
+			s.nextEquipmentID++
+		}
+		s.equipment[e.ID] = e
+	}
+
+	return nil
+}
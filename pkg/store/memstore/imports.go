@@ -0,0 +1,131 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type importStore struct {
+	*db
+}
+
+func (s *importStore) Create(ctx context.Context, imp *models.Import) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[imp.ProjectID]; !ok {
+		return store.ErrNotFound
+	}
+
+	imp.ID = s.nextImportID
+	s.nextImportID++
+	s.imports[imp.ID] = copyImport(*imp)
+
+	return nil
+}
+
+func (s *importStore) Update(ctx context.Context, imp models.Import) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.imports[imp.ID]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	// Only the outcome of an import changes, as in the SQL store.
+	existing.Status = imp.Status
+	existing.TotalRows = imp.TotalRows
+	existing.ImportedRows = imp.ImportedRows
+	existing.RejectedRows = imp.RejectedRows
+	existing.ProcessedRows = imp.ProcessedRows
+	existing.Errors = imp.Errors
+	existing.Warnings = imp.Warnings
+	existing.StartedAt = imp.StartedAt
+	existing.FinishedAt = imp.FinishedAt
+	existing.DurationMs = imp.DurationMs
+	s.imports[imp.ID] = copyImport(existing)
+
+	return nil
+}
+
+func (s *importStore) UpdateProgress(ctx context.Context, id int, processedRows int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imp, ok := s.imports[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	imp.ProcessedRows = processedRows
+	s.imports[id] = imp
+
+	return nil
+}
+
+func (s *importStore) Get(ctx context.Context, id int) (models.Import, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	imp, ok := s.imports[id]
+	if !ok {
+		return models.Import{}, store.ErrNotFound
+	}
+
+	return copyImport(imp), nil
+}
+
+func (s *importStore) ListByProject(ctx context.Context, projectID int) ([]models.Import, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	imports := []models.Import{}
+	for _, imp := range s.imports {
+		if imp.ProjectID == projectID {
+			imports = append(imports, copyImport(imp))
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].ID > imports[j].ID })
+
+	return imports, nil
+}
+
+func (s *importStore) ListByStatus(ctx context.Context, statuses ...string) ([]models.Import, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	imports := []models.Import{}
+	for _, imp := range s.imports {
+		for _, status := range statuses {
+			if imp.Status == status {
+				imports = append(imports, copyImport(imp))
+				break
+			}
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].ID < imports[j].ID })
+
+	return imports, nil
+}
+
+// copyImport returns imp without sharing its slices and pointers with the
+// caller.
+func copyImport(imp models.Import) models.Import {
+	imp.Errors = append([]string{}, imp.Errors...)
+	imp.Warnings = append([]string{}, imp.Warnings...)
+	if imp.Options.Aliases != nil {
+		aliases := make(map[string]string, len(imp.Options.Aliases))
+		for k, v := range imp.Options.Aliases {
+			aliases[k] = v
+		}
+		imp.Options.Aliases = aliases
+	}
+	if imp.FinishedAt != nil {
+		t := *imp.FinishedAt
+		imp.FinishedAt = &t
+	}
+	return imp
+}
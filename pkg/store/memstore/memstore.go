@@ -0,0 +1,101 @@
+// Package memstore implements the store interfaces in memory. It mirrors the
+// behaviour of sqlstore closely enough for handler tests and for running the
+// API without a database.
+package memstore
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/units"
+)
+
+// db is the shared state behind the individual stores. A single lock keeps
+// cross-table checks, such as a project referencing a service, consistent.
+type db struct {
+	mu sync.RWMutex
+
+	projects map[int]models.Project
+	services map[int]models.Service
+
+	dataPoints  []models.DataPoint
+	nextPointID int
+
+	imports      map[int]models.Import
+	nextImportID int
+
+	units       map[int]models.Unit
+	unitAliases map[int]models.UnitAlias
+	nextAliasID int
+
+	naming map[int]models.NamingConvention
+
+	equipment       map[int]models.Equipment
+	nextEquipmentID int
+
+	networks      map[int]models.Network
+	nextNetworkID int
+	devices       map[int]models.Device
+	nextDeviceID  int
+
+	tagRules  map[int]models.TaggingRules
+	pointTags map[int]models.PointTags
+
+	templates      map[int]models.PointTemplate
+	nextTemplateID int
+}
+
+// New returns an in-memory store.Store that is empty apart from the units
+// catalogue, which is seeded like the units migration does.
+func New() store.Store {
+	d := &db{
+		projects:        make(map[int]models.Project),
+		services:        make(map[int]models.Service),
+		imports:         make(map[int]models.Import),
+		nextImportID:    1,
+		nextPointID:     1,
+		units:           make(map[int]models.Unit, len(units.Standard)),
+		unitAliases:     make(map[int]models.UnitAlias, len(units.DefaultAliases)),
+		nextAliasID:     1,
+		naming:          make(map[int]models.NamingConvention),
+		equipment:       make(map[int]models.Equipment),
+		nextEquipmentID: 1,
+		networks:        make(map[int]models.Network),
+		nextNetworkID:   1,
+		devices:         make(map[int]models.Device),
+		nextDeviceID:    1,
+		tagRules:        make(map[int]models.TaggingRules),
+		pointTags:       make(map[int]models.PointTags),
+		templates:       make(map[int]models.PointTemplate),
+		nextTemplateID:  1,
+	}
+
+	for _, u := range units.Standard {
+		d.units[u.ID] = u
+	}
+	aliases := make([]models.UnitAlias, 0, len(units.DefaultAliases))
+	for alias, unitID := range units.DefaultAliases {
+		aliases = append(aliases, models.UnitAlias{Alias: units.Normalize(alias), UnitID: unitID})
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+	for _, a := range aliases {
+		a.ID = d.nextAliasID
+		d.unitAliases[a.ID] = a
+		d.nextAliasID++
+	}
+
+	return store.Store{
+		Projects:   &projectStore{d},
+		Services:   &serviceStore{d},
+		DataPoints: &dataPointStore{d},
+		Imports:    &importStore{d},
+		Units:      &unitStore{d},
+		Naming:     &namingStore{d},
+		Equipment:  &equipmentStore{d},
+		Networks:   &networkStore{d},
+		Tags:       &tagStore{d},
+		Templates:  &templateStore{d},
+	}
+}
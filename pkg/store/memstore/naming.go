@@ -0,0 +1,41 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type namingStore struct {
+	*db
+}
+
+func (s *namingStore) Get(ctx context.Context, projectID int) (models.NamingConvention, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nc, ok := s.naming[projectID]
+	if !ok {
+		return models.NamingConvention{}, store.ErrNotFound
+	}
+
+	return copyNamingConvention(nc), nil
+}
+
+func (s *namingStore) Put(ctx context.Context, nc models.NamingConvention) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[nc.ProjectID]; !ok {
+		return store.ErrNotFound
+	}
+	s.naming[nc.ProjectID] = copyNamingConvention(nc)
+
+	return nil
+}
+
+func copyNamingConvention(nc models.NamingConvention) models.NamingConvention {
+	nc.Rules = append([]models.NamingRule{}, nc.Rules...)
+	return nc
+}
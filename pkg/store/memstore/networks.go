@@ -0,0 +1,132 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type networkStore struct {
+	*db
+}
+
+func (s *networkStore) ListNetworks(ctx context.Context, projectID int) ([]models.Network, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []models.Network{}
+	for _, n := range s.networks {
+		if n.ProjectID == projectID {
+			list = append(list, n)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+
+	return list, nil
+}
+
+func (s *networkStore) ListDevices(ctx context.Context, projectID int) ([]models.Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []models.Device{}
+	for _, d := range s.devices {
+		if d.ProjectID == projectID {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+
+	return list, nil
+}
+
+func (s *networkStore) CreateDevice(ctx context.Context, d *models.Device) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[d.ProjectID]; !ok {
+		return store.ErrNotFound
+	}
+	for _, other := range s.devices {
+		if other.ProjectID == d.ProjectID && strings.EqualFold(other.Name, d.Name) {
+			return store.ErrConflict
+		}
+	}
+
+	d.ID = s.nextDeviceID
+	s.nextDeviceID++
+	d.Declared = true
+	d.Points = 0
+	s.devices[d.ID] = *d
+
+	return nil
+}
+
+func (s *networkStore) DeleteDevice(ctx context.Context, projectID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.devices[id]; !ok || d.ProjectID != projectID {
+		return store.ErrNotFound
+	}
+	delete(s.devices, id)
+
+	return nil
+}
+
+func (s *networkStore) Sync(ctx context.Context, projectID int, networks []models.Network, devices []models.Device) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[projectID]; !ok {
+		return store.ErrNotFound
+	}
+
+	existingNetworks := make(map[string]int)
+	for id, n := range s.networks {
+		if n.ProjectID == projectID {
+			existingNetworks[n.Type+"\x00"+strings.ToLower(n.Name)] = id
+			delete(s.networks, id)
+		}
+	}
+	for _, n := range networks {
+		n.ProjectID = projectID
+		if id, ok := existingNetworks[n.Type+"\x00"+strings.ToLower(n.Name)]; ok {
+			n.ID = id
+		} else {
+			n.ID = s.nextNetworkID
+			s.nextNetworkID++
+		}
+		s.networks[n.ID] = n
+	}
+
+	existingDevices := make(map[string]int)
+	for id, d := range s.devices {
+		if d.ProjectID == projectID {
+			existingDevices[strings.ToLower(d.Name)] = id
+			delete(s.devices, id)
+		}
+	}
+	for _, d := range devices {
+		d.ProjectID = projectID
+		if id, ok := existingDevices[strings.ToLower(d.Name)]; ok {
+			d.ID = id
+		} else {
+			d.ID = s.nextDeviceID
+			s.nextDeviceID++
+		}
+		s.devices[d.ID] = d
+	}
+
+	return nil
+}
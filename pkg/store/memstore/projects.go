@@ -0,0 +1,129 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type projectStore struct {
+	*db
+}
+
+func (s *projectStore) List(ctx context.Context) ([]models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var projects []models.Project
+	for _, p := range s.projects {
+		service, ok := s.services[p.Service.ID]
+		if !ok {
+			continue
+		}
+		p.Service = service
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+
+	return projects, nil
+}
+
+func (s *projectStore) Get(ctx context.Context, id int) (models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.projects[id]
+	if !ok {
+		return models.Project{}, store.ErrNotFound
+	}
+	service, ok := s.services[p.Service.ID]
+	if !ok {
+		return models.Project{}, store.ErrNotFound
+	}
+	p.Service = service
+
+	return p, nil
+}
+
+func (s *projectStore) Create(ctx context.Context, project models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.services[project.Service.ID]; !ok {
+		return store.ErrNotFound
+	}
+
+	if project.ID == 0 {
+		for id := range s.projects {
+			if id > project.ID {
+				project.ID = id
+			}
+		}
+		project.ID++
+	}
+	if _, ok := s.projects[project.ID]; ok {
+		return store.ErrConflict
+	}
+	s.projects[project.ID] = project
+
+	return nil
+}
+
+func (s *projectStore) Update(ctx context.Context, project models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.projects[project.ID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if _, ok := s.services[project.Service.ID]; !ok {
+		return store.ErrNotFound
+	}
+
+	// The SQL update leaves the date alone.
+	project.Date = existing.Date
+	s.projects[project.ID] = project
+
+	return nil
+}
+
+func (s *projectStore) Delete(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.projects, id)
+
+	// Datapoints, imports, equipment, networks, devices, the naming
+	// convention and the tagging rules cascade with their project.
+	s.deleteProjectPoints(id)
+	for importID, imp := range s.imports {
+		if imp.ProjectID == id {
+			delete(s.imports, importID)
+		}
+	}
+	for equipmentID, e := range s.equipment {
+		if e.ProjectID == id {
+			delete(s.equipment, equipmentID)
+		}
+	}
+	for networkID, n := range s.networks {
+		if n.ProjectID == id {
+			delete(s.networks, networkID)
+		}
+	}
+	for deviceID, d := range s.devices {
+		if d.ProjectID == id {
+			delete(s.devices, deviceID)
+		}
+	}
+	delete(s.naming, id)
+	delete(s.tagRules, id)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type serviceStore struct {
+	*db
+}
+
+func (s *serviceStore) Get(ctx context.Context, id int) (models.Service, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	service, ok := s.services[id]
+	if !ok {
+		return models.Service{}, store.ErrNotFound
+	}
+
+	return service, nil
+}
+
+func (s *serviceStore) Upsert(ctx context.Context, service models.Service) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.services[service.ID] = service
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type tagStore struct {
+	*db
+}
+
+func (s *tagStore) GetRules(ctx context.Context, projectID int) (models.TaggingRules, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules, ok := s.tagRules[projectID]
+	if !ok {
+		return models.TaggingRules{}, store.ErrNotFound
+	}
+
+	return copyTaggingRules(rules), nil
+}
+
+func (s *tagStore) PutRules(ctx context.Context, rules models.TaggingRules) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[rules.ProjectID]; !ok {
+		return store.ErrNotFound
+	}
+	s.tagRules[rules.ProjectID] = copyTaggingRules(rules)
+
+	return nil
+}
+
+func (s *tagStore) ListPointTags(ctx context.Context, projectID int) ([]models.PointTags, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []models.PointTags{}
+	for _, t := range s.pointTags {
+		if t.ProjectID == projectID {
+			list = append(list, copyPointTags(t))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PointID < list[j].PointID })
+
+	return list, nil
+}
+
+func (s *tagStore) GetPointTags(ctx context.Context, pointID int) (models.PointTags, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.pointTags[pointID]
+	if !ok {
+		return models.PointTags{}, store.ErrNotFound
+	}
+
+	return copyPointTags(t), nil
+}
+
+func (s *tagStore) PutPointTags(ctx context.Context, t models.PointTags) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findPoint(t.ProjectID, t.PointID) < 0 {
+		return store.ErrNotFound
+	}
+	if len(t.Add) == 0 && len(t.Remove) == 0 {
+		delete(s.pointTags, t.PointID)
+		return nil
+	}
+	s.pointTags[t.PointID] = copyPointTags(t)
+
+	return nil
+}
+
+func copyTaggingRules(rules models.TaggingRules) models.TaggingRules {
+	list := make([]models.TagRule, len(rules.Rules))
+	for i, r := range rules.Rules {
+		r.Tags = append([]string{}, r.Tags...)
+		list[i] = r
+	}
+	rules.Rules = list
+	return rules
+}
+
+func copyPointTags(t models.PointTags) models.PointTags {
+	t.Add = append([]string{}, t.Add...)
+	t.Remove = append([]string{}, t.Remove...)
+	return t
+}
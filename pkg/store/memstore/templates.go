@@ -0,0 +1,98 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type templateStore struct {
+	*db
+}
+
+func (s *templateStore) List(ctx context.Context) ([]models.PointTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]models.PointTemplate, 0, len(s.templates))
+	for _, t := range s.templates {
+		list = append(list, copyTemplate(t))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list, nil
+}
+
+func (s *templateStore) Get(ctx context.Context, id int) (models.PointTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[id]
+	if !ok {
+		return models.PointTemplate{}, store.ErrNotFound
+	}
+
+	return copyTemplate(t), nil
+}
+
+func (s *templateStore) Create(ctx context.Context, t *models.PointTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.templateNameUsed(t.Name, 0) {
+		return store.ErrConflict
+	}
+
+	t.ID = s.nextTemplateID
+	s.nextTemplateID++
+	s.templates[t.ID] = copyTemplate(*t)
+
+	return nil
+}
+
+func (s *templateStore) Update(ctx context.Context, t models.PointTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[t.ID]; !ok {
+		return store.ErrNotFound
+	}
+	if s.templateNameUsed(t.Name, t.ID) {
+		return store.ErrConflict
+	}
+	s.templates[t.ID] = copyTemplate(t)
+
+	return nil
+}
+
+func (s *templateStore) Delete(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.templates, id)
+
+	return nil
+}
+
+// templateNameUsed reports whether a template other than except has name,
+// compared ignoring case like the unique key in MySQL. The caller must hold
+// the lock.
+func (d *db) templateNameUsed(name string, except int) bool {
+	for id, t := range d.templates {
+		if id != except && strings.EqualFold(t.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTemplate(t models.PointTemplate) models.PointTemplate {
+	t.Points = append([]models.TemplatePoint{}, t.Points...)
+	return t
+}
@@ -0,0 +1,83 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type unitStore struct {
+	*db
+}
+
+func (s *unitStore) List(ctx context.Context) ([]models.Unit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	units := make([]models.Unit, 0, len(s.units))
+	for _, u := range s.units {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].ID < units[j].ID })
+
+	return units, nil
+}
+
+func (s *unitStore) Get(ctx context.Context, id int) (models.Unit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.units[id]
+	if !ok {
+		return models.Unit{}, store.ErrNotFound
+	}
+
+	return u, nil
+}
+
+func (s *unitStore) ListAliases(ctx context.Context) ([]models.UnitAlias, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	aliases := make([]models.UnitAlias, 0, len(s.unitAliases))
+	for _, a := range s.unitAliases {
+		aliases = append(aliases, a)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+
+	return aliases, nil
+}
+
+func (s *unitStore) CreateAlias(ctx context.Context, alias *models.UnitAlias) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.units[alias.UnitID]; !ok {
+		return store.ErrNotFound
+	}
+	for _, a := range s.unitAliases {
+		if a.Alias == alias.Alias {
+			return store.ErrConflict
+		}
+	}
+
+	alias.ID = s.nextAliasID
+	s.nextAliasID++
+	s.unitAliases[alias.ID] = *alias
+
+	return nil
+}
+
+func (s *unitStore) DeleteAlias(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.unitAliases[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.unitAliases, id)
+
+	return nil
+}
@@ -0,0 +1,347 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+const (
+	// maxBatchRows is the number of rows inserted per statement.
+	maxBatchRows = 500
+
+	// maxPlaceholders is the number of placeholders MySQL accepts in a
+	// prepared statement.
+	maxPlaceholders = 65535
+)
+
+type dataPointStore struct {
+	db *sql.DB
+}
+
+// dataPointColumns is the select list for a full datapoint. Bit columns are
+// cast so they scan like the other columns.
+var dataPointColumns = func() string {
+	cols := []string{"Id", "ProjectId"}
+	for _, c := range models.DataPointColumns {
+		if c.Type == models.ColumnBool {
+			cols = append(cols, "CAST(`"+c.Name+"` AS UNSIGNED)")
+		} else {
+			cols = append(cols, "`"+c.Name+"`")
+		}
+	}
+	return strings.Join(cols, ", ")
+}()
+
+func scanDataPoint(row scanner) (models.DataPoint, error) {
+	var dp models.DataPoint
+
+	values := make([]sql.NullString, len(models.DataPointColumns))
+	dest := make([]interface{}, 0, len(values)+2)
+	dest = append(dest, &dp.ID, &dp.ProjectID)
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return dp, err
+	}
+
+	for i, c := range models.DataPointColumns {
+		if err := dp.Set(c.Name, values[i].String); err != nil {
+			return dp, err
+		}
+	}
+
+	return dp, nil
+}
+
+// dataPointValues returns the arguments for every datapoints column of dp.
+func dataPointValues(dp models.DataPoint) []interface{} {
+	values := make([]interface{}, 0, len(models.DataPointColumns))
+	for _, c := range models.DataPointColumns {
+		values = append(values, columnValue(c, dp.Get(c.Name)))
+	}
+	return values
+}
+
+func (s *dataPointStore) ListByProject(ctx context.Context, projectID int) ([]models.DataPoint, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+dataPointColumns+" FROM datapoints WHERE ProjectId = ? ORDER BY Id", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := []models.DataPoint{}
+	for rows.Next() {
+		dp, err := scanDataPoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, dp)
+	}
+
+	return data, rows.Err()
+}
+
+func (s *dataPointStore) Query(ctx context.Context, projectID int, q store.DataPointQuery) ([]models.DataPoint, int, error) {
+	where := []string{"ProjectId = ?"}
+	args := []interface{}{projectID}
+
+	for name, value := range q.Equals {
+		c, ok := models.DataPointColumn(name)
+		if !ok {
+			return nil, 0, fmt.Errorf("sqlstore: unknown datapoints column %q", name)
+		}
+		if value == "" {
+			where = append(where, "`"+c.Name+"` IS NULL")
+			continue
+		}
+		where = append(where, "`"+c.Name+"` = ?")
+		args = append(args, columnValue(c, value))
+	}
+
+	if q.Search != "" {
+		like := "%" + likeEscaper.Replace(q.Search) + "%"
+		where = append(where, "(PointName LIKE ? OR Descriptor LIKE ?)")
+		args = append(args, like, like)
+	}
+
+	cond := strings.Join(where, " AND ")
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM datapoints WHERE "+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order := "Id"
+	if q.Sort != "" && !strings.EqualFold(q.Sort, "Id") {
+		c, ok := models.DataPointColumn(q.Sort)
+		if !ok {
+			return nil, 0, fmt.Errorf("sqlstore: unknown datapoints column %q", q.Sort)
+		}
+		order = "`" + c.Name + "`"
+	}
+	dir := " ASC"
+	if q.Desc {
+		dir = " DESC"
+	}
+	query := "SELECT " + dataPointColumns + " FROM datapoints WHERE " + cond + " ORDER BY " + order + dir
+	if order != "Id" {
+		query += ", Id" + dir
+	}
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	} else if q.Offset > 0 {
+		// MySQL has no OFFSET without LIMIT
+		query += " LIMIT 18446744073709551615 OFFSET ?"
+		args = append(args, q.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	data := []models.DataPoint{}
+	for rows.Next() {
+		dp, err := scanDataPoint(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		data = append(data, dp)
+	}
+
+	return data, total, rows.Err()
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *dataPointStore) Get(ctx context.Context, projectID, id int) (models.DataPoint, error) {
+	dp, err := scanDataPoint(s.db.QueryRowContext(ctx, "SELECT "+dataPointColumns+" FROM datapoints WHERE Id = ? AND ProjectId = ?", id, projectID))
+	if err != nil {
+		return dp, translate(err)
+	}
+
+	return dp, nil
+}
+
+func (s *dataPointStore) Create(ctx context.Context, dp *models.DataPoint) error {
+	cols := make([]string, len(models.DataPointColumns))
+	for i, c := range models.DataPointColumns {
+		cols[i] = "`" + c.Name + "`"
+	}
+	placeholders := strings.Repeat(", ?", len(cols))
+
+	args := append([]interface{}{dp.ProjectID}, dataPointValues(*dp)...)
+	res, err := s.db.ExecContext(ctx, "INSERT INTO datapoints (ProjectId, "+strings.Join(cols, ", ")+") VALUES (?"+placeholders+")", args...)
+	if err != nil {
+		return translate(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	dp.ID = int(id)
+
+	return nil
+}
+
+func (s *dataPointStore) Update(ctx context.Context, dp models.DataPoint) error {
+	return updateDataPoint(ctx, s.db, dp)
+}
+
+func (s *dataPointStore) UpdateMany(ctx context.Context, points []models.DataPoint) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, dp := range points {
+		if err := updateDataPoint(ctx, tx, dp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// execer is the part of *sql.DB and *sql.Tx the datapoint updates use.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// updateDataPoint replaces every column of an existing datapoint.
+func updateDataPoint(ctx context.Context, db execer, dp models.DataPoint) error {
+	sets := make([]string, len(models.DataPointColumns))
+	for i, c := range models.DataPointColumns {
+		sets[i] = "`" + c.Name + "` = ?"
+	}
+
+	args := append(dataPointValues(dp), dp.ID, dp.ProjectID)
+	res, err := db.ExecContext(ctx, "UPDATE datapoints SET "+strings.Join(sets, ", ")+" WHERE Id = ? AND ProjectId = ?", args...)
+	if err != nil {
+		return translate(err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM datapoints WHERE Id = ? AND ProjectId = ?)", dp.ID, dp.ProjectID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *dataPointStore) Delete(ctx context.Context, projectID, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM datapoints WHERE Id = ? AND ProjectId = ?", id, projectID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *dataPointStore) Import(ctx context.Context, projectID int, columns []string, rows [][]string, opts store.ImportOptions) error {
+	// Only known columns ever reach the statement, so quoting them is safe.
+	defs := make([]models.Column, len(columns))
+	escaped := make([]string, len(columns))
+	for i, name := range columns {
+		c, ok := models.DataPointColumn(name)
+		if !ok {
+			return fmt.Errorf("sqlstore: unknown datapoints column %q", name)
+		}
+		defs[i] = c
+		escaped[i] = "`" + c.Name + "`"
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if opts.Mode == store.ImportReplace {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM datapoints WHERE ProjectId = ?", projectID); err != nil {
+			return err
+		}
+	}
+
+	// Insert the rows in multi-row statements, keeping every statement
+	// within both the batch size and MySQL's placeholder limit.
+	size := maxBatchRows
+	if n := maxPlaceholders / (len(columns) + 1); n < size {
+		size = n
+	}
+	rowPlaceholders := "(?" + strings.Repeat(", ?", len(columns)) + ")"
+	prefix := fmt.Sprintf("INSERT INTO datapoints (ProjectId, %s) VALUES ", strings.Join(escaped, ", "))
+
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batch := rows[start:end]
+
+		query := prefix + strings.TrimSuffix(strings.Repeat(rowPlaceholders+", ", len(batch)), ", ")
+		values := make([]interface{}, 0, len(batch)*(len(columns)+1))
+		for _, row := range batch {
+			values = append(values, projectID)
+			for i, def := range defs {
+				var v string
+				if i < len(row) {
+					v = row[i]
+				}
+				values = append(values, columnValue(def, v))
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, query, values...); err != nil {
+			return fmt.Errorf("sqlstore: importing rows %d-%d: %w", start+1, end, translate(err))
+		}
+		if opts.Progress != nil {
+			opts.Progress(end)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// columnValue converts a value in the importer's string form into the
+// argument for a datapoints column. Empty values become NULL.
+func columnValue(c models.Column, v string) interface{} {
+	if v == "" {
+		return nil
+	}
+
+	switch c.Type {
+	case models.ColumnInt:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	case models.ColumnBool:
+		return v == "1"
+	}
+
+	return v
+}
@@ -0,0 +1,93 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+type equipmentStore struct {
+	db *sql.DB
+}
+
+func (s *equipmentStore) ListByProject(ctx context.Context, projectID int) ([]models.Equipment, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, ProjectId, EquipID, EquipType, System, Parent, Points FROM equipment WHERE ProjectId = ? ORDER BY EquipID", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Equipment{}
+	for rows.Next() {
+		var (
+			e                         models.Equipment
+			equipType, system, parent sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &e.ProjectID, &e.EquipID, &equipType, &system, &parent, &e.Points); err != nil {
+			return nil, err
+		}
+		e.EquipType = equipType.String
+		e.System = system.String
+		e.Parent = parent.String
+		list = append(list, e)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *equipmentStore) Sync(ctx context.Context, projectID int, list []models.Equipment) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Remove the equipment that is gone, then upsert the rest so the
+	// remaining rows keep their IDs.
+	keep := make(map[string]bool, len(list))
+	for _, e := range list {
+		keep[strings.ToLower(e.EquipID)] = true
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT Id, EquipID FROM equipment WHERE ProjectId = ?", projectID)
+	if err != nil {
+		return err
+	}
+	var gone []int
+	for rows.Next() {
+		var (
+			id      int
+			equipID string
+		)
+		if err := rows.Scan(&id, &equipID); err != nil {
+			rows.Close()
+			return err
+		}
+		if !keep[strings.ToLower(equipID)] {
+			gone = append(gone, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range gone {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM equipment WHERE Id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range list {
+		_, err := tx.ExecContext(ctx, "INSERT INTO equipment (ProjectId, EquipID, EquipType, System, Parent, Points) VALUES (?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE EquipType = VALUES(EquipType), System = VALUES(System), Parent = VALUES(Parent), Points = VALUES(Points)",
+			projectID, e.EquipID, nullString(e.EquipType), nullString(e.System), nullString(e.Parent), e.Points)
+		if err != nil {
+			return translate(err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,181 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+const importColumns = "Id, ProjectId, FileName, StoredName, Checksum, Size, Uploader, Mode, Options, Status, " +
+	"TotalRows, ImportedRows, RejectedRows, ProcessedRows, Errors, Warnings, StartedAt, FinishedAt, DurationMs"
+
+type importStore struct {
+	db *sql.DB
+}
+
+func (s *importStore) Create(ctx context.Context, imp *models.Import) error {
+	errs, err := json.Marshal(imp.Errors)
+	if err != nil {
+		return err
+	}
+	warnings, err := json.Marshal(imp.Warnings)
+	if err != nil {
+		return err
+	}
+	opts, err := json.Marshal(imp.Options)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, "INSERT INTO imports (ProjectId, FileName, StoredName, Checksum, Size, Uploader, Mode, Options, Status, "+
+		"TotalRows, ImportedRows, RejectedRows, ProcessedRows, Errors, Warnings, StartedAt, FinishedAt, DurationMs) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		imp.ProjectID, imp.FileName, imp.StoredName, imp.Checksum, imp.Size, nullString(imp.Uploader), imp.Mode, string(opts), imp.Status,
+		imp.TotalRows, imp.ImportedRows, imp.RejectedRows, imp.ProcessedRows, string(errs), string(warnings), imp.StartedAt, imp.FinishedAt, imp.DurationMs)
+	if err != nil {
+		return translate(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	imp.ID = int(id)
+
+	return nil
+}
+
+func (s *importStore) Update(ctx context.Context, imp models.Import) error {
+	errs, err := json.Marshal(imp.Errors)
+	if err != nil {
+		return err
+	}
+	warnings, err := json.Marshal(imp.Warnings)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, "UPDATE imports SET Status = ?, TotalRows = ?, ImportedRows = ?, RejectedRows = ?, ProcessedRows = ?, Errors = ?, Warnings = ?, StartedAt = ?, FinishedAt = ?, DurationMs = ? WHERE Id = ?",
+		imp.Status, imp.TotalRows, imp.ImportedRows, imp.RejectedRows, imp.ProcessedRows, string(errs), string(warnings), imp.StartedAt, imp.FinishedAt, imp.DurationMs, imp.ID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM imports WHERE Id = ?)", imp.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *importStore) UpdateProgress(ctx context.Context, id int, processedRows int) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE imports SET ProcessedRows = ? WHERE Id = ?", processedRows, id)
+	return err
+}
+
+func (s *importStore) Get(ctx context.Context, id int) (models.Import, error) {
+	imp, err := scanImport(s.db.QueryRowContext(ctx, "SELECT "+importColumns+" FROM imports WHERE Id = ?", id))
+	if err != nil {
+		return imp, translate(err)
+	}
+
+	return imp, nil
+}
+
+func (s *importStore) ListByProject(ctx context.Context, projectID int) ([]models.Import, error) {
+	return s.list(ctx, "SELECT "+importColumns+" FROM imports WHERE ProjectId = ? ORDER BY Id DESC", projectID)
+}
+
+func (s *importStore) ListByStatus(ctx context.Context, statuses ...string) ([]models.Import, error) {
+	if len(statuses) == 0 {
+		return []models.Import{}, nil
+	}
+
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")
+
+	return s.list(ctx, "SELECT "+importColumns+" FROM imports WHERE Status IN ("+placeholders+") ORDER BY Id", args...)
+}
+
+func (s *importStore) list(ctx context.Context, query string, args ...interface{}) ([]models.Import, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imports := []models.Import{}
+	for rows.Next() {
+		imp, err := scanImport(rows)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, imp)
+	}
+
+	return imports, rows.Err()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanImport(row scanner) (models.Import, error) {
+	var (
+		imp      models.Import
+		uploader sql.NullString
+		opts     sql.NullString
+		errs     sql.NullString
+		warnings sql.NullString
+		finished sql.NullTime
+	)
+
+	err := row.Scan(&imp.ID, &imp.ProjectID, &imp.FileName, &imp.StoredName, &imp.Checksum, &imp.Size, &uploader, &imp.Mode, &opts, &imp.Status,
+		&imp.TotalRows, &imp.ImportedRows, &imp.RejectedRows, &imp.ProcessedRows, &errs, &warnings, &imp.StartedAt, &finished, &imp.DurationMs)
+	if err != nil {
+		return imp, err
+	}
+
+	imp.Uploader = uploader.String
+	if finished.Valid {
+		imp.FinishedAt = &finished.Time
+	}
+	if opts.Valid && opts.String != "" {
+		if err := json.Unmarshal([]byte(opts.String), &imp.Options); err != nil {
+			return imp, err
+		}
+	}
+	imp.Errors = []string{}
+	if errs.Valid && errs.String != "" {
+		if err := json.Unmarshal([]byte(errs.String), &imp.Errors); err != nil {
+			return imp, err
+		}
+	}
+	imp.Warnings = []string{}
+	if warnings.Valid && warnings.String != "" {
+		if err := json.Unmarshal([]byte(warnings.String), &imp.Warnings); err != nil {
+			return imp, err
+		}
+	}
+
+	return imp, nil
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,50 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+type namingStore struct {
+	db *sql.DB
+}
+
+func (s *namingStore) Get(ctx context.Context, projectID int) (models.NamingConvention, error) {
+	var (
+		nc       models.NamingConvention
+		template sql.NullString
+		rules    sql.NullString
+	)
+
+	err := s.db.QueryRowContext(ctx, "SELECT ProjectId, PointNameTemplate, Rules FROM naming_conventions WHERE ProjectId = ?", projectID).
+		Scan(&nc.ProjectID, &template, &rules)
+	if err != nil {
+		return nc, translate(err)
+	}
+
+	nc.PointNameTemplate = template.String
+	nc.Rules = []models.NamingRule{}
+	if rules.Valid && rules.String != "" {
+		if err := json.Unmarshal([]byte(rules.String), &nc.Rules); err != nil {
+			return nc, err
+		}
+	}
+
+	return nc, nil
+}
+
+func (s *namingStore) Put(ctx context.Context, nc models.NamingConvention) error {
+	rules, err := json.Marshal(nc.Rules)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO naming_conventions (ProjectId, PointNameTemplate, Rules) VALUES (?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE PointNameTemplate = VALUES(PointNameTemplate), Rules = VALUES(Rules)",
+		nc.ProjectID, nullString(nc.PointNameTemplate), string(rules))
+
+	return translate(err)
+}
@@ -0,0 +1,183 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type networkStore struct {
+	db *sql.DB
+}
+
+func (s *networkStore) ListNetworks(ctx context.Context, projectID int) ([]models.Network, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, ProjectId, Type, Name, Devices, Points FROM networks WHERE ProjectId = ? ORDER BY Type, Name", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Network{}
+	for rows.Next() {
+		var n models.Network
+		if err := rows.Scan(&n.ID, &n.ProjectID, &n.Type, &n.Name, &n.Devices, &n.Points); err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *networkStore) ListDevices(ctx context.Context, projectID int) ([]models.Device, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, ProjectId, Name, Kind, NetworkType, Network, Parent, Address, Instance, Declared, Points FROM devices WHERE ProjectId = ? ORDER BY Name", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Device{}
+	for rows.Next() {
+		var (
+			d                                     models.Device
+			networkType, network, parent, address sql.NullString
+			instance                              sql.NullInt64
+		)
+		if err := rows.Scan(&d.ID, &d.ProjectID, &d.Name, &d.Kind, &networkType, &network, &parent, &address, &instance, &d.Declared, &d.Points); err != nil {
+			return nil, err
+		}
+		d.NetworkType = networkType.String
+		d.Network = network.String
+		d.Parent = parent.String
+		d.Address = address.String
+		if instance.Valid {
+			n := int(instance.Int64)
+			d.Instance = &n
+		}
+		list = append(list, d)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *networkStore) CreateDevice(ctx context.Context, d *models.Device) error {
+	res, err := s.db.ExecContext(ctx, "INSERT INTO devices (ProjectId, Name, Kind, NetworkType, Network, Parent, Address, Instance, Declared, Points) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, 0)",
+		d.ProjectID, d.Name, d.Kind, nullString(d.NetworkType), nullString(d.Network), nullString(d.Parent), nullString(d.Address), d.Instance)
+	if err != nil {
+		return translate(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	d.ID = int(id)
+	d.Declared = true
+	d.Points = 0
+
+	return nil
+}
+
+func (s *networkStore) DeleteDevice(ctx context.Context, projectID, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM devices WHERE Id = ? AND ProjectId = ?", id, projectID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *networkStore) Sync(ctx context.Context, projectID int, networks []models.Network, devices []models.Device) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Remove the rows that are gone, then upsert the rest so the remaining
+	// rows keep their IDs.
+	keepNetworks := make(map[string]bool, len(networks))
+	for _, n := range networks {
+		keepNetworks[n.Type+"\x00"+strings.ToLower(n.Name)] = true
+	}
+	err = deleteGone(ctx, tx, "SELECT Id, CONCAT(Type, CHAR(0), Name) FROM networks WHERE ProjectId = ?", "DELETE FROM networks WHERE Id = ?", projectID, func(key string) bool {
+		typ, name, _ := strings.Cut(key, "\x00")
+		return keepNetworks[typ+"\x00"+strings.ToLower(name)]
+	})
+	if err != nil {
+		return err
+	}
+
+	keepDevices := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		keepDevices[strings.ToLower(d.Name)] = true
+	}
+	err = deleteGone(ctx, tx, "SELECT Id, Name FROM devices WHERE ProjectId = ?", "DELETE FROM devices WHERE Id = ?", projectID, func(key string) bool {
+		return keepDevices[strings.ToLower(key)]
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range networks {
+		_, err := tx.ExecContext(ctx, "INSERT INTO networks (ProjectId, Type, Name, Devices, Points) VALUES (?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE Devices = VALUES(Devices), Points = VALUES(Points)",
+			projectID, n.Type, n.Name, n.Devices, n.Points)
+		if err != nil {
+			return translate(err)
+		}
+	}
+
+	for _, d := range devices {
+		_, err := tx.ExecContext(ctx, "INSERT INTO devices (ProjectId, Name, Kind, NetworkType, Network, Parent, Address, Instance, Declared, Points) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE Kind = VALUES(Kind), NetworkType = VALUES(NetworkType), Network = VALUES(Network), Parent = VALUES(Parent), "+
+			"Address = VALUES(Address), Instance = VALUES(Instance), Declared = VALUES(Declared), Points = VALUES(Points)",
+			projectID, d.Name, d.Kind, nullString(d.NetworkType), nullString(d.Network), nullString(d.Parent), nullString(d.Address), d.Instance, d.Declared, d.Points)
+		if err != nil {
+			return translate(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// deleteGone deletes the rows of a project whose key is not kept. The list
+// query selects the ID and key of every row.
+func deleteGone(ctx context.Context, tx *sql.Tx, list, del string, projectID int, keep func(key string) bool) error {
+	rows, err := tx.QueryContext(ctx, list, projectID)
+	if err != nil {
+		return err
+	}
+	var gone []int
+	for rows.Next() {
+		var (
+			id  int
+			key string
+		)
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return err
+		}
+		if !keep(key) {
+			gone = append(gone, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range gone {
+		if _, err := tx.ExecContext(ctx, del, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type projectStore struct {
+	db *sql.DB
+}
+
+func (s *projectStore) List(ctx context.Context) ([]models.Project, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT p.id, p.name, p.title, p.sapnumber, p.notes, p.branchid, p.statusid, s.id, s.name "+
+		"FROM projects p "+
+		"JOIN services s ON p.serviceid = s.id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var project models.Project
+		err := rows.Scan(&project.ID, &project.Name, &project.Title, &project.SAPNumber, &project.Notes, &project.BranchID, &project.StatusID, &project.Service.ID, &project.Service.Name)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+func (s *projectStore) Get(ctx context.Context, id int) (models.Project, error) {
+	var project models.Project
+	err := s.db.QueryRowContext(ctx, "SELECT p.id, p.name, p.title, p.date, p.sapnumber, p.notes, p.branchId, p.statusId, p.serviceId, s.name FROM projects p JOIN services s ON p.serviceId = s.id WHERE p.id = ?", id).
+		Scan(&project.ID, &project.Name, &project.Title, &project.Date, &project.SAPNumber, &project.Notes, &project.BranchID, &project.StatusID, &project.Service.ID, &project.Service.Name)
+	if err != nil {
+		return project, translate(err)
+	}
+
+	return project, nil
+}
+
+func (s *projectStore) Create(ctx context.Context, project models.Project) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO projects (id, name, title, date, sapnumber, notes, branchId, statusId, serviceId) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		project.ID, project.Name, project.Title, project.Date, project.SAPNumber, project.Notes, project.BranchID, project.StatusID, project.Service.ID)
+	return translate(err)
+}
+
+func (s *projectStore) Update(ctx context.Context, project models.Project) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE projects SET name = ?, title = ?, sapnumber = ?, notes = ?, branchId = ?, statusId = ?, serviceId = ? WHERE id = ?",
+		project.Name, project.Title, project.SAPNumber, project.Notes, project.BranchID, project.StatusID, project.Service.ID, project.ID)
+	if err != nil {
+		return translate(err)
+	}
+
+	// MySQL reports 0 affected rows when nothing changed, so only treat it
+	// as missing if the row really is not there.
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM projects WHERE id = ?)", project.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *projectStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM projects WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+type serviceStore struct {
+	db *sql.DB
+}
+
+func (s *serviceStore) Get(ctx context.Context, id int) (models.Service, error) {
+	var service models.Service
+	err := s.db.QueryRowContext(ctx, "SELECT id, name FROM services WHERE id = ?", id).Scan(&service.ID, &service.Name)
+	if err != nil {
+		return service, translate(err)
+	}
+
+	return service, nil
+}
+
+func (s *serviceStore) Upsert(ctx context.Context, service models.Service) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO services (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = ?", service.ID, service.Name, service.Name)
+	return err
+}
@@ -0,0 +1,51 @@
+// Package sqlstore implements the store interfaces on top of MySQL.
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+// MySQL error numbers translated into store errors.
+const (
+	errDupEntry        = 1062
+	errNoReferencedRow = 1452
+)
+
+// New returns a store.Store backed by the given connection pool.
+func New(db *sql.DB) store.Store {
+	return store.Store{
+		Projects:   &projectStore{db: db},
+		Services:   &serviceStore{db: db},
+		DataPoints: &dataPointStore{db: db},
+		Imports:    &importStore{db: db},
+		Units:      &unitStore{db: db},
+		Naming:     &namingStore{db: db},
+		Equipment:  &equipmentStore{db: db},
+		Networks:   &networkStore{db: db},
+		Tags:       &tagStore{db: db},
+		Templates:  &templateStore{db: db},
+	}
+}
+
+// translate maps driver errors onto the store error values.
+func translate(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.ErrNotFound
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case errDupEntry:
+			return store.ErrConflict
+		case errNoReferencedRow:
+			return store.ErrNotFound
+		}
+	}
+
+	return err
+}
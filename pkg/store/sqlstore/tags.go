@@ -0,0 +1,132 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+type tagStore struct {
+	db *sql.DB
+}
+
+func (s *tagStore) GetRules(ctx context.Context, projectID int) (models.TaggingRules, error) {
+	var (
+		rules models.TaggingRules
+		data  sql.NullString
+	)
+
+	err := s.db.QueryRowContext(ctx, "SELECT ProjectId, Rules FROM tagging_rules WHERE ProjectId = ?", projectID).
+		Scan(&rules.ProjectID, &data)
+	if err != nil {
+		return rules, translate(err)
+	}
+
+	rules.Rules = []models.TagRule{}
+	if data.Valid && data.String != "" {
+		if err := json.Unmarshal([]byte(data.String), &rules.Rules); err != nil {
+			return rules, err
+		}
+	}
+
+	return rules, nil
+}
+
+func (s *tagStore) PutRules(ctx context.Context, rules models.TaggingRules) error {
+	data, err := json.Marshal(rules.Rules)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO tagging_rules (ProjectId, Rules) VALUES (?, ?) "+
+		"ON DUPLICATE KEY UPDATE Rules = VALUES(Rules)",
+		rules.ProjectID, string(data))
+
+	return translate(err)
+}
+
+func (s *tagStore) ListPointTags(ctx context.Context, projectID int) ([]models.PointTags, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DataPointId, ProjectId, AddTags, RemoveTags FROM point_tags WHERE ProjectId = ? ORDER BY DataPointId", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.PointTags{}
+	for rows.Next() {
+		t, err := scanPointTags(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *tagStore) GetPointTags(ctx context.Context, pointID int) (models.PointTags, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT DataPointId, ProjectId, AddTags, RemoveTags FROM point_tags WHERE DataPointId = ?", pointID)
+
+	t, err := scanPointTags(row)
+	if err != nil {
+		return t, translate(err)
+	}
+
+	return t, nil
+}
+
+func (s *tagStore) PutPointTags(ctx context.Context, t models.PointTags) error {
+	// The overrides must belong to a point of the project
+	var id int
+	err := s.db.QueryRowContext(ctx, "SELECT Id FROM datapoints WHERE Id = ? AND ProjectId = ?", t.PointID, t.ProjectID).Scan(&id)
+	if err != nil {
+		return translate(err)
+	}
+
+	if len(t.Add) == 0 && len(t.Remove) == 0 {
+		_, err := s.db.ExecContext(ctx, "DELETE FROM point_tags WHERE DataPointId = ?", t.PointID)
+		return err
+	}
+
+	add, err := json.Marshal(t.Add)
+	if err != nil {
+		return err
+	}
+	remove, err := json.Marshal(t.Remove)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO point_tags (DataPointId, ProjectId, AddTags, RemoveTags) VALUES (?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE AddTags = VALUES(AddTags), RemoveTags = VALUES(RemoveTags)",
+		t.PointID, t.ProjectID, string(add), string(remove))
+
+	return translate(err)
+}
+
+func scanPointTags(row scanner) (models.PointTags, error) {
+	var (
+		t           models.PointTags
+		add, remove sql.NullString
+	)
+	if err := row.Scan(&t.PointID, &t.ProjectID, &add, &remove); err != nil {
+		return t, err
+	}
+
+	t.Add = []string{}
+	t.Remove = []string{}
+	if add.Valid && add.String != "" {
+		if err := json.Unmarshal([]byte(add.String), &t.Add); err != nil {
+			return t, err
+		}
+	}
+	if remove.Valid && remove.String != "" {
+		if err := json.Unmarshal([]byte(remove.String), &t.Remove); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
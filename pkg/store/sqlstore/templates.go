@@ -0,0 +1,127 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type templateStore struct {
+	db *sql.DB
+}
+
+func (s *templateStore) List(ctx context.Context) ([]models.PointTemplate, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, Name, EquipType, Description, Points FROM point_templates ORDER BY Name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.PointTemplate{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *templateStore) Get(ctx context.Context, id int) (models.PointTemplate, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT Id, Name, EquipType, Description, Points FROM point_templates WHERE Id = ?", id)
+
+	t, err := scanTemplate(row)
+	if err != nil {
+		return t, translate(err)
+	}
+
+	return t, nil
+}
+
+func (s *templateStore) Create(ctx context.Context, t *models.PointTemplate) error {
+	points, err := json.Marshal(t.Points)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, "INSERT INTO point_templates (Name, EquipType, Description, Points) VALUES (?, ?, ?, ?)",
+		t.Name, t.EquipType, nullString(t.Description), string(points))
+	if err != nil {
+		return translate(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	t.ID = int(id)
+
+	return nil
+}
+
+func (s *templateStore) Update(ctx context.Context, t models.PointTemplate) error {
+	points, err := json.Marshal(t.Points)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, "UPDATE point_templates SET Name = ?, EquipType = ?, Description = ?, Points = ? WHERE Id = ?",
+		t.Name, t.EquipType, nullString(t.Description), string(points), t.ID)
+	if err != nil {
+		return translate(err)
+	}
+
+	// MySQL reports 0 affected rows when nothing changed, so only treat it
+	// as missing if the row really is not there.
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM point_templates WHERE Id = ?)", t.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return store.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *templateStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM point_templates WHERE Id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanTemplate(row scanner) (models.PointTemplate, error) {
+	var (
+		t           models.PointTemplate
+		description sql.NullString
+		points      sql.NullString
+	)
+	if err := row.Scan(&t.ID, &t.Name, &t.EquipType, &description, &points); err != nil {
+		return t, err
+	}
+
+	t.Description = description.String
+	t.Points = []models.TemplatePoint{}
+	if points.Valid && points.String != "" {
+		if err := json.Unmarshal([]byte(points.String), &t.Points); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,101 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+)
+
+type unitStore struct {
+	db *sql.DB
+}
+
+func (s *unitStore) List(ctx context.Context) ([]models.Unit, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, Name, Symbol FROM units ORDER BY Id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := []models.Unit{}
+	for rows.Next() {
+		u, err := scanUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, u)
+	}
+
+	return units, rows.Err()
+}
+
+func (s *unitStore) Get(ctx context.Context, id int) (models.Unit, error) {
+	u, err := scanUnit(s.db.QueryRowContext(ctx, "SELECT Id, Name, Symbol FROM units WHERE Id = ?", id))
+	if err != nil {
+		return u, translate(err)
+	}
+
+	return u, nil
+}
+
+func scanUnit(row scanner) (models.Unit, error) {
+	var (
+		u      models.Unit
+		symbol sql.NullString
+	)
+	if err := row.Scan(&u.ID, &u.Name, &symbol); err != nil {
+		return u, err
+	}
+	u.Symbol = symbol.String
+
+	return u, nil
+}
+
+func (s *unitStore) ListAliases(ctx context.Context) ([]models.UnitAlias, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT Id, Alias, UnitId FROM unit_aliases ORDER BY Alias")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []models.UnitAlias{}
+	for rows.Next() {
+		var a models.UnitAlias
+		if err := rows.Scan(&a.ID, &a.Alias, &a.UnitID); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, a)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (s *unitStore) CreateAlias(ctx context.Context, alias *models.UnitAlias) error {
+	res, err := s.db.ExecContext(ctx, "INSERT INTO unit_aliases (Alias, UnitId) VALUES (?, ?)", alias.Alias, alias.UnitID)
+	if err != nil {
+		return translate(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	alias.ID = int(id)
+
+	return nil
+}
+
+func (s *unitStore) DeleteAlias(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM unit_aliases WHERE Id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+// Package store defines the persistence interfaces used by the controllers.
+//
+// The sqlstore package implements them on top of MySQL and the memstore
+// package keeps everything in memory, which is what the handler tests and
+// local development without a database use.
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("store: not found")
+
+	// ErrConflict is returned when a record with the same key already exists.
+	ErrConflict = errors.New("store: conflict")
+)
+
+// ProjectStore persists projects. Projects are returned with their service
+// joined in.
+type ProjectStore interface {
+	List(ctx context.Context) ([]models.Project, error)
+	Get(ctx context.Context, id int) (models.Project, error)
+	Create(ctx context.Context, project models.Project) error
+	Update(ctx context.Context, project models.Project) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ServiceStore persists services.
+type ServiceStore interface {
+	Get(ctx context.Context, id int) (models.Service, error)
+	// Upsert inserts the service or renames it if the ID already exists.
+	Upsert(ctx context.Context, service models.Service) error
+}
+
+// ImportMode selects what happens to a project's existing datapoints when a
+// point list is imported.
+type ImportMode int
+
+const (
+	// ImportAppend adds the imported points to the existing ones.
+	ImportAppend ImportMode = iota
+	// ImportReplace deletes the existing points and loads the imported ones
+	// in their place.
+	ImportReplace
+)
+
+// ImportOptions controls how DataPointStore.Import stores a point list.
+type ImportOptions struct {
+	Mode ImportMode
+
+	// Progress, if set, is called with the number of rows written so far
+	// as the import advances.
+	Progress func(rows int)
+}
+
+// DataPointQuery selects, orders and pages the datapoints of a project.
+type DataPointQuery struct {
+	// Equals holds column values points must match, in the form
+	// models.DataPoint.Get returns. Text is compared ignoring case.
+	Equals map[string]string
+
+	// Search matches points whose PointName or Descriptor contains it,
+	// ignoring case.
+	Search string
+
+	// Sort is the column to order by, Id when empty. Ties are broken by Id.
+	Sort string
+	Desc bool
+
+	// Offset skips that many matching points. Limit caps the page size;
+	// zero means no cap.
+	Offset int
+	Limit  int
+}
+
+// DataPointStore persists the datapoints of a project.
+type DataPointStore interface {
+	ListByProject(ctx context.Context, projectID int) ([]models.DataPoint, error)
+	// Query returns a page of the points matching q along with the number
+	// of matching points overall.
+	Query(ctx context.Context, projectID int, q DataPointQuery) ([]models.DataPoint, int, error)
+	Get(ctx context.Context, projectID, id int) (models.DataPoint, error)
+	// Create stores a new datapoint and sets its ID.
+	Create(ctx context.Context, dp *models.DataPoint) error
+	// Update replaces every column of an existing datapoint.
+	Update(ctx context.Context, dp models.DataPoint) error
+	// UpdateMany replaces every column of existing datapoints. Either every
+	// point is stored or, on error, none.
+	UpdateMany(ctx context.Context, points []models.DataPoint) error
+	Delete(ctx context.Context, projectID, id int) error
+	// Import adds one datapoint per row, each row holding the values of
+	// columns in order. Either every row is stored or, on error, none.
+	Import(ctx context.Context, projectID int, columns []string, rows [][]string, opts ImportOptions) error
+}
+
+// ImportStore records point list imports.
+type ImportStore interface {
+	// Create stores a new import and sets its ID.
+	Create(ctx context.Context, imp *models.Import) error
+	// Update stores the status, row counts and errors of an import.
+	Update(ctx context.Context, imp models.Import) error
+	// UpdateProgress stores the number of rows written so far.
+	UpdateProgress(ctx context.Context, id int, processedRows int) error
+	Get(ctx context.Context, id int) (models.Import, error)
+	// ListByProject returns the imports of a project, newest first.
+	ListByProject(ctx context.Context, projectID int) ([]models.Import, error)
+	// ListByStatus returns the imports in any of the given statuses, oldest
+	// first.
+	ListByStatus(ctx context.Context, statuses ...string) ([]models.Import, error)
+}
+
+// UnitStore holds the engineering units catalogue and its aliases.
+type UnitStore interface {
+	// List returns the catalogue ordered by ID.
+	List(ctx context.Context) ([]models.Unit, error)
+	Get(ctx context.Context, id int) (models.Unit, error)
+	// ListAliases returns the aliases ordered by alias.
+	ListAliases(ctx context.Context) ([]models.UnitAlias, error)
+	// CreateAlias stores a new alias and sets its ID. ErrConflict is returned
+	// for an alias that already exists, ErrNotFound for an unknown unit.
+	CreateAlias(ctx context.Context, alias *models.UnitAlias) error
+	DeleteAlias(ctx context.Context, id int) error
+}
+
+// NamingStore holds the naming conventions of projects.
+type NamingStore interface {
+	// Get returns the convention of a project, ErrNotFound if it has none.
+	Get(ctx context.Context, projectID int) (models.NamingConvention, error)
+	// Put creates or replaces the convention of a project.
+	Put(ctx context.Context, nc models.NamingConvention) error
+}
+
+// EquipmentStore holds the equipment derived from the datapoints of projects.
+type EquipmentStore interface {
+	// ListByProject returns the equipment of a project ordered by EquipID.
+	ListByProject(ctx context.Context, projectID int) ([]models.Equipment, error)
+	// Sync makes list the equipment of a project. Equipment keeps its ID
+	// while its EquipID stays in the list.
+	Sync(ctx context.Context, projectID int, list []models.Equipment) error
+}
+
+// NetworkStore holds the networks and devices derived from the datapoints
+// of projects, and the devices declared ahead of their points.
+type NetworkStore interface {
+	// ListNetworks returns the networks of a project ordered by type and
+	// name.
+	ListNetworks(ctx context.Context, projectID int) ([]models.Network, error)
+	// ListDevices returns the devices of a project ordered by name.
+	ListDevices(ctx context.Context, projectID int) ([]models.Device, error)
+	// CreateDevice declares a device and sets its ID. ErrConflict is
+	// returned for a name the project already uses.
+	CreateDevice(ctx context.Context, d *models.Device) error
+	DeleteDevice(ctx context.Context, projectID, id int) error
+	// Sync makes networks and devices those of a project. Rows keep their
+	// ID while their type and name, or name, stay in the lists.
+	Sync(ctx context.Context, projectID int, networks []models.Network, devices []models.Device) error
+}
+
+// TagStore holds the Haystack tagging rules of projects and the tag
+// overrides of their datapoints.
+type TagStore interface {
+	// GetRules returns the rules of a project, ErrNotFound if it has none.
+	GetRules(ctx context.Context, projectID int) (models.TaggingRules, error)
+	// PutRules creates or replaces the rules of a project.
+	PutRules(ctx context.Context, rules models.TaggingRules) error
+	// ListPointTags returns the overrides of the datapoints of a project
+	// ordered by point.
+	ListPointTags(ctx context.Context, projectID int) ([]models.PointTags, error)
+	// GetPointTags returns the overrides of a datapoint, ErrNotFound if it
+	// has none.
+	GetPointTags(ctx context.Context, pointID int) (models.PointTags, error)
+	// PutPointTags creates or replaces the overrides of a datapoint. Empty
+	// overrides are removed.
+	PutPointTags(ctx context.Context, tags models.PointTags) error
+}
+
+// TemplateStore holds the point template library.
+type TemplateStore interface {
+	// List returns the templates ordered by name.
+	List(ctx context.Context) ([]models.PointTemplate, error)
+	Get(ctx context.Context, id int) (models.PointTemplate, error)
+	// Create stores a new template and sets its ID. ErrConflict is returned
+	// for a name that is already used.
+	Create(ctx context.Context, t *models.PointTemplate) error
+	Update(ctx context.Context, t models.PointTemplate) error
+	Delete(ctx context.Context, id int) error
+}
+
+// Store groups the stores used by the API.
+type Store struct {
+	Projects   ProjectStore
+	Services   ServiceStore
+	DataPoints DataPointStore
+	Imports    ImportStore
+	Units      UnitStore
+	Naming     NamingStore
+	Equipment  EquipmentStore
+	Networks   NetworkStore
+	Tags       TagStore
+	Templates  TemplateStore
+}
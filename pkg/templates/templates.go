@@ -0,0 +1,184 @@
+// Package templates checks point templates and stamps equipment out of them.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/trends"
+)
+
+// MaxCount is the largest number of pieces of equipment stamped at once.
+const MaxCount = 500
+
+// Check trims the text of t and reports what is wrong with it, if anything.
+func Check(t *models.PointTemplate) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.EquipType = strings.TrimSpace(t.EquipType)
+
+	var errs []string
+	if t.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if t.EquipType == "" {
+		errs = append(errs, "equipType is required")
+	}
+	if len(t.Points) == 0 {
+		errs = append(errs, "at least one point is required")
+	}
+
+	seen := make(map[string]int, len(t.Points))
+	for i := range t.Points {
+		p := &t.Points[i]
+		p.Function = strings.TrimSpace(p.Function)
+		if p.Function == "" {
+			errs = append(errs, fmt.Sprintf("point %d: Function is required", i+1))
+			continue
+		}
+		key := strings.ToLower(p.Function)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Sprintf("point %d: Function %q is already used by point %d", i+1, p.Function, first))
+			continue
+		}
+		seen[key] = i + 1
+
+		// The values must fit the datapoints columns they are copied into
+		dp, err := Point(*t, *p, "X")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("point %d: trend: %v", i+1, err))
+			continue
+		}
+		if err := dp.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("point %d: %v", i+1, err))
+		}
+		for _, msg := range trends.Check(dp, trends.DefaultLimits) {
+			errs = append(errs, fmt.Sprintf("point %d: %s", i+1, msg))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Point returns the datapoint of p for the equipment equipID. It fails when
+// the trend configuration of p cannot be written to the trend columns.
+func Point(t models.PointTemplate, p models.TemplatePoint, equipID string) (models.DataPoint, error) {
+	included := true
+	dp := models.DataPoint{
+		EquipID:           equipID,
+		EquipType:         t.EquipType,
+		Function:          p.Function,
+		Descriptor:        p.Descriptor,
+		PointType:         p.PointType,
+		EngineeringUnits:  p.EngineeringUnits,
+		BACnetObjectType:  p.BACnetObjectType,
+		IncludedInProject: &included,
+	}
+
+	if p.Trend != nil {
+		// Apply normalizes what it is given, the template stays as it is
+		cfg := models.TrendConfig{Trends: append([]models.TrendDefinition(nil), p.Trend.Trends...)}
+		if p.Trend.COV != nil {
+			cov := *p.Trend.COV
+			cfg.COV = &cov
+		}
+		if err := trends.Apply(&dp, &cfg); err != nil {
+			return dp, err
+		}
+	}
+
+	return dp, nil
+}
+
+// Stamp describes the equipment to stamp out of a template.
+//
+// swagger:model
+type Stamp struct {
+	// The number of pieces of equipment.
+	//
+	// required: true
+	// example: 12
+	Count int `json:"count"`
+
+	// The number of the first piece of equipment, 1 by default.
+	//
+	// example: 1
+	Start int `json:"start"`
+
+	// Put in front of the number to form the EquipID, the template's
+	// EquipType and a dash by default.
+	//
+	// example: VAV-1-
+	Prefix string `json:"prefix"`
+
+	// The minimum number of digits, padded with zeros.
+	//
+	// example: 2
+	Digits int `json:"digits"`
+
+	// The System of the points.
+	//
+	// example: HVAC
+	System string `json:"system"`
+
+	// The EquipRef of the points: the equipment feeding the new equipment.
+	//
+	// example: AHU-1
+	EquipRef string `json:"equipRef"`
+}
+
+// Defaults fills the unset options of s from t and checks them.
+func (s *Stamp) Defaults(t models.PointTemplate) error {
+	if s.Count < 1 || s.Count > MaxCount {
+		return fmt.Errorf("count must be between 1 and %d", MaxCount)
+	}
+	if s.Start == 0 {
+		s.Start = 1
+	}
+	if s.Start < 0 {
+		return errors.New("start must not be negative")
+	}
+	if s.Digits < 0 || s.Digits > 9 {
+		return errors.New("digits must be between 0 and 9")
+	}
+	if s.Prefix == "" {
+		s.Prefix = t.EquipType + "-"
+	}
+	return nil
+}
+
+// EquipIDs returns the EquipIDs of the stamped equipment.
+func (s Stamp) EquipIDs() []string {
+	ids := make([]string, s.Count)
+	for i := range ids {
+		n := strconv.Itoa(s.Start + i)
+		if pad := s.Digits - len(n); pad > 0 {
+			n = strings.Repeat("0", pad) + n
+		}
+		ids[i] = s.Prefix + n
+	}
+	return ids
+}
+
+// Points returns the datapoints of the stamped equipment, equipment by
+// equipment in template order. PointName is left to the caller.
+func (s Stamp) Points(t models.PointTemplate) ([]models.DataPoint, error) {
+	var points []models.DataPoint
+	for _, equipID := range s.EquipIDs() {
+		for _, p := range t.Points {
+			dp, err := Point(t, p, equipID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: trend: %v", p.Function, err)
+			}
+			dp.System = s.System
+			dp.EquipRef = s.EquipRef
+			points = append(points, dp)
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,255 @@
+// Package topology derives the networks and devices of a project from the
+// addressing columns of its datapoints and arranges them into a tree: site,
+// network, panel, field device, points.
+//
+// A point belongs to its field device, FLNdeviceSysName, or failing that to
+// its panel, DeviceSysName or NodeIdentifier. Panels are on the BLN named by
+// BLNSysName or on a BACnet/IP network; field devices are on an MS/TP
+// network or on the FLN trunk of their panel.
+//
+// The networks and devices tables are kept in step with the datapoints by
+// calling Sync whenever the points of a project change.
+package topology
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+	"github.com/pufington-pixie/haver/pkg/store"
+	"github.com/pufington-pixie/haver/pkg/trends"
+	"github.com/pufington-pixie/haver/utils"
+)
+
+// Derive returns the networks and devices of a project's points together
+// with its declared devices, ordered by type and name. Network, Address and
+// Instance of a device take the most common value of its points; declared
+// values fill in what the points leave open. A field device named like a
+// panel is taken for the panel.
+func Derive(points []models.DataPoint, declared []models.Device) ([]models.Network, []models.Device) {
+	type tally struct {
+		device    models.Device
+		networks  utils.Counter
+		parents   utils.Counter
+		addresses utils.Counter
+		instances utils.Counter
+	}
+
+	var order []string
+	byKey := make(map[string]*tally)
+	get := func(projectID int, name, kind string) *tally {
+		key := strings.ToLower(name)
+		t, ok := byKey[key]
+		if !ok {
+			t = &tally{device: models.Device{ProjectID: projectID, Name: name, Kind: kind}}
+			byKey[key] = t
+			order = append(order, key)
+		}
+		return t
+	}
+
+	// Panels first, so that a field device named like a panel is found to be
+	// one whatever the order of the points
+	for _, dp := range points {
+		if panel := trends.Panel(dp); panel != "" {
+			get(dp.ProjectID, panel, models.DevicePanel)
+		}
+	}
+
+	for _, dp := range points {
+		panel := trends.Panel(dp)
+		field := strings.TrimSpace(dp.FLNdeviceSysName)
+
+		var owner *tally
+		if panel != "" {
+			owner = get(dp.ProjectID, panel, models.DevicePanel)
+			owner.networks.Add(panelNetwork(dp))
+			owner.addresses.Add(panelAddress(dp))
+		}
+		if t, ok := byKey[strings.ToLower(field)]; ok && t.device.Kind == models.DevicePanel {
+			// Device names are unique within a project, so the points of the
+			// field device count for the panel; conflicts reports the clash
+			owner = t
+		} else if field != "" {
+			owner = get(dp.ProjectID, field, models.DeviceField)
+			owner.networks.Add(fieldNetwork(dp, panel))
+			owner.parents.Add(panel)
+			owner.addresses.Add(dp.MSTPaddress)
+		}
+		if owner == nil {
+			continue
+		}
+		owner.device.Points++
+		owner.instances.Add(dp.BACnetDeviceInstance)
+	}
+
+	devices := make([]models.Device, 0, len(byKey)+len(declared))
+	for _, key := range order {
+		t := byKey[key]
+		d := t.device
+		if network := t.networks.Top(); network != "" {
+			d.NetworkType, d.Network = splitNetwork(network)
+		}
+		if d.Kind == models.DeviceField {
+			d.Parent = t.parents.Top()
+			// Spell a known panel the way its own points do
+			if parent, ok := byKey[strings.ToLower(d.Parent)]; ok {
+				d.Parent = parent.device.Name
+			}
+		}
+		d.Address = t.addresses.Top()
+		if n, err := strconv.Atoi(t.instances.Top()); err == nil {
+			d.Instance = &n
+		}
+		devices = append(devices, d)
+	}
+
+	for _, dec := range declared {
+		i := indexOf(devices, dec.Name)
+		if i < 0 {
+			dec.ID, dec.Points, dec.Declared = 0, 0, true
+			devices = append(devices, dec)
+			continue
+		}
+		d := &devices[i]
+		d.Declared = true
+		if d.Network == "" {
+			d.NetworkType, d.Network = dec.NetworkType, dec.Network
+		}
+		if d.Parent == "" && d.Kind == models.DeviceField {
+			d.Parent = dec.Parent
+		}
+		if d.Address == "" {
+			d.Address = dec.Address
+		}
+		if d.Instance == nil {
+			d.Instance = dec.Instance
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return strings.ToLower(devices[i].Name) < strings.ToLower(devices[j].Name)
+	})
+
+	networks := []models.Network{}
+	seen := make(map[string]int)
+	for _, d := range devices {
+		if d.Network == "" {
+			continue
+		}
+		key := d.NetworkType + "\x00" + strings.ToLower(d.Network)
+		i, ok := seen[key]
+		if !ok {
+			i = len(networks)
+			seen[key] = i
+			networks = append(networks, models.Network{ProjectID: d.ProjectID, Type: d.NetworkType, Name: d.Network})
+		}
+		networks[i].Devices++
+		networks[i].Points += d.Points
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		if networks[i].Type != networks[j].Type {
+			return networks[i].Type < networks[j].Type
+		}
+		return strings.ToLower(networks[i].Name) < strings.ToLower(networks[j].Name)
+	})
+
+	return networks, devices
+}
+
+// Owner returns the name of the device a point belongs to, "" if none.
+func Owner(dp models.DataPoint) string {
+	if field := strings.TrimSpace(dp.FLNdeviceSysName); field != "" {
+		return field
+	}
+	return trends.Panel(dp)
+}
+
+// panelNetwork returns the network of the panel of a point as type and name
+// joined by networkSep, "" when the point does not tell.
+func panelNetwork(dp models.DataPoint) string {
+	if name := strings.TrimSpace(dp.BLNSysName); name != "" {
+		return models.NetworkBLN + networkSep + name
+	}
+	if strings.TrimSpace(dp.IPaddress) != "" {
+		name := "IP"
+		if dp.BACnetNetwork != nil {
+			name = strconv.Itoa(*dp.BACnetNetwork)
+		}
+		return models.NetworkBACnetIP + networkSep + name
+	}
+	return ""
+}
+
+// fieldNetwork returns the network of the field device of a point, the way
+// panelNetwork does.
+func fieldNetwork(dp models.DataPoint, panel string) string {
+	if name := strings.TrimSpace(dp.MSTPnetwork); name != "" {
+		return models.NetworkMSTP + networkSep + name
+	}
+	if panel == "" {
+		return ""
+	}
+	if strings.TrimSpace(dp.MSTPaddress) != "" {
+		return models.NetworkMSTP + networkSep + panel + " MS/TP"
+	}
+	return models.NetworkFLN + networkSep + panel + " FLN"
+}
+
+// panelAddress returns the IP address and port of a panel, or its node
+// number.
+func panelAddress(dp models.DataPoint) string {
+	if ip := strings.TrimSpace(dp.IPaddress); ip != "" {
+		for _, port := range []string{dp.UDPport, dp.IPport} {
+			if port = strings.TrimSpace(port); port != "" {
+				return ip + ":" + port
+			}
+		}
+		return ip
+	}
+	if dp.InsightNodeNumber != nil {
+		return strconv.Itoa(*dp.InsightNodeNumber)
+	}
+	return ""
+}
+
+const networkSep = "\x00"
+
+func splitNetwork(s string) (typ, name string) {
+	typ, name, _ = strings.Cut(s, networkSep)
+	return typ, name
+}
+
+func indexOf(devices []models.Device, name string) int {
+	for i, d := range devices {
+		if strings.EqualFold(d.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Sync derives the networks and devices of a project from its datapoints
+// and its declared devices and stores them.
+func Sync(ctx context.Context, s store.Store, projectID int) error {
+	points, err := s.DataPoints.ListByProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	devices, err := s.Networks.ListDevices(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	var declared []models.Device
+	for _, d := range devices {
+		if d.Declared {
+			declared = append(declared, d)
+		}
+	}
+
+	networks, devices := Derive(points, declared)
+	return s.Networks.Sync(ctx, projectID, networks, devices)
+}
@@ -0,0 +1,40 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestDerive(t *testing.T) {
+	instance := 2001
+	points := []models.DataPoint{
+		// A field device named like a panel, seen before the panel, is the
+		// panel
+		{ID: 1, DeviceSysName: "PXC-1", BLNSysName: "BLN1", FLNdeviceSysName: "pxc-2", MSTPaddress: "5"},
+		{ID: 2, DeviceSysName: "PXC-1", BLNSysName: "BLN1", FLNdeviceSysName: "TEC-1", MSTPnetwork: "FLN1", MSTPaddress: "3"},
+		{ID: 3, DeviceSysName: "PXC-2", IPaddress: "10.0.0.21", UDPport: "47808", BACnetDeviceInstance: "2001"},
+		{ID: 4},
+	}
+
+	networks, devices := Derive(points, nil)
+
+	wantDevices := []models.Device{
+		{Name: "PXC-1", Kind: models.DevicePanel, NetworkType: models.NetworkBLN, Network: "BLN1"},
+		{Name: "PXC-2", Kind: models.DevicePanel, NetworkType: models.NetworkBACnetIP, Network: "IP", Address: "10.0.0.21:47808", Instance: &instance, Points: 2},
+		{Name: "TEC-1", Kind: models.DeviceField, NetworkType: models.NetworkMSTP, Network: "FLN1", Parent: "PXC-1", Address: "3", Points: 1},
+	}
+	if !reflect.DeepEqual(devices, wantDevices) {
+		t.Errorf("devices = %+v, want %+v", devices, wantDevices)
+	}
+
+	wantNetworks := []models.Network{
+		{Type: models.NetworkBACnetIP, Name: "IP", Devices: 1, Points: 2},
+		{Type: models.NetworkBLN, Name: "BLN1", Devices: 1},
+		{Type: models.NetworkMSTP, Name: "FLN1", Devices: 1, Points: 1},
+	}
+	if !reflect.DeepEqual(networks, wantNetworks) {
+		t.Errorf("networks = %+v, want %+v", networks, wantNetworks)
+	}
+}
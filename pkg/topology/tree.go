@@ -0,0 +1,110 @@
+package topology
+
+import (
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Site is the root of a project's topology.
+type Site struct {
+	Name     string         `json:"name"`
+	Networks []*NetworkNode `json:"networks"`
+	// UnassignedPoints are the points that belong to no device.
+	UnassignedPoints []Point `json:"unassignedPoints"`
+	// UnusedDevices are the devices without points or field devices.
+	UnusedDevices []models.Device `json:"unusedDevices"`
+}
+
+// NetworkNode is a network with the devices on it. Field devices are nested
+// under their panel, so a trunk only has devices of its own when their panel
+// is unknown. Devices on no known network are under a network without type
+// or name.
+type NetworkNode struct {
+	models.Network
+	Devices []*DeviceNode `json:"devices"`
+}
+
+// DeviceNode is a device with its field devices and points.
+type DeviceNode struct {
+	models.Device
+	Devices []*DeviceNode `json:"devices"`
+	Points  []Point       `json:"points"`
+}
+
+// Point identifies a datapoint within the tree.
+type Point struct {
+	ID        int    `json:"id"`
+	EquipID   string `json:"EquipID"`
+	PointName string `json:"point_name"`
+}
+
+// Tree arranges the networks, devices and points of a project.
+func Tree(project models.Project, networks []models.Network, devices []models.Device, points []models.DataPoint) *Site {
+	site := &Site{
+		Name:             project.Name,
+		Networks:         []*NetworkNode{},
+		UnassignedPoints: []Point{},
+		UnusedDevices:    []models.Device{},
+	}
+
+	nodes := make(map[string]*DeviceNode, len(devices))
+	for _, d := range devices {
+		nodes[strings.ToLower(d.Name)] = &DeviceNode{Device: d, Devices: []*DeviceNode{}, Points: []Point{}}
+	}
+
+	for _, dp := range points {
+		p := Point{ID: dp.ID, EquipID: dp.EquipID, PointName: dp.PointName}
+		if node, ok := nodes[strings.ToLower(Owner(dp))]; ok {
+			node.Points = append(node.Points, p)
+			continue
+		}
+		site.UnassignedPoints = append(site.UnassignedPoints, p)
+	}
+
+	byNetwork := make(map[string]*NetworkNode, len(networks))
+	for _, n := range networks {
+		node := &NetworkNode{Network: n, Devices: []*DeviceNode{}}
+		byNetwork[n.Type+"\x00"+strings.ToLower(n.Name)] = node
+	}
+	// Networks the devices are on but the list lacks, in order of appearance
+	var extra []*NetworkNode
+	network := func(d models.Device) *NetworkNode {
+		key := d.NetworkType + "\x00" + strings.ToLower(d.Network)
+		node, ok := byNetwork[key]
+		if !ok {
+			node = &NetworkNode{Network: models.Network{ProjectID: d.ProjectID, Type: d.NetworkType, Name: d.Network}, Devices: []*DeviceNode{}}
+			byNetwork[key] = node
+			extra = append(extra, node)
+		}
+		return node
+	}
+
+	for _, d := range devices {
+		node := nodes[strings.ToLower(d.Name)]
+		if d.Kind == models.DeviceField {
+			if panel, ok := nodes[strings.ToLower(d.Parent)]; ok && panel != node {
+				panel.Devices = append(panel.Devices, node)
+				continue
+			}
+		}
+		n := network(d)
+		n.Devices = append(n.Devices, node)
+	}
+
+	// A panel whose points are all on its field devices is in use
+	for _, d := range devices {
+		if node := nodes[strings.ToLower(d.Name)]; len(node.Points) == 0 && len(node.Devices) == 0 {
+			site.UnusedDevices = append(site.UnusedDevices, d)
+		}
+	}
+
+	for _, n := range networks {
+		if node := byNetwork[n.Type+"\x00"+strings.ToLower(n.Name)]; len(node.Devices) > 0 {
+			site.Networks = append(site.Networks, node)
+		}
+	}
+	site.Networks = append(site.Networks, extra...)
+
+	return site
+}
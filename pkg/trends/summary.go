@@ -0,0 +1,140 @@
+package trends
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// PanelLoad is the trend load of the points of one panel.
+type PanelLoad struct {
+	// Panel is the DeviceSysName of the points, "Node n" for points with
+	// only a NodeIdentifier and "" for points assigned to no panel.
+	Panel         string `json:"panel"`
+	Points        int    `json:"points"`
+	TrendedPoints int    `json:"trendedPoints"`
+	COVPoints     int    `json:"covPoints"`
+	Definitions   int    `json:"definitions"`
+	// PanelSamples is the number of samples buffered in the panel.
+	PanelSamples int `json:"panelSamples"`
+	// SamplesPerDay counts the interval samples only; COV samples depend
+	// on how the value moves.
+	SamplesPerDay float64 `json:"samplesPerDay"`
+	Capacity      int     `json:"capacity"`
+	OverCapacity  bool    `json:"overCapacity"`
+	// InvalidPoints are the points whose trend columns cannot be read.
+	InvalidPoints []int `json:"invalidPoints"`
+}
+
+// Summary is the trend load of a project.
+type Summary struct {
+	Points        int         `json:"points"`
+	TrendedPoints int         `json:"trendedPoints"`
+	Definitions   int         `json:"definitions"`
+	PanelSamples  int         `json:"panelSamples"`
+	SamplesPerDay float64     `json:"samplesPerDay"`
+	Panels        []PanelLoad `json:"panels"`
+}
+
+// Load is the trend load of a single point.
+type Load struct {
+	Trended     bool
+	COV         bool
+	Definitions int
+	// PanelSamples is the number of samples buffered in the panel.
+	PanelSamples int
+	// SamplesPerDay counts the interval samples only.
+	SamplesPerDay float64
+}
+
+// PointLoad returns the trend load of a point with the trend configuration
+// cfg. Every estimate of panel and trunk load starts from it.
+func PointLoad(cfg models.TrendConfig) Load {
+	l := Load{Trended: cfg.COV != nil || len(cfg.Trends) > 0, COV: cfg.COV != nil}
+	if cfg.COV != nil {
+		l.PanelSamples += cfg.COV.PanelSamples
+	}
+	for _, t := range cfg.Trends {
+		l.Definitions++
+		l.PanelSamples += t.PanelSamples
+		if t.IntervalSeconds > 0 {
+			l.SamplesPerDay += 86400 / float64(t.IntervalSeconds)
+		}
+	}
+	return l
+}
+
+// Panel returns the panel a point is assigned to: its DeviceSysName, or its
+// NodeIdentifier when it has no system name.
+func Panel(dp models.DataPoint) string {
+	if name := strings.TrimSpace(dp.DeviceSysName); name != "" {
+		return name
+	}
+	if dp.NodeIdentifier != nil {
+		return "Node " + strconv.Itoa(*dp.NodeIdentifier)
+	}
+	return ""
+}
+
+// Summarize adds up the trend load of points per panel. Panels are listed
+// by name, the points without a panel last.
+func Summarize(points []models.DataPoint, limits Limits) Summary {
+	summary := Summary{Points: len(points), Panels: []PanelLoad{}}
+
+	panels := make(map[string]*PanelLoad)
+	var names []string
+	for _, dp := range points {
+		name := Panel(dp)
+		p, ok := panels[strings.ToLower(name)]
+		if !ok {
+			p = &PanelLoad{Panel: name, Capacity: limits.PanelCapacity, InvalidPoints: []int{}}
+			panels[strings.ToLower(name)] = p
+			names = append(names, strings.ToLower(name))
+		}
+		p.Points++
+
+		cfg, errs := Parse(dp)
+		if len(errs) > 0 {
+			p.InvalidPoints = append(p.InvalidPoints, dp.ID)
+		}
+
+		l := PointLoad(cfg)
+		if l.Trended {
+			p.TrendedPoints++
+		}
+		if l.COV {
+			p.COVPoints++
+		}
+		p.Definitions += l.Definitions
+		p.PanelSamples += l.PanelSamples
+		p.SamplesPerDay += l.SamplesPerDay
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "" || names[j] == "" {
+			return names[j] == ""
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		p := panels[name]
+		p.OverCapacity = p.Capacity > 0 && p.PanelSamples > p.Capacity
+		summary.TrendedPoints += p.TrendedPoints
+		summary.Definitions += p.Definitions
+		summary.PanelSamples += p.PanelSamples
+		summary.SamplesPerDay += p.SamplesPerDay
+		p.SamplesPerDay = round(p.SamplesPerDay)
+		summary.Panels = append(summary.Panels, *p)
+	}
+	summary.SamplesPerDay = round(summary.SamplesPerDay)
+
+	return summary
+}
+
+// round rounds a rate to four decimals for display.
+func round(f float64) float64 {
+	return math.Round(f*1e4) / 1e4
+}
@@ -0,0 +1,419 @@
+// Package trends reads and writes the trend configuration of datapoints.
+//
+// The datapoints table flattens a change of value trend and four interval
+// trend definitions into free-text columns. Parse turns them into a
+// models.TrendConfig, Apply writes one back in canonical form and Check
+// validates the values against panel limits.
+package trends
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Slots is the number of interval trend definitions a datapoint holds.
+const Slots = 4
+
+// Limits are what a field panel supports.
+type Limits struct {
+	// MinInterval and MaxInterval bound the sampling interval.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// MaxPanelSamples is the largest sample buffer of one definition.
+	MaxPanelSamples int
+
+	// MaxPCDays is the longest retention on the PC.
+	MaxPCDays int
+
+	// PanelCapacity is the number of samples a panel holds over all of its
+	// points.
+	PanelCapacity int
+}
+
+// DefaultLimits are the limits of a typical field panel.
+var DefaultLimits = Limits{
+	MinInterval:     time.Second,
+	MaxInterval:     24 * time.Hour,
+	MaxPanelSamples: 10000,
+	MaxPCDays:       3650,
+	PanelCapacity:   200000,
+}
+
+// Columns returns the datapoints columns holding the trend configuration.
+func Columns() []string {
+	columns := []string{"COVTrend", "COVLimit", "Collection", "PanelSamples", "PCSamples"}
+	for slot := 1; slot <= Slots; slot++ {
+		columns = append(columns, slotColumns(slot)...)
+	}
+	return columns
+}
+
+// slotColumns returns the interval, collection, panel samples and PC days
+// columns of a definition slot.
+func slotColumns(slot int) []string {
+	n := strconv.Itoa(slot)
+	return []string{"TrendInterval" + n, "Collection" + n, "PanelSamples" + n, "PCDays" + n}
+}
+
+// problem is a trend column of a datapoint that cannot be read, or whose
+// value exceeds the limits.
+type problem struct {
+	column     string
+	message    string
+	unreadable bool
+}
+
+func (p problem) String() string {
+	return p.column + ": " + p.message
+}
+
+// messages returns the problems as "column: message" strings, nil when there
+// are none.
+func messages(problems []problem) []string {
+	var msgs []string
+	for _, p := range problems {
+		msgs = append(msgs, p.String())
+	}
+	return msgs
+}
+
+// Parse reads the trend configuration of dp. Values that cannot be read are
+// left out of the configuration and reported, one message per column.
+func Parse(dp models.DataPoint) (models.TrendConfig, []string) {
+	cfg, problems := parse(dp)
+	return cfg, messages(problems)
+}
+
+func parse(dp models.DataPoint) (models.TrendConfig, []problem) {
+	cfg := models.TrendConfig{Trends: []models.TrendDefinition{}}
+	var problems []problem
+	fail := func(column string, err error) {
+		problems = append(problems, problem{column: column, message: err.Error(), unreadable: true})
+	}
+
+	cov, err := parseSwitch(dp.COVTrend)
+	if err != nil {
+		fail("COVTrend", err)
+	}
+	if cov {
+		c := &models.COVTrend{}
+		if v := strings.TrimSpace(dp.COVLimit); v != "" {
+			limit, err := strconv.ParseFloat(v, 64)
+			if err != nil || limit < 0 {
+				fail("COVLimit", fmt.Errorf("%q is not a positive number", v))
+			} else {
+				c.Limit = &limit
+			}
+		}
+		if c.Collection, err = ParseCollection(dp.Collection); err != nil {
+			fail("Collection", err)
+		}
+		if c.PanelSamples, err = parseCount(dp.PanelSamples); err != nil {
+			fail("PanelSamples", err)
+		}
+		if c.PCSamples, err = parseCount(dp.PCSamples); err != nil {
+			fail("PCSamples", err)
+		}
+		cfg.COV = c
+	}
+
+	for slot := 1; slot <= Slots; slot++ {
+		columns := slotColumns(slot)
+		values := make([]string, len(columns))
+		set := false
+		for i, col := range columns {
+			values[i] = strings.TrimSpace(dp.Get(col))
+			set = set || values[i] != ""
+		}
+		if !set {
+			continue
+		}
+
+		t := models.TrendDefinition{Slot: slot}
+		interval, err := ParseInterval(values[0])
+		if err != nil {
+			fail(columns[0], err)
+		} else {
+			t.Interval = FormatInterval(interval)
+			t.IntervalSeconds = int(interval / time.Second)
+		}
+		if t.Collection, err = ParseCollection(values[1]); err != nil {
+			fail(columns[1], err)
+		}
+		if t.PanelSamples, err = parseCount(values[2]); err != nil {
+			fail(columns[2], err)
+		}
+		if t.PCDays, err = parseCount(values[3]); err != nil {
+			fail(columns[3], err)
+		}
+		cfg.Trends = append(cfg.Trends, t)
+	}
+
+	return cfg, problems
+}
+
+// Check returns a message for every trend value of dp that cannot be read
+// or exceeds the limits.
+func Check(dp models.DataPoint, limits Limits) []string {
+	return messages(check(dp, limits))
+}
+
+// CheckChanged is Check for the trend columns whose value differs between
+// before and dp, so that an edit is not refused over values it leaves alone.
+// A zero before checks every column that is set.
+func CheckChanged(before, dp models.DataPoint, limits Limits) []string {
+	var changed []problem
+	for _, p := range check(dp, limits) {
+		if before.Get(p.column) != dp.Get(p.column) {
+			changed = append(changed, p)
+		}
+	}
+	return messages(changed)
+}
+
+// CheckImport is Check for imported rows. Values that cannot be read are
+// returned as warnings rather than errors: point lists exported from older
+// tools carry them, and a row should not be refused for them. Only values
+// beyond the limits are errors.
+func CheckImport(dp models.DataPoint, limits Limits) (errs, warnings []string) {
+	for _, p := range check(dp, limits) {
+		if p.unreadable {
+			warnings = append(warnings, p.String())
+		} else {
+			errs = append(errs, p.String())
+		}
+	}
+	return errs, warnings
+}
+
+func check(dp models.DataPoint, limits Limits) []problem {
+	cfg, problems := parse(dp)
+	exceeds := func(column, format string, args ...interface{}) {
+		problems = append(problems, problem{column: column, message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.COV != nil && cfg.COV.PanelSamples > limits.MaxPanelSamples {
+		exceeds("PanelSamples", "%d samples, at most %d allowed", cfg.COV.PanelSamples, limits.MaxPanelSamples)
+	}
+	for _, t := range cfg.Trends {
+		columns := slotColumns(t.Slot)
+		interval := time.Duration(t.IntervalSeconds) * time.Second
+		if t.Interval != "" && (interval < limits.MinInterval || interval > limits.MaxInterval) {
+			exceeds(columns[0], "interval must be between %s and %s", FormatInterval(limits.MinInterval), FormatInterval(limits.MaxInterval))
+		}
+		if t.PanelSamples > limits.MaxPanelSamples {
+			exceeds(columns[2], "%d samples, at most %d allowed", t.PanelSamples, limits.MaxPanelSamples)
+		}
+		if t.PCDays > limits.MaxPCDays {
+			exceeds(columns[3], "%d days, at most %d allowed", t.PCDays, limits.MaxPCDays)
+		}
+	}
+
+	return problems
+}
+
+// Apply replaces the trend columns of dp with cfg. Definitions without a
+// slot take the first free one. The values of cfg are normalized in place and
+// its definitions put in slot order.
+func Apply(dp *models.DataPoint, cfg *models.TrendConfig) error {
+	if len(cfg.Trends) > Slots {
+		return fmt.Errorf("at most %d trend definitions are allowed", Slots)
+	}
+
+	used := make(map[int]bool, Slots)
+	for _, t := range cfg.Trends {
+		if t.Slot == 0 {
+			continue
+		}
+		if t.Slot < 0 || t.Slot > Slots {
+			return fmt.Errorf("slot must be between 1 and %d", Slots)
+		}
+		if used[t.Slot] {
+			return fmt.Errorf("slot %d is used twice", t.Slot)
+		}
+		used[t.Slot] = true
+	}
+
+	for i := range cfg.Trends {
+		t := &cfg.Trends[i]
+		for slot := 1; t.Slot == 0; slot++ {
+			if !used[slot] {
+				t.Slot, used[slot] = slot, true
+			}
+		}
+
+		interval, err := ParseInterval(t.Interval)
+		if err != nil {
+			return fmt.Errorf("trend %d: interval: %v", t.Slot, err)
+		}
+		t.Interval = FormatInterval(interval)
+		t.IntervalSeconds = int(interval / time.Second)
+		if t.Collection, err = ParseCollection(t.Collection); err != nil {
+			return fmt.Errorf("trend %d: collection: %v", t.Slot, err)
+		}
+		if t.PanelSamples < 0 || t.PCDays < 0 {
+			return fmt.Errorf("trend %d: counts must not be negative", t.Slot)
+		}
+	}
+
+	if c := cfg.COV; c != nil {
+		var err error
+		if c.Limit != nil && *c.Limit < 0 {
+			return errors.New("cov: limit must not be negative")
+		}
+		if c.Collection, err = ParseCollection(c.Collection); err != nil {
+			return fmt.Errorf("cov: collection: %v", err)
+		}
+		if c.PanelSamples < 0 || c.PCSamples < 0 {
+			return errors.New("cov: counts must not be negative")
+		}
+	}
+
+	sort.Slice(cfg.Trends, func(i, j int) bool {
+		return cfg.Trends[i].Slot < cfg.Trends[j].Slot
+	})
+
+	for _, col := range Columns() {
+		dp.Set(col, "")
+	}
+
+	dp.COVTrend = "No"
+	if c := cfg.COV; c != nil {
+		dp.COVTrend = "Yes"
+		if c.Limit != nil {
+			dp.COVLimit = strconv.FormatFloat(*c.Limit, 'g', -1, 64)
+		}
+		dp.Collection = c.Collection
+		dp.PanelSamples = formatCount(c.PanelSamples)
+		dp.PCSamples = formatCount(c.PCSamples)
+	}
+
+	for _, t := range cfg.Trends {
+		columns := slotColumns(t.Slot)
+		dp.Set(columns[0], t.Interval)
+		dp.Set(columns[1], t.Collection)
+		dp.Set(columns[2], formatCount(t.PanelSamples))
+		dp.Set(columns[3], formatCount(t.PCDays))
+	}
+
+	return nil
+}
+
+// intervalUnits are the units of intervals written as a number and a unit,
+// the way older point lists do.
+var intervalUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// ParseInterval reads a sampling interval written as HH:MM:SS, HH:MM, a
+// number and a unit such as "15 min" or "1 hr", a Go duration such as 1h30m,
+// or a bare number of minutes.
+func ParseInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("interval is required")
+	}
+
+	var d time.Duration
+	switch {
+	case strings.Contains(s, ":"):
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("%q is not an interval", s)
+		}
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("%q is not an interval", s)
+			}
+			d += time.Duration(n) * units[i]
+		}
+
+	default:
+		if n, err := strconv.Atoi(s); err == nil {
+			d = time.Duration(n) * time.Minute
+			break
+		}
+		if i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' }); i > 0 {
+			n, err := strconv.ParseFloat(s[:i], 64)
+			unit, ok := intervalUnits[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s[i:])), ".")]
+			if err == nil && ok {
+				d = time.Duration(math.Round(n * float64(unit)))
+				break
+			}
+		}
+		var err error
+		if d, err = time.ParseDuration(strings.ToLower(s)); err != nil {
+			return 0, fmt.Errorf("%q is not an interval", s)
+		}
+	}
+
+	if d <= 0 || d%time.Second != 0 {
+		return 0, fmt.Errorf("%q is not a whole number of seconds", s)
+	}
+	return d, nil
+}
+
+// FormatInterval writes an interval as HH:MM:SS.
+func FormatInterval(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// ParseCollection reads a collection mode, "" when s is empty.
+func ParseCollection(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "auto", "automatic", "yes", "y", "1", "true":
+		return models.CollectionAuto, nil
+	case "manual":
+		return models.CollectionManual, nil
+	case "off", "none", "no", "n", "0", "false":
+		return models.CollectionOff, nil
+	}
+	return "", fmt.Errorf("%q is not Auto, Manual or Off", s)
+}
+
+// parseSwitch reads a yes/no column, no when it is empty.
+func parseSwitch(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "x", "on":
+		return true, nil
+	case "", "0", "false", "no", "n", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a yes/no value", s)
+}
+
+// parseCount reads a sample or day count, 0 when it is empty.
+func parseCount(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a positive whole number", s)
+	}
+	return n, nil
+}
+
+func formatCount(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,248 @@
+package trends
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "00:15:00", want: 15 * time.Minute},
+		{in: "1:30", want: 90 * time.Minute},
+		{in: "15", want: 15 * time.Minute},
+		{in: "15m", want: 15 * time.Minute},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "15 min", want: 15 * time.Minute},
+		{in: "15 Mins", want: 15 * time.Minute},
+		{in: "1 hr", want: time.Hour},
+		{in: "2hrs", want: 2 * time.Hour},
+		{in: "1.5 hours", want: 90 * time.Minute},
+		{in: "30 sec", want: 30 * time.Second},
+		{in: "1 day", want: 24 * time.Hour},
+		{in: "0.1 min", want: 6 * time.Second},
+		{in: "", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "1:2:3:4", wantErr: true},
+		{in: "15 fortnights", wantErr: true},
+		{in: "500ms", wantErr: true},
+		{in: "every 15", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterval(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseInterval(%q) error = %v, want error %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseInterval(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	limit := 0.5
+	tests := []struct {
+		name     string
+		dp       models.DataPoint
+		want     models.TrendConfig
+		wantErrs []string
+	}{
+		{
+			name: "none",
+			want: models.TrendConfig{Trends: []models.TrendDefinition{}},
+		},
+		{
+			name: "cov and two slots",
+			dp: models.DataPoint{
+				COVTrend: "Yes", COVLimit: "0.5", Collection: "auto", PanelSamples: "500",
+				TrendInterval1: "15 min", Collection1: "Manual", PanelSamples1: "672", PCDays1: "365",
+				TrendInterval3: "01:00:00",
+			},
+			want: models.TrendConfig{
+				COV: &models.COVTrend{Limit: &limit, Collection: models.CollectionAuto, PanelSamples: 500},
+				Trends: []models.TrendDefinition{
+					{Slot: 1, Interval: "00:15:00", IntervalSeconds: 900, Collection: models.CollectionManual, PanelSamples: 672, PCDays: 365},
+					{Slot: 3, Interval: "01:00:00", IntervalSeconds: 3600},
+				},
+			},
+		},
+		{
+			name: "unreadable values are left out",
+			dp:   models.DataPoint{COVTrend: "maybe", TrendInterval1: "every 15", PanelSamples1: "lots"},
+			want: models.TrendConfig{Trends: []models.TrendDefinition{{Slot: 1}}},
+			wantErrs: []string{
+				`COVTrend: "maybe" is not a yes/no value`,
+				`TrendInterval1: "every 15" is not an interval`,
+				`PanelSamples1: "lots" is not a positive whole number`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := Parse(tt.dp)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("Parse() errors = %q, want %q", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		dp   models.DataPoint
+		want []string
+	}{
+		{
+			name: "within limits",
+			dp:   models.DataPoint{TrendInterval1: "00:15:00", PanelSamples1: "10000", PCDays1: "3650"},
+		},
+		{
+			name: "beyond limits",
+			dp: models.DataPoint{
+				COVTrend: "Yes", PanelSamples: "10001",
+				TrendInterval2: "25:00:00", PanelSamples2: "20000", PCDays2: "4000",
+			},
+			want: []string{
+				"PanelSamples: 10001 samples, at most 10000 allowed",
+				"TrendInterval2: interval must be between 00:00:01 and 24:00:00",
+				"PanelSamples2: 20000 samples, at most 10000 allowed",
+				"PCDays2: 4000 days, at most 3650 allowed",
+			},
+		},
+		{
+			name: "unreadable",
+			dp:   models.DataPoint{TrendInterval1: "often"},
+			want: []string{`TrendInterval1: "often" is not an interval`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.dp, DefaultLimits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckChanged(t *testing.T) {
+	legacy := models.DataPoint{TrendInterval1: "every 15", PanelSamples1: "672"}
+
+	tests := []struct {
+		name string
+		dp   models.DataPoint
+		want []string
+	}{
+		{
+			name: "untouched legacy value",
+			dp:   models.DataPoint{TrendInterval1: "every 15", PanelSamples1: "700", Descriptor: "Supply"},
+		},
+		{
+			name: "changed to another unreadable value",
+			dp:   models.DataPoint{TrendInterval1: "every 20", PanelSamples1: "672"},
+			want: []string{`TrendInterval1: "every 20" is not an interval`},
+		},
+		{
+			name: "changed beyond the limits",
+			dp:   models.DataPoint{TrendInterval1: "every 15", PanelSamples1: "20000"},
+			want: []string{"PanelSamples1: 20000 samples, at most 10000 allowed"},
+		},
+		{
+			name: "cleared",
+			dp:   models.DataPoint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckChanged(legacy, tt.dp, DefaultLimits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckChanged() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without a stored point every value counts as changed
+	if got, want := CheckChanged(models.DataPoint{}, legacy, DefaultLimits), Check(legacy, DefaultLimits); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckChanged(zero) = %q, want %q", got, want)
+	}
+}
+
+func TestCheckImport(t *testing.T) {
+	dp := models.DataPoint{TrendInterval1: "every 15", PanelSamples1: "672", TrendInterval2: "15 min", PanelSamples2: "20000"}
+
+	errs, warnings := CheckImport(dp, DefaultLimits)
+	if want := []string{"PanelSamples2: 20000 samples, at most 10000 allowed"}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("CheckImport() errors = %q, want %q", errs, want)
+	}
+	if want := []string{`TrendInterval1: "every 15" is not an interval`}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("CheckImport() warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	limit := 1.0
+	cfg := models.TrendConfig{
+		COV: &models.COVTrend{Limit: &limit, Collection: "auto", PanelSamples: 100},
+		Trends: []models.TrendDefinition{
+			{Slot: 3, Interval: "1 hr", Collection: "off"},
+			{Interval: "15", PanelSamples: 672, PCDays: 365},
+		},
+	}
+	dp := models.DataPoint{TrendInterval4: "stale", Descriptor: "kept"}
+
+	if err := Apply(&dp, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.DataPoint{
+		Descriptor: "kept",
+		COVTrend:   "Yes", COVLimit: "1", Collection: models.CollectionAuto, PanelSamples: "100",
+		TrendInterval1: "00:15:00", PanelSamples1: "672", PCDays1: "365",
+		TrendInterval3: "01:00:00", Collection3: models.CollectionOff,
+	}
+	if !reflect.DeepEqual(dp, want) {
+		t.Errorf("Apply() wrote %+v, want %+v", dp, want)
+	}
+	if cfg.Trends[0].Slot != 1 || cfg.Trends[1].Slot != 3 {
+		t.Errorf("Apply() left the definitions in slots %d and %d, want 1 and 3", cfg.Trends[0].Slot, cfg.Trends[1].Slot)
+	}
+
+	// Reading back gives the normalized configuration
+	if got, errs := Parse(dp); errs != nil || !reflect.DeepEqual(got, cfg) {
+		t.Errorf("Parse(Apply()) = %+v, %q, want %+v", got, errs, cfg)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  models.TrendConfig
+	}{
+		{"too many", models.TrendConfig{Trends: make([]models.TrendDefinition, Slots+1)}},
+		{"slot out of range", models.TrendConfig{Trends: []models.TrendDefinition{{Slot: 5, Interval: "15"}}}},
+		{"slot used twice", models.TrendConfig{Trends: []models.TrendDefinition{{Slot: 1, Interval: "15"}, {Slot: 1, Interval: "30"}}}},
+		{"bad interval", models.TrendConfig{Trends: []models.TrendDefinition{{Interval: "often"}}}},
+		{"bad collection", models.TrendConfig{Trends: []models.TrendDefinition{{Interval: "15", Collection: "sometimes"}}}},
+		{"negative count", models.TrendConfig{Trends: []models.TrendDefinition{{Interval: "15", PCDays: -1}}}},
+		{"negative limit", models.TrendConfig{COV: &models.COVTrend{Limit: new(float64)}}},
+	}
+	*tests[len(tests)-1].cfg.COV.Limit = -1
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dp models.DataPoint
+			if err := Apply(&dp, &tt.cfg); err == nil {
+				t.Error("Apply() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package units
+
+import "github.com/pufington-pixie/haver/pkg/models"
+
+// Standard is the BACnet engineering units enumeration of ANSI/ASHRAE 135,
+// the contents the units table is seeded with.
+var Standard = []models.Unit{
+	{ID: 0, Name: "square-meters", Symbol: "m²"},
+	{ID: 1, Name: "square-feet", Symbol: "ft²"},
+	{ID: 2, Name: "milliamperes", Symbol: "mA"},
+	{ID: 3, Name: "amperes", Symbol: "A"},
+	{ID: 4, Name: "ohms", Symbol: "Ω"},
+	{ID: 5, Name: "volts", Symbol: "V"},
+	{ID: 6, Name: "kilovolts", Symbol: "kV"},
+	{ID: 7, Name: "megavolts"},
+	{ID: 8, Name: "volt-amperes", Symbol: "VA"},
+	{ID: 9, Name: "kilovolt-amperes", Symbol: "kVA"},
+	{ID: 10, Name: "megavolt-amperes"},
+	{ID: 11, Name: "volt-amperes-reactive", Symbol: "var"},
+	{ID: 12, Name: "kilovolt-amperes-reactive", Symbol: "kvar"},
+	{ID: 13, Name: "megavolt-amperes-reactive"},
+	{ID: 14, Name: "degrees-phase"},
+	{ID: 15, Name: "power-factor", Symbol: "PF"},
+	{ID: 16, Name: "joules", Symbol: "J"},
+	{ID: 17, Name: "kilojoules", Symbol: "kJ"},
+	{ID: 18, Name: "watt-hours", Symbol: "Wh"},
+	{ID: 19, Name: "kilowatt-hours", Symbol: "kWh"},
+	{ID: 20, Name: "btus", Symbol: "BTU"},
+	{ID: 21, Name: "therms", Symbol: "thm"},
+	{ID: 22, Name: "ton-hours"},
+	{ID: 23, Name: "joules-per-kilogram-dry-air", Symbol: "J/kg"},
+	{ID: 24, Name: "btus-per-pound-dry-air"},
+	{ID: 25, Name: "cycles-per-hour"},
+	{ID: 26, Name: "cycles-per-minute"},
+	{ID: 27, Name: "hertz", Symbol: "Hz"},
+	{ID: 28, Name: "grams-of-water-per-kilogram-dry-air", Symbol: "g/kg"},
+	{ID: 29, Name: "percent-relative-humidity", Symbol: "%RH"},
+	{ID: 30, Name: "millimeters", Symbol: "mm"},
+	{ID: 31, Name: "meters", Symbol: "m"},
+	{ID: 32, Name: "inches", Symbol: "in"},
+	{ID: 33, Name: "feet", Symbol: "ft"},
+	{ID: 34, Name: "watts-per-square-foot", Symbol: "W/ft²"},
+	{ID: 35, Name: "watts-per-square-meter", Symbol: "W/m²"},
+	{ID: 36, Name: "lumens", Symbol: "lm"},
+	{ID: 37, Name: "luxes", Symbol: "lx"},
+	{ID: 38, Name: "foot-candles", Symbol: "fc"},
+	{ID: 39, Name: "kilograms", Symbol: "kg"},
+	{ID: 40, Name: "pounds-mass", Symbol: "lb"},
+	{ID: 41, Name: "tons"},
+	{ID: 42, Name: "kilograms-per-second", Symbol: "kg/s"},
+	{ID: 43, Name: "kilograms-per-minute", Symbol: "kg/min"},
+	{ID: 44, Name: "kilograms-per-hour", Symbol: "kg/h"},
+	{ID: 45, Name: "pounds-mass-per-minute", Symbol: "lb/min"},
+	{ID: 46, Name: "pounds-mass-per-hour", Symbol: "lb/h"},
+	{ID: 47, Name: "watts", Symbol: "W"},
+	{ID: 48, Name: "kilowatts", Symbol: "kW"},
+	{ID: 49, Name: "megawatts"},
+	{ID: 50, Name: "btus-per-hour", Symbol: "BTU/h"},
+	{ID: 51, Name: "horsepower", Symbol: "hp"},
+	{ID: 52, Name: "tons-refrigeration", Symbol: "TR"},
+	{ID: 53, Name: "pascals", Symbol: "Pa"},
+	{ID: 54, Name: "kilopascals", Symbol: "kPa"},
+	{ID: 55, Name: "bars", Symbol: "bar"},
+	{ID: 56, Name: "pounds-force-per-square-inch", Symbol: "psi"},
+	{ID: 57, Name: "centimeters-of-water", Symbol: "cmH2O"},
+	{ID: 58, Name: "inches-of-water", Symbol: "inH2O"},
+	{ID: 59, Name: "millimeters-of-mercury", Symbol: "mmHg"},
+	{ID: 60, Name: "centimeters-of-mercury", Symbol: "cmHg"},
+	{ID: 61, Name: "inches-of-mercury", Symbol: "inHg"},
+	{ID: 62, Name: "degrees-celsius", Symbol: "°C"},
+	{ID: 63, Name: "degrees-kelvin", Symbol: "K"},
+	{ID: 64, Name: "degrees-fahrenheit", Symbol: "°F"},
+	{ID: 65, Name: "degree-days-celsius"},
+	{ID: 66, Name: "degree-days-fahrenheit"},
+	{ID: 67, Name: "years", Symbol: "yr"},
+	{ID: 68, Name: "months", Symbol: "mo"},
+	{ID: 69, Name: "weeks", Symbol: "wk"},
+	{ID: 70, Name: "days", Symbol: "d"},
+	{ID: 71, Name: "hours", Symbol: "h"},
+	{ID: 72, Name: "minutes", Symbol: "min"},
+	{ID: 73, Name: "seconds", Symbol: "s"},
+	{ID: 74, Name: "meters-per-second", Symbol: "m/s"},
+	{ID: 75, Name: "kilometers-per-hour", Symbol: "km/h"},
+	{ID: 76, Name: "feet-per-second", Symbol: "ft/s"},
+	{ID: 77, Name: "feet-per-minute", Symbol: "ft/min"},
+	{ID: 78, Name: "miles-per-hour", Symbol: "mph"},
+	{ID: 79, Name: "cubic-feet", Symbol: "ft³"},
+	{ID: 80, Name: "cubic-meters", Symbol: "m³"},
+	{ID: 81, Name: "imperial-gallons"},
+	{ID: 82, Name: "liters", Symbol: "L"},
+	{ID: 83, Name: "us-gallons", Symbol: "gal"},
+	{ID: 84, Name: "cubic-feet-per-minute", Symbol: "cfm"},
+	{ID: 85, Name: "cubic-meters-per-second", Symbol: "m³/s"},
+	{ID: 86, Name: "imperial-gallons-per-minute"},
+	{ID: 87, Name: "liters-per-second", Symbol: "L/s"},
+	{ID: 88, Name: "liters-per-minute", Symbol: "L/min"},
+	{ID: 89, Name: "us-gallons-per-minute", Symbol: "gpm"},
+	{ID: 90, Name: "degrees-angular"},
+	{ID: 91, Name: "degrees-celsius-per-hour", Symbol: "°C/h"},
+	{ID: 92, Name: "degrees-celsius-per-minute", Symbol: "°C/min"},
+	{ID: 93, Name: "degrees-fahrenheit-per-hour", Symbol: "°F/h"},
+	{ID: 94, Name: "degrees-fahrenheit-per-minute", Symbol: "°F/min"},
+	{ID: 95, Name: "no-units"},
+	{ID: 96, Name: "parts-per-million", Symbol: "ppm"},
+	{ID: 97, Name: "parts-per-billion", Symbol: "ppb"},
+	{ID: 98, Name: "percent", Symbol: "%"},
+	{ID: 99, Name: "percent-per-second", Symbol: "%/s"},
+	{ID: 100, Name: "per-minute", Symbol: "/min"},
+	{ID: 101, Name: "per-second", Symbol: "/s"},
+	{ID: 102, Name: "psi-per-degree-fahrenheit"},
+	{ID: 103, Name: "radians", Symbol: "rad"},
+	{ID: 104, Name: "revolutions-per-minute", Symbol: "rpm"},
+	{ID: 105, Name: "currency1"},
+	{ID: 106, Name: "currency2"},
+	{ID: 107, Name: "currency3"},
+	{ID: 108, Name: "currency4"},
+	{ID: 109, Name: "currency5"},
+	{ID: 110, Name: "currency6"},
+	{ID: 111, Name: "currency7"},
+	{ID: 112, Name: "currency8"},
+	{ID: 113, Name: "currency9"},
+	{ID: 114, Name: "currency10"},
+	{ID: 115, Name: "square-inches", Symbol: "in²"},
+	{ID: 116, Name: "square-centimeters", Symbol: "cm²"},
+	{ID: 117, Name: "btus-per-pound", Symbol: "BTU/lb"},
+	{ID: 118, Name: "centimeters", Symbol: "cm"},
+	{ID: 119, Name: "pounds-mass-per-second", Symbol: "lb/s"},
+	{ID: 120, Name: "delta-degrees-fahrenheit", Symbol: "Δ°F"},
+	{ID: 121, Name: "delta-degrees-kelvin", Symbol: "ΔK"},
+	{ID: 122, Name: "kilohms", Symbol: "kΩ"},
+	{ID: 123, Name: "megohms"},
+	{ID: 124, Name: "millivolts", Symbol: "mV"},
+	{ID: 125, Name: "kilojoules-per-kilogram", Symbol: "kJ/kg"},
+	{ID: 126, Name: "megajoules", Symbol: "MJ"},
+	{ID: 127, Name: "joules-per-degree-kelvin", Symbol: "J/K"},
+	{ID: 128, Name: "joules-per-kilogram-degree-kelvin", Symbol: "J/(kg·K)"},
+	{ID: 129, Name: "kilohertz", Symbol: "kHz"},
+	{ID: 130, Name: "megahertz", Symbol: "MHz"},
+	{ID: 131, Name: "per-hour", Symbol: "/h"},
+	{ID: 132, Name: "milliwatts", Symbol: "mW"},
+	{ID: 133, Name: "hectopascals", Symbol: "hPa"},
+	{ID: 134, Name: "millibars", Symbol: "mbar"},
+	{ID: 135, Name: "cubic-meters-per-hour", Symbol: "m³/h"},
+	{ID: 136, Name: "liters-per-hour", Symbol: "L/h"},
+	{ID: 137, Name: "kilowatt-hours-per-square-meter", Symbol: "kWh/m²"},
+	{ID: 138, Name: "kilowatt-hours-per-square-foot", Symbol: "kWh/ft²"},
+	{ID: 139, Name: "megajoules-per-square-meter", Symbol: "MJ/m²"},
+	{ID: 140, Name: "megajoules-per-square-foot", Symbol: "MJ/ft²"},
+	{ID: 141, Name: "watts-per-square-meter-degree-kelvin", Symbol: "W/(m²·K)"},
+	{ID: 142, Name: "cubic-feet-per-second", Symbol: "ft³/s"},
+	{ID: 143, Name: "percent-obscuration-per-foot", Symbol: "%/ft"},
+	{ID: 144, Name: "percent-obscuration-per-meter", Symbol: "%/m"},
+	{ID: 145, Name: "milliohms", Symbol: "mΩ"},
+	{ID: 146, Name: "megawatt-hours", Symbol: "MWh"},
+	{ID: 147, Name: "kilo-btus", Symbol: "kBTU"},
+	{ID: 148, Name: "mega-btus", Symbol: "MMBTU"},
+	{ID: 149, Name: "kilojoules-per-kilogram-dry-air"},
+	{ID: 150, Name: "megajoules-per-kilogram-dry-air"},
+	{ID: 151, Name: "kilojoules-per-degree-kelvin", Symbol: "kJ/K"},
+	{ID: 152, Name: "megajoules-per-degree-kelvin", Symbol: "MJ/K"},
+	{ID: 153, Name: "newton", Symbol: "N"},
+	{ID: 154, Name: "grams-per-second", Symbol: "g/s"},
+	{ID: 155, Name: "grams-per-minute", Symbol: "g/min"},
+	{ID: 156, Name: "tons-per-hour", Symbol: "t/h"},
+	{ID: 157, Name: "kilo-btus-per-hour", Symbol: "MBH"},
+	{ID: 158, Name: "hundredths-seconds"},
+	{ID: 159, Name: "milliseconds", Symbol: "ms"},
+	{ID: 160, Name: "newton-meters", Symbol: "N·m"},
+	{ID: 161, Name: "millimeters-per-second", Symbol: "mm/s"},
+	{ID: 162, Name: "millimeters-per-minute", Symbol: "mm/min"},
+	{ID: 163, Name: "meters-per-minute", Symbol: "m/min"},
+	{ID: 164, Name: "meters-per-hour", Symbol: "m/h"},
+	{ID: 165, Name: "cubic-meters-per-minute", Symbol: "m³/min"},
+	{ID: 166, Name: "meters-per-second-per-second", Symbol: "m/s²"},
+	{ID: 167, Name: "amperes-per-meter", Symbol: "A/m"},
+	{ID: 168, Name: "amperes-per-square-meter", Symbol: "A/m²"},
+	{ID: 169, Name: "ampere-square-meters", Symbol: "A·m²"},
+	{ID: 170, Name: "farads", Symbol: "F"},
+	{ID: 171, Name: "henrys", Symbol: "H"},
+	{ID: 172, Name: "ohm-meters", Symbol: "Ω·m"},
+	{ID: 173, Name: "siemens", Symbol: "S"},
+	{ID: 174, Name: "siemens-per-meter", Symbol: "S/m"},
+	{ID: 175, Name: "teslas", Symbol: "T"},
+	{ID: 176, Name: "volts-per-degree-kelvin", Symbol: "V/K"},
+	{ID: 177, Name: "volts-per-meter", Symbol: "V/m"},
+	{ID: 178, Name: "webers", Symbol: "Wb"},
+	{ID: 179, Name: "candelas", Symbol: "cd"},
+	{ID: 180, Name: "candelas-per-square-meter", Symbol: "cd/m²"},
+	{ID: 181, Name: "degrees-kelvin-per-hour", Symbol: "K/h"},
+	{ID: 182, Name: "degrees-kelvin-per-minute", Symbol: "K/min"},
+	{ID: 183, Name: "joule-seconds", Symbol: "J·s"},
+	{ID: 184, Name: "radians-per-second", Symbol: "rad/s"},
+	{ID: 185, Name: "square-meters-per-newton", Symbol: "m²/N"},
+	{ID: 186, Name: "kilograms-per-cubic-meter", Symbol: "kg/m³"},
+	{ID: 187, Name: "newton-seconds", Symbol: "N·s"},
+	{ID: 188, Name: "newtons-per-meter", Symbol: "N/m"},
+	{ID: 189, Name: "watts-per-meter-per-degree-kelvin", Symbol: "W/(m·K)"},
+	{ID: 190, Name: "micro-siemens", Symbol: "µS"},
+	{ID: 191, Name: "cubic-feet-per-hour", Symbol: "ft³/h"},
+	{ID: 192, Name: "us-gallons-per-hour", Symbol: "gph"},
+	{ID: 193, Name: "kilometers", Symbol: "km"},
+	{ID: 194, Name: "micrometers", Symbol: "µm"},
+	{ID: 195, Name: "grams", Symbol: "g"},
+	{ID: 196, Name: "milligrams", Symbol: "mg"},
+	{ID: 197, Name: "milliliters", Symbol: "mL"},
+	{ID: 198, Name: "milliliters-per-second", Symbol: "mL/s"},
+	{ID: 199, Name: "decibels", Symbol: "dB"},
+	{ID: 200, Name: "decibels-millivolt", Symbol: "dBmV"},
+	{ID: 201, Name: "decibels-volt", Symbol: "dBV"},
+	{ID: 202, Name: "millisiemens", Symbol: "mS"},
+	{ID: 203, Name: "watt-hours-reactive", Symbol: "varh"},
+	{ID: 204, Name: "kilowatt-hours-reactive", Symbol: "kvarh"},
+	{ID: 205, Name: "megawatt-hours-reactive"},
+	{ID: 206, Name: "millimeters-of-water", Symbol: "mmH2O"},
+	{ID: 207, Name: "per-mille", Symbol: "‰"},
+	{ID: 208, Name: "grams-per-gram", Symbol: "g/g"},
+	{ID: 209, Name: "kilograms-per-kilogram", Symbol: "kg/kg"},
+	{ID: 210, Name: "grams-per-kilogram"},
+	{ID: 211, Name: "milligrams-per-gram", Symbol: "mg/g"},
+	{ID: 212, Name: "milligrams-per-kilogram", Symbol: "mg/kg"},
+	{ID: 213, Name: "grams-per-milliliter", Symbol: "g/mL"},
+	{ID: 214, Name: "grams-per-liter", Symbol: "g/L"},
+	{ID: 215, Name: "milligrams-per-liter", Symbol: "mg/L"},
+	{ID: 216, Name: "micrograms-per-liter", Symbol: "µg/L"},
+	{ID: 217, Name: "grams-per-cubic-meter", Symbol: "g/m³"},
+	{ID: 218, Name: "milligrams-per-cubic-meter", Symbol: "mg/m³"},
+	{ID: 219, Name: "micrograms-per-cubic-meter", Symbol: "µg/m³"},
+	{ID: 220, Name: "nanograms-per-cubic-meter", Symbol: "ng/m³"},
+	{ID: 221, Name: "grams-per-cubic-centimeter", Symbol: "g/cm³"},
+	{ID: 222, Name: "becquerels", Symbol: "Bq"},
+	{ID: 223, Name: "kilobecquerels", Symbol: "kBq"},
+	{ID: 224, Name: "megabecquerels", Symbol: "MBq"},
+	{ID: 225, Name: "gray", Symbol: "Gy"},
+	{ID: 226, Name: "milligray", Symbol: "mGy"},
+	{ID: 227, Name: "microgray", Symbol: "µGy"},
+	{ID: 228, Name: "sieverts", Symbol: "Sv"},
+	{ID: 229, Name: "millisieverts", Symbol: "mSv"},
+	{ID: 230, Name: "microsieverts", Symbol: "µSv"},
+	{ID: 231, Name: "microsieverts-per-hour", Symbol: "µSv/h"},
+	{ID: 232, Name: "decibels-a", Symbol: "dBA"},
+	{ID: 233, Name: "nephelometric-turbidity-unit", Symbol: "NTU"},
+	{ID: 234, Name: "ph", Symbol: "pH"},
+	{ID: 235, Name: "grams-per-square-meter", Symbol: "g/m²"},
+	{ID: 236, Name: "minutes-per-degree-kelvin", Symbol: "min/K"},
+	{ID: 237, Name: "ohm-meter-squared-per-meter"},
+	{ID: 238, Name: "ampere-seconds", Symbol: "A·s"},
+	{ID: 239, Name: "volt-ampere-hours", Symbol: "VAh"},
+	{ID: 240, Name: "kilovolt-ampere-hours", Symbol: "kVAh"},
+	{ID: 241, Name: "megavolt-ampere-hours"},
+	{ID: 242, Name: "volt-ampere-hours-reactive"},
+	{ID: 243, Name: "kilovolt-ampere-hours-reactive"},
+	{ID: 244, Name: "megavolt-ampere-hours-reactive"},
+	{ID: 245, Name: "volt-square-hours", Symbol: "V²h"},
+	{ID: 246, Name: "ampere-square-hours", Symbol: "A²h"},
+	{ID: 247, Name: "joule-per-hours", Symbol: "J/h"},
+	{ID: 248, Name: "cubic-feet-per-day", Symbol: "ft³/d"},
+	{ID: 249, Name: "cubic-meters-per-day", Symbol: "m³/d"},
+	{ID: 250, Name: "watt-hours-per-cubic-meter", Symbol: "Wh/m³"},
+	{ID: 251, Name: "joules-per-cubic-meter", Symbol: "J/m³"},
+	{ID: 252, Name: "mole-percent", Symbol: "mol%"},
+	{ID: 253, Name: "pascal-seconds", Symbol: "Pa·s"},
+	{ID: 254, Name: "million-standard-cubic-feet-per-minute", Symbol: "MMSCFM"},
+}
+
+// DefaultAliases are the spellings seen in point schedules that the BACnet
+// names and symbols do not cover, or that must win over them, such as "tons"
+// meaning refrigeration rather than mass. The unit_aliases table is seeded
+// with them.
+var DefaultAliases = map[string]int{
+	"c":            62,
+	"celsius":      62,
+	"centigrade":   62,
+	"f":            64,
+	"fahrenheit":   64,
+	"kelvin":       63,
+	"delta deg f":  120,
+	"delta f":      120,
+	"delta k":      121,
+	"rh":           29,
+	"pct rh":       29,
+	"pct":          98,
+	"percent open": 98,
+	"ft3/min":      84,
+	"scfm":         84,
+	"us gpm":       89,
+	"lps":          87,
+	"cmh":          135,
+	"psig":         56,
+	"in wc":        58,
+	"in wg":        58,
+	"iwc":          58,
+	"kw hr":        19,
+	"volt":         5,
+	"vac":          5,
+	"vdc":          5,
+	"amp":          3,
+	"amps":         3,
+	"btuh":         50,
+	"btu/hr":       50,
+	"ton":          52,
+	"tons":         52,
+	"sec":          73,
+	"secs":         73,
+	"mins":         72,
+	"hr":           71,
+	"hrs":          71,
+	"day":          70,
+	"fpm":          77,
+	"rev/min":      104,
+	"lux":          37,
+	"sq ft":        1,
+	"sq m":         0,
+	"none":         95,
+}
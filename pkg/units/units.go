@@ -0,0 +1,93 @@
+// Package units maps the free-text engineering units of point lists onto the
+// units catalogue, which holds the BACnet engineering units enumeration.
+//
+// A spelling matches a unit through its BACnet name, its symbol or an alias.
+// Spellings are compared normalized, so "degF", "°F" and "DEG F" are all the
+// same spelling.
+package units
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pufington-pixie/haver/pkg/models"
+)
+
+// Catalogue looks units up by spelling.
+type Catalogue struct {
+	units map[int]models.Unit
+	byKey map[string]int
+}
+
+// New returns a catalogue of units. Aliases take precedence over names and
+// names over symbols; among symbols the lowest unit wins.
+func New(units []models.Unit, aliases []models.UnitAlias) *Catalogue {
+	c := &Catalogue{
+		units: make(map[int]models.Unit, len(units)),
+		byKey: make(map[string]int, 2*len(units)+len(aliases)),
+	}
+
+	add := func(spelling string, id int) {
+		key := Normalize(spelling)
+		if _, ok := c.byKey[key]; key != "" && !ok {
+			c.byKey[key] = id
+		}
+	}
+
+	for _, u := range units {
+		c.units[u.ID] = u
+	}
+	for _, a := range aliases {
+		if _, ok := c.units[a.UnitID]; ok {
+			add(a.Alias, a.UnitID)
+		}
+	}
+	for _, u := range units {
+		add(u.Name, u.ID)
+	}
+	for _, u := range units {
+		add(u.Symbol, u.ID)
+	}
+
+	return c
+}
+
+// Lookup returns the unit a spelling stands for.
+func (c *Catalogue) Lookup(spelling string) (models.Unit, bool) {
+	id, ok := c.byKey[Normalize(spelling)]
+	if !ok {
+		return models.Unit{}, false
+	}
+	return c.units[id], true
+}
+
+// Get returns the unit with the given BACnet value.
+func (c *Catalogue) Get(id int) (models.Unit, bool) {
+	u, ok := c.units[id]
+	return u, ok
+}
+
+// Normalize returns the form spellings are compared in: lower case, degree
+// signs spelled "deg", superscripts as digits and without spaces, dashes,
+// underscores, dots or quotes.
+func Normalize(spelling string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(spelling)) {
+		switch r {
+		case '°', 'º':
+			b.WriteString("deg")
+		case '²':
+			b.WriteByte('2')
+		case '³':
+			b.WriteByte('3')
+		case 'µ', 'μ':
+			b.WriteByte('u')
+		case '-', '_', '.', '\'', '"':
+		default:
+			if !unicode.IsSpace(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package utils
+
+import "strings"
+
+// Counter counts the values of a column, remembering the order they were
+// first seen in to break ties. The zero value is ready to use.
+type Counter struct {
+	order  []string
+	counts map[string]int
+}
+
+// Add counts value, ignoring surrounding space and empty values.
+func (c *Counter) Add(value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	if c.counts[value] == 0 {
+		c.order = append(c.order, value)
+	}
+	c.counts[value]++
+}
+
+// Top returns the most common value, "" if there are none.
+func (c *Counter) Top() string {
+	var best string
+	for _, v := range c.order {
+		if c.counts[v] > c.counts[best] {
+			best = v
+		}
+	}
+	return best
+}